@@ -25,6 +25,8 @@ func RegisterBuiltinTypes(reg Registry) error {
 		&LoginChallenge{},
 		&LoginResponse{},
 		&LoginCheckpoint{},
+		&Handshake{},
+		&SessionResume{},
 		&PinRequest{},
 	}
 
@@ -91,6 +93,40 @@ func (v *Tags) New() tag.Value {
 	return &Tags{}
 }
 
+// FindSubTags returns the first sub Tags whose ID has the given ContentType, or nil if not found.
+func (v *Tags) FindSubTags(contentType string) *Tags {
+	if v == nil {
+		return nil
+	}
+	for _, sub := range v.SubTags {
+		if sub != nil && sub.ID != nil && sub.ID.ContentType == contentType {
+			return sub
+		}
+	}
+	return nil
+}
+
+// WithoutSubTags returns a shallow copy of v excluding sub Tags whose ID has the given ContentType.
+// If nothing remains, nil is returned so that an empty Tags and a nil Tags marshal identically.
+func (v *Tags) WithoutSubTags(contentType string) *Tags {
+	if v == nil {
+		return nil
+	}
+	out := &Tags{
+		ID: v.ID,
+	}
+	for _, sub := range v.SubTags {
+		if sub != nil && sub.ID != nil && sub.ID.ContentType == contentType {
+			continue
+		}
+		out.SubTags = append(out.SubTags, sub)
+	}
+	if out.ID == nil && len(out.SubTags) == 0 {
+		return nil
+	}
+	return out
+}
+
 func (v *Tag) SetID(tagID tag.ID) {
 	v.ID_0 = int64(tagID[0])
 	v.ID_1 = tagID[1]
@@ -206,6 +242,30 @@ func (v *LoginCheckpoint) New() tag.Value {
 	return &LoginCheckpoint{}
 }
 
+func (v *Handshake) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *Handshake) TagSpec() tag.Spec {
+	return AttrSpec.With("Handshake")
+}
+
+func (v *Handshake) New() tag.Value {
+	return &Handshake{}
+}
+
+func (v *SessionResume) MarshalToStore(in []byte) (out []byte, err error) {
+	return MarshalPbToStore(v, in)
+}
+
+func (v *SessionResume) TagSpec() tag.Spec {
+	return AttrSpec.With("SessionResume")
+}
+
+func (v *SessionResume) New() tag.Value {
+	return &SessionResume{}
+}
+
 func (v *PinRequest) MarshalToStore(in []byte) (out []byte, err error) {
 	return MarshalPbToStore(v, in)
 }
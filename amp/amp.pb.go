@@ -45,7 +45,9 @@ const (
 	//          03:04 -- Const_TxHeader_Version
 	//          04:08 -- TxMsg body size: header + serialized TxOp(s)
 	//          08:12 -- TxMsg.DataStore size
-	//          12:16 -- Reserved
+	//          12:13 -- TxCodec of TxMsg.DataStore
+	//          13:14 -- TxCodec of the TxMsg body (TxEnvelope and TxOps)
+	//          14:16 -- Reserved
 	Const_TxHeader_Size Const = 16
 	// Version of the TxHeader -- first byte
 	Const_TxHeader_Version Const = 51
@@ -165,6 +167,35 @@ func (TxField) EnumDescriptor() ([]byte, []int) {
 	return fileDescriptor_7e479d288f92766f, []int{2}
 }
 
+// TxCodec specifies how a section of a serialized TxMsg is compressed.
+// A compressed section is a uvarint of its uncompressed size followed by the codec's output.
+type TxCodec int32
+
+const (
+	TxCodec_None    TxCodec = 0
+	TxCodec_Deflate TxCodec = 1
+	TxCodec_Zlib    TxCodec = 2
+	TxCodec_Gzip    TxCodec = 3
+)
+
+var TxCodec_name = map[int32]string{
+	0: "TxCodec_None",
+	1: "TxCodec_Deflate",
+	2: "TxCodec_Zlib",
+	3: "TxCodec_Gzip",
+}
+
+var TxCodec_value = map[string]int32{
+	"TxCodec_None":    0,
+	"TxCodec_Deflate": 1,
+	"TxCodec_Zlib":    2,
+	"TxCodec_Gzip":    3,
+}
+
+func (TxCodec) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_7e479d288f92766f, []int{3}
+}
+
 type SelectOp int32
 
 const (
@@ -186,7 +217,7 @@ var SelectOp_value = map[string]int32{
 }
 
 func (SelectOp) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{3}
+	return fileDescriptor_7e479d288f92766f, []int{4}
 }
 
 // OpStatus allows a sender to express the status of a request.
@@ -224,7 +255,7 @@ var OpStatus_value = map[string]int32{
 }
 
 func (OpStatus) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{4}
+	return fileDescriptor_7e479d288f92766f, []int{5}
 }
 
 type StateSync int32
@@ -254,7 +285,7 @@ var StateSync_value = map[string]int32{
 }
 
 func (StateSync) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{5}
+	return fileDescriptor_7e479d288f92766f, []int{6}
 }
 
 type Enable int32
@@ -281,7 +312,7 @@ var Enable_value = map[string]int32{
 }
 
 func (Enable) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{6}
+	return fileDescriptor_7e479d288f92766f, []int{7}
 }
 
 type UrlScheme int32
@@ -317,7 +348,7 @@ var UrlScheme_value = map[string]int32{
 }
 
 func (UrlScheme) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{7}
+	return fileDescriptor_7e479d288f92766f, []int{8}
 }
 
 type Metric int32
@@ -353,7 +384,7 @@ var Metric_value = map[string]int32{
 }
 
 func (Metric) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{8}
+	return fileDescriptor_7e479d288f92766f, []int{9}
 }
 
 // CryptoKitID identifies an encryption suite that implements ski.CryptoKit
@@ -364,6 +395,7 @@ const (
 	CryptoKit_SecretBox_NaCl  CryptoKitID = 100
 	CryptoKit_AsymMsg_NaCl    CryptoKitID = 101
 	CryptoKit_Signing_NaCl    CryptoKitID = 102
+	CryptoKit_AES_GCM         CryptoKitID = 200
 	CryptoKit_Signing_ED25519 CryptoKitID = 202
 )
 
@@ -372,6 +404,7 @@ var CryptoKitID_name = map[int32]string{
 	100: "CryptoKit_SecretBox_NaCl",
 	101: "CryptoKit_AsymMsg_NaCl",
 	102: "CryptoKit_Signing_NaCl",
+	200: "CryptoKit_AES_GCM",
 	202: "CryptoKit_Signing_ED25519",
 }
 
@@ -380,11 +413,12 @@ var CryptoKitID_value = map[string]int32{
 	"CryptoKit_SecretBox_NaCl":  100,
 	"CryptoKit_AsymMsg_NaCl":    101,
 	"CryptoKit_Signing_NaCl":    102,
+	"CryptoKit_AES_GCM":         200,
 	"CryptoKit_Signing_ED25519": 202,
 }
 
 func (CryptoKitID) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{9}
+	return fileDescriptor_7e479d288f92766f, []int{10}
 }
 
 // ErrCode expresses status and error codes.
@@ -403,6 +437,7 @@ const (
 	ErrCode_LoginFailed             ErrCode = 5008
 	ErrCode_SessionExpired          ErrCode = 5009
 	ErrCode_NotReady                ErrCode = 5010
+	ErrCode_DecryptFailed           ErrCode = 5011
 	ErrCode_RequestNotFound         ErrCode = 5020
 	ErrCode_RequestClosed           ErrCode = 5021
 	ErrCode_BadRequest              ErrCode = 5022
@@ -424,6 +459,10 @@ const (
 	ErrCode_ProviderErr             ErrCode = 5059
 	ErrCode_ViolatesAppendOnly      ErrCode = 5100
 	ErrCode_InsufficientPermissions ErrCode = 5101
+	ErrCode_PinLimitReached         ErrCode = 5110
+	ErrCode_TxRateExceeded          ErrCode = 5111
+	ErrCode_ByteRateExceeded        ErrCode = 5112
+	ErrCode_CommitTooLarge          ErrCode = 5113
 )
 
 var ErrCode_name = map[int32]string{
@@ -439,6 +478,7 @@ var ErrCode_name = map[int32]string{
 	5008: "ErrCode_LoginFailed",
 	5009: "ErrCode_SessionExpired",
 	5010: "ErrCode_NotReady",
+	5011: "ErrCode_DecryptFailed",
 	5020: "ErrCode_RequestNotFound",
 	5021: "ErrCode_RequestClosed",
 	5022: "ErrCode_BadRequest",
@@ -460,6 +500,10 @@ var ErrCode_name = map[int32]string{
 	5059: "ErrCode_ProviderErr",
 	5100: "ErrCode_ViolatesAppendOnly",
 	5101: "ErrCode_InsufficientPermissions",
+	5110: "ErrCode_PinLimitReached",
+	5111: "ErrCode_TxRateExceeded",
+	5112: "ErrCode_ByteRateExceeded",
+	5113: "ErrCode_CommitTooLarge",
 }
 
 var ErrCode_value = map[string]int32{
@@ -475,6 +519,7 @@ var ErrCode_value = map[string]int32{
 	"ErrCode_LoginFailed":             5008,
 	"ErrCode_SessionExpired":          5009,
 	"ErrCode_NotReady":                5010,
+	"ErrCode_DecryptFailed":           5011,
 	"ErrCode_RequestNotFound":         5020,
 	"ErrCode_RequestClosed":           5021,
 	"ErrCode_BadRequest":              5022,
@@ -496,10 +541,14 @@ var ErrCode_value = map[string]int32{
 	"ErrCode_ProviderErr":             5059,
 	"ErrCode_ViolatesAppendOnly":      5100,
 	"ErrCode_InsufficientPermissions": 5101,
+	"ErrCode_PinLimitReached":         5110,
+	"ErrCode_TxRateExceeded":          5111,
+	"ErrCode_ByteRateExceeded":        5112,
+	"ErrCode_CommitTooLarge":          5113,
 }
 
 func (ErrCode) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{10}
+	return fileDescriptor_7e479d288f92766f, []int{11}
 }
 
 type LogLevel int32
@@ -523,7 +572,7 @@ var LogLevel_value = map[string]int32{
 }
 
 func (LogLevel) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{11}
+	return fileDescriptor_7e479d288f92766f, []int{12}
 }
 
 // TxEnvelope contains information for a TxMsg
@@ -920,6 +969,182 @@ func (m *LoginCheckpoint) GetURI() string {
 	return ""
 }
 
+// SessionResume -- client -> host: resumes a suspended session over a new transport.
+// The host replies with the session's Login or, if the session can't be resumed, an ErrCode_SessionExpired error.
+type SessionResume struct {
+	Checkpoint *LoginCheckpoint `protobuf:"bytes,1,opt,name=Checkpoint,proto3" json:"Checkpoint,omitempty"`
+	Pins       []*ResumePin     `protobuf:"bytes,2,rep,name=Pins,proto3" json:"Pins,omitempty"`
+}
+
+func (m *SessionResume) Reset()      { *m = SessionResume{} }
+func (*SessionResume) ProtoMessage() {}
+func (*SessionResume) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7e479d288f92766f, []int{5}
+}
+func (m *SessionResume) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *SessionResume) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_SessionResume.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *SessionResume) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionResume.Merge(m, src)
+}
+func (m *SessionResume) XXX_Size() int {
+	return m.Size()
+}
+func (m *SessionResume) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionResume.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionResume proto.InternalMessageInfo
+
+func (m *SessionResume) GetCheckpoint() *LoginCheckpoint {
+	if m != nil {
+		return m.Checkpoint
+	}
+	return nil
+}
+
+func (m *SessionResume) GetPins() []*ResumePin {
+	if m != nil {
+		return m.Pins
+	}
+	return nil
+}
+
+// ResumePin names an open request and the latest OpStatus_Synced tx the client received for it.
+// If the host still has every tx it sent since that tx, it re-sends them; otherwise it re-sends the request's state in full.
+type ResumePin struct {
+	RequestID    *Tag `protobuf:"bytes,1,opt,name=RequestID,proto3" json:"RequestID,omitempty"`
+	LastSyncedTx *Tag `protobuf:"bytes,2,opt,name=LastSyncedTx,proto3" json:"LastSyncedTx,omitempty"`
+}
+
+func (m *ResumePin) Reset()      { *m = ResumePin{} }
+func (*ResumePin) ProtoMessage() {}
+func (*ResumePin) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7e479d288f92766f, []int{6}
+}
+func (m *ResumePin) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ResumePin) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ResumePin.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ResumePin) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ResumePin.Merge(m, src)
+}
+func (m *ResumePin) XXX_Size() int {
+	return m.Size()
+}
+func (m *ResumePin) XXX_DiscardUnknown() {
+	xxx_messageInfo_ResumePin.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ResumePin proto.InternalMessageInfo
+
+func (m *ResumePin) GetRequestID() *Tag {
+	if m != nil {
+		return m.RequestID
+	}
+	return nil
+}
+
+func (m *ResumePin) GetLastSyncedTx() *Tag {
+	if m != nil {
+		return m.LastSyncedTx
+	}
+	return nil
+}
+
+// Handshake is sent by each peer as a MetaNodeID attr at session start, advertising the wire features it supports.
+// Each peer then writes the highest TxHeader version both support and only uses TxCodecs and signing kits both support.
+// Until then, a peer writes its lowest TxHeader version so that a peer supporting only newer versions can still read its Handshake.
+type Handshake struct {
+	MinHeaderVersion uint32        `protobuf:"varint,1,opt,name=MinHeaderVersion,proto3" json:"MinHeaderVersion,omitempty"`
+	MaxHeaderVersion uint32        `protobuf:"varint,2,opt,name=MaxHeaderVersion,proto3" json:"MaxHeaderVersion,omitempty"`
+	Codecs           []TxCodec     `protobuf:"varint,3,rep,packed,name=Codecs,proto3,enum=amp.TxCodec" json:"Codecs,omitempty"`
+	SigningKits      []CryptoKitID `protobuf:"varint,4,rep,packed,name=SigningKits,proto3,enum=amp.CryptoKitID" json:"SigningKits,omitempty"`
+}
+
+func (m *Handshake) Reset()      { *m = Handshake{} }
+func (*Handshake) ProtoMessage() {}
+func (*Handshake) Descriptor() ([]byte, []int) {
+	return fileDescriptor_7e479d288f92766f, []int{7}
+}
+func (m *Handshake) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *Handshake) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_Handshake.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *Handshake) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Handshake.Merge(m, src)
+}
+func (m *Handshake) XXX_Size() int {
+	return m.Size()
+}
+func (m *Handshake) XXX_DiscardUnknown() {
+	xxx_messageInfo_Handshake.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Handshake proto.InternalMessageInfo
+
+func (m *Handshake) GetMinHeaderVersion() uint32 {
+	if m != nil {
+		return m.MinHeaderVersion
+	}
+	return 0
+}
+
+func (m *Handshake) GetMaxHeaderVersion() uint32 {
+	if m != nil {
+		return m.MaxHeaderVersion
+	}
+	return 0
+}
+
+func (m *Handshake) GetCodecs() []TxCodec {
+	if m != nil {
+		return m.Codecs
+	}
+	return nil
+}
+
+func (m *Handshake) GetSigningKits() []CryptoKitID {
+	if m != nil {
+		return m.SigningKits
+	}
+	return nil
+}
+
 // PinRequest is a client request to "pin" a cell, meaning selected attrs and child cells will be pushed to the client.
 type PinRequest struct {
 	// Specifies a target URL or tag / cell ID to be pinned with the above available mint templates available.
@@ -935,7 +1160,7 @@ type PinRequest struct {
 func (m *PinRequest) Reset()      { *m = PinRequest{} }
 func (*PinRequest) ProtoMessage() {}
 func (*PinRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{5}
+	return fileDescriptor_7e479d288f92766f, []int{8}
 }
 func (m *PinRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1000,7 +1225,7 @@ type LaunchURL struct {
 func (m *LaunchURL) Reset()      { *m = LaunchURL{} }
 func (*LaunchURL) ProtoMessage() {}
 func (*LaunchURL) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{6}
+	return fileDescriptor_7e479d288f92766f, []int{9}
 }
 func (m *LaunchURL) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1058,7 +1283,7 @@ type Tag struct {
 func (m *Tag) Reset()      { *m = Tag{} }
 func (*Tag) ProtoMessage() {}
 func (*Tag) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{7}
+	return fileDescriptor_7e479d288f92766f, []int{10}
 }
 func (m *Tag) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1175,7 +1400,7 @@ type Tags struct {
 func (m *Tags) Reset()      { *m = Tags{} }
 func (*Tags) ProtoMessage() {}
 func (*Tags) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{8}
+	return fileDescriptor_7e479d288f92766f, []int{11}
 }
 func (m *Tags) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1226,7 +1451,7 @@ type CryptoKey struct {
 func (m *CryptoKey) Reset()      { *m = CryptoKey{} }
 func (*CryptoKey) ProtoMessage() {}
 func (*CryptoKey) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{9}
+	return fileDescriptor_7e479d288f92766f, []int{12}
 }
 func (m *CryptoKey) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1282,7 +1507,7 @@ type Err struct {
 func (m *Err) Reset()      { *m = Err{} }
 func (*Err) ProtoMessage() {}
 func (*Err) Descriptor() ([]byte, []int) {
-	return fileDescriptor_7e479d288f92766f, []int{10}
+	return fileDescriptor_7e479d288f92766f, []int{13}
 }
 func (m *Err) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -1336,6 +1561,7 @@ func init() {
 	proto.RegisterEnum("amp.Const", Const_name, Const_value)
 	proto.RegisterEnum("amp.TxOpCode", TxOpCode_name, TxOpCode_value)
 	proto.RegisterEnum("amp.TxField", TxField_name, TxField_value)
+	proto.RegisterEnum("amp.TxCodec", TxCodec_name, TxCodec_value)
 	proto.RegisterEnum("amp.SelectOp", SelectOp_name, SelectOp_value)
 	proto.RegisterEnum("amp.OpStatus", OpStatus_name, OpStatus_value)
 	proto.RegisterEnum("amp.StateSync", StateSync_name, StateSync_value)
@@ -1350,6 +1576,9 @@ func init() {
 	proto.RegisterType((*LoginChallenge)(nil), "amp.LoginChallenge")
 	proto.RegisterType((*LoginResponse)(nil), "amp.LoginResponse")
 	proto.RegisterType((*LoginCheckpoint)(nil), "amp.LoginCheckpoint")
+	proto.RegisterType((*SessionResume)(nil), "amp.SessionResume")
+	proto.RegisterType((*ResumePin)(nil), "amp.ResumePin")
+	proto.RegisterType((*Handshake)(nil), "amp.Handshake")
 	proto.RegisterType((*PinRequest)(nil), "amp.PinRequest")
 	proto.RegisterType((*LaunchURL)(nil), "amp.LaunchURL")
 	proto.RegisterType((*Tag)(nil), "amp.Tag")
@@ -1361,130 +1590,145 @@ func init() {
 func init() { proto.RegisterFile("amp/amp.proto", fileDescriptor_7e479d288f92766f) }
 
 var fileDescriptor_7e479d288f92766f = []byte{
-	// 1966 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x98, 0x4f, 0x70, 0x1b, 0x49,
-	0xf5, 0xc7, 0x3d, 0x92, 0x2c, 0x5b, 0xed, 0x7f, 0xed, 0x5e, 0xdb, 0x99, 0xe4, 0xe7, 0x28, 0x2e,
-	0x25, 0x3f, 0xe4, 0x52, 0x6d, 0xfe, 0x48, 0x61, 0x0f, 0x1c, 0x6d, 0x49, 0x49, 0x54, 0xeb, 0x7f,
-	0x35, 0x92, 0x03, 0x1b, 0xaa, 0x56, 0xd5, 0xd1, 0x3c, 0x49, 0x53, 0x19, 0x75, 0x0f, 0x3d, 0x2d,
-	0x23, 0xe5, 0xc4, 0x85, 0x2a, 0x58, 0xfe, 0x2d, 0x1c, 0x38, 0x2d, 0xb0, 0x1c, 0x58, 0x96, 0x3d,
-	0x71, 0xe3, 0x00, 0x0b, 0x05, 0x5c, 0xb6, 0x38, 0xe5, 0xb8, 0xc5, 0x89, 0x38, 0x17, 0x0e, 0x50,
-	0x95, 0x0b, 0x67, 0xa8, 0xee, 0xf9, 0xa3, 0x19, 0xaf, 0x6f, 0xaf, 0x3f, 0xdf, 0xd7, 0xaf, 0x5f,
-	0xbf, 0xee, 0x7e, 0x23, 0x1b, 0xad, 0xd0, 0x91, 0x77, 0x97, 0x8e, 0xbc, 0x3b, 0x9e, 0xe0, 0x92,
-	0x93, 0x2c, 0x1d, 0x79, 0xa5, 0xf7, 0xb2, 0x08, 0x75, 0x26, 0x4d, 0x76, 0x06, 0x2e, 0xf7, 0x80,
-	0xfc, 0x3f, 0xca, 0xb7, 0x25, 0x95, 0x63, 0xdf, 0xcc, 0xec, 0x18, 0xbb, 0xab, 0xb5, 0x95, 0x3b,
-	0xca, 0xff, 0xd8, 0x0b, 0xa0, 0x15, 0x8a, 0xc4, 0x44, 0x0b, 0xc7, 0x5e, 0x9d, 0x8f, 0x99, 0x34,
-	0x73, 0x3b, 0xc6, 0x6e, 0xce, 0x8a, 0x86, 0xe4, 0x06, 0x5a, 0x7a, 0x08, 0x0c, 0x7c, 0xc7, 0x6f,
-	0x35, 0xba, 0xf7, 0xcc, 0xf9, 0x1d, 0x63, 0x37, 0x6b, 0xa1, 0x18, 0xdd, 0x4b, 0x3b, 0x54, 0xcd,
-	0xfc, 0x8e, 0xb1, 0x9b, 0x4f, 0x38, 0x54, 0xd3, 0x0e, 0x35, 0x73, 0xe1, 0x82, 0x43, 0x4d, 0x39,
-	0xd4, 0x39, 0x93, 0x30, 0x91, 0x7a, 0x09, 0x14, 0x2c, 0x11, 0xa3, 0x7b, 0x69, 0x87, 0xaa, 0xb9,
-	0x14, 0x44, 0x88, 0x51, 0x35, 0xed, 0x50, 0x33, 0x97, 0x2f, 0x38, 0xd4, 0xc8, 0x36, 0xca, 0x3d,
-	0x10, 0x7c, 0x64, 0xae, 0xee, 0x18, 0xbb, 0x4b, 0xb5, 0x45, 0x5d, 0x84, 0x0e, 0x1d, 0x58, 0x9a,
-	0x12, 0x13, 0x65, 0x3a, 0xdc, 0x5c, 0xbb, 0xa0, 0x65, 0x3a, 0x9c, 0x14, 0xd1, 0x7c, 0xd3, 0xe3,
-	0xbd, 0xa1, 0x89, 0x2f, 0x88, 0x01, 0x26, 0xd7, 0x51, 0xae, 0x43, 0x07, 0xbe, 0xb9, 0xae, 0xe5,
-	0x42, 0x24, 0xfb, 0x96, 0xc6, 0xa5, 0xdf, 0x1b, 0x68, 0xfe, 0x80, 0x0f, 0x1c, 0x46, 0x76, 0x50,
-	0xfe, 0xd4, 0x07, 0xd1, 0x6a, 0x98, 0xc6, 0x85, 0x48, 0x21, 0x27, 0xb7, 0xd0, 0x62, 0x03, 0xce,
-	0x9c, 0x1e, 0xb4, 0x1a, 0xba, 0xca, 0x49, 0x9f, 0x58, 0x21, 0x3b, 0x68, 0xe9, 0x11, 0xf7, 0xe5,
-	0x9e, 0x6d, 0x0b, 0xf0, 0x7d, 0x73, 0x71, 0xc7, 0xd8, 0x2d, 0x58, 0x49, 0x44, 0x48, 0x98, 0x52,
-	0x41, 0x4b, 0xda, 0x26, 0x5f, 0x46, 0xa8, 0x3e, 0x84, 0xde, 0x33, 0x8f, 0x3b, 0x4c, 0xea, 0xf2,
-	0x2c, 0xd5, 0x36, 0x74, 0x74, 0x9d, 0xdd, 0x4c, 0xb3, 0x12, 0x7e, 0xa5, 0x5b, 0x68, 0x35, 0x94,
-	0xa9, 0xeb, 0x02, 0x1b, 0x80, 0x8a, 0xfd, 0x88, 0xfa, 0x43, 0xbd, 0x87, 0x65, 0x4b, 0xdb, 0xa5,
-	0xfb, 0x68, 0x45, 0x7b, 0x59, 0xe0, 0x7b, 0x9c, 0xf9, 0x40, 0x4a, 0x68, 0x59, 0x09, 0xd1, 0x38,
-	0x74, 0x4e, 0xb1, 0xd2, 0xef, 0x0c, 0xb4, 0x76, 0x61, 0x69, 0xb2, 0x8d, 0x0a, 0x1d, 0xfe, 0x0c,
-	0x58, 0x67, 0xea, 0x05, 0x93, 0x0a, 0xd6, 0x0c, 0xa8, 0x8d, 0xef, 0xf5, 0x7a, 0xe0, 0xfb, 0x1a,
-	0xe9, 0xdb, 0x5c, 0xb0, 0x92, 0x48, 0xad, 0x6b, 0x41, 0x5f, 0x80, 0x3f, 0x0c, 0x5c, 0xb2, 0xda,
-	0x25, 0xc5, 0xc8, 0x16, 0xca, 0x37, 0x27, 0x9e, 0x23, 0xa6, 0xfa, 0x9a, 0x67, 0xad, 0x70, 0xa4,
-	0x78, 0x78, 0x3c, 0x4b, 0x7a, 0x56, 0x74, 0x28, 0x18, 0x65, 0x4f, 0xad, 0x96, 0xae, 0x58, 0xc1,
-	0x52, 0x66, 0xe9, 0x23, 0x03, 0xa1, 0x13, 0xb5, 0xdb, 0x6f, 0x8c, 0xc1, 0x97, 0xe4, 0x4b, 0xa8,
-	0x70, 0xe2, 0xb0, 0x0e, 0x15, 0x03, 0x90, 0x3a, 0xa9, 0xe4, 0xb1, 0xcd, 0x24, 0x75, 0xba, 0x27,
-	0x0e, 0xdb, 0x93, 0x52, 0xf8, 0x66, 0x6e, 0x27, 0x9b, 0x3e, 0xdd, 0x48, 0x21, 0x6f, 0xa2, 0x82,
-	0x7a, 0x90, 0xd0, 0x9e, 0xb2, 0x9e, 0x7e, 0x49, 0xab, 0xb5, 0x55, 0xed, 0x16, 0x53, 0x6b, 0xe6,
-	0xa0, 0x2e, 0x75, 0xe2, 0xf2, 0x25, 0x2e, 0xb5, 0xbe, 0x7b, 0xd7, 0x51, 0xe1, 0x80, 0x8e, 0x59,
-	0x6f, 0x78, 0x6a, 0x1d, 0x04, 0xfb, 0x38, 0x08, 0xab, 0xaa, 0xcc, 0xd2, 0x7f, 0x0d, 0x94, 0xed,
-	0xd0, 0x01, 0x59, 0x47, 0x39, 0xfd, 0xea, 0x32, 0xba, 0x1e, 0x59, 0xf5, 0xdc, 0x02, 0x54, 0xd5,
-	0x05, 0xcc, 0x2b, 0x54, 0x0d, 0x51, 0x4d, 0x57, 0x4d, 0xa3, 0x9a, 0x3a, 0x10, 0xfd, 0xc0, 0x98,
-	0xd4, 0x07, 0x86, 0x82, 0x03, 0x49, 0x20, 0xbd, 0x68, 0xab, 0x11, 0x17, 0xaf, 0xd5, 0xd0, 0x77,
-	0x13, 0x26, 0xd2, 0x5c, 0x09, 0xef, 0x26, 0x4c, 0x64, 0x94, 0xda, 0x5a, 0x9c, 0x1a, 0xb9, 0x89,
-	0xf2, 0x87, 0x20, 0x85, 0xd3, 0x33, 0x37, 0x74, 0x09, 0x96, 0xf4, 0xce, 0x02, 0x64, 0x85, 0x12,
-	0xd9, 0x40, 0xf3, 0x6d, 0xe7, 0x39, 0x7c, 0xcd, 0xdc, 0xd4, 0x89, 0x07, 0x83, 0x88, 0xbe, 0x63,
-	0x6e, 0xcd, 0xe8, 0x3b, 0x11, 0x7d, 0x62, 0x5e, 0x99, 0xd1, 0x27, 0xa5, 0x66, 0x50, 0x3e, 0xf5,
-	0xfa, 0x2f, 0x79, 0x96, 0x99, 0x56, 0x83, 0xdc, 0x44, 0x0b, 0xed, 0xf1, 0x53, 0x5d, 0xe3, 0x45,
-	0x7d, 0x66, 0x89, 0x07, 0x1e, 0x29, 0xa5, 0xaf, 0xa3, 0x42, 0x5d, 0x4c, 0x3d, 0xc9, 0xdf, 0x86,
-	0x29, 0xa9, 0xa1, 0xa5, 0x70, 0xe0, 0xc8, 0x30, 0xe8, 0x6a, 0x0d, 0xeb, 0x59, 0x09, 0x6e, 0x25,
-	0x9d, 0xc8, 0x35, 0xb4, 0xf8, 0x36, 0x4c, 0xf7, 0xa7, 0x12, 0x7c, 0x5d, 0xdf, 0x65, 0x2b, 0x1e,
-	0x97, 0xde, 0x45, 0xd9, 0xa6, 0x10, 0x64, 0x07, 0xe5, 0xea, 0xdc, 0x86, 0x30, 0xde, 0xb2, 0x8e,
-	0xd7, 0x14, 0x42, 0x31, 0x4b, 0x2b, 0xe4, 0x26, 0x9a, 0x3f, 0x80, 0x33, 0x70, 0x53, 0x6d, 0xfe,
-	0x80, 0x0f, 0x34, 0xb4, 0x02, 0x4d, 0x95, 0xfa, 0xd0, 0x1f, 0xe8, 0x45, 0x0a, 0x96, 0x32, 0x2b,
-	0x1f, 0x1a, 0x68, 0xbe, 0xce, 0x99, 0x2f, 0xc9, 0x2a, 0x42, 0xda, 0xe8, 0x36, 0xa0, 0xef, 0xe3,
-	0x39, 0x72, 0x1d, 0x99, 0xf1, 0x98, 0x8e, 0x5d, 0xd9, 0x06, 0xa1, 0x5a, 0xd0, 0x09, 0x17, 0x12,
-	0x7f, 0xb6, 0x4b, 0xae, 0xa0, 0x37, 0x02, 0xb9, 0x33, 0x79, 0x04, 0xd4, 0x06, 0xd1, 0x55, 0x45,
-	0xc5, 0x98, 0x5c, 0x43, 0x5b, 0x17, 0x84, 0xc7, 0x20, 0x7c, 0x87, 0x33, 0x7c, 0x9f, 0x6c, 0xa3,
-	0xcd, 0x0b, 0xda, 0x21, 0x15, 0xcf, 0x40, 0xe0, 0xd7, 0x7f, 0xff, 0x76, 0x96, 0x6c, 0x22, 0x1c,
-	0xa8, 0x2d, 0x76, 0xc6, 0x7b, 0x54, 0xaa, 0x39, 0x9f, 0x5e, 0xaf, 0x74, 0xd0, 0x62, 0x67, 0xa2,
-	0xbe, 0x46, 0xb6, 0xba, 0x51, 0xcb, 0x91, 0xdd, 0x3d, 0x72, 0x5c, 0x3c, 0xa7, 0x96, 0x8b, 0xc9,
-	0xa9, 0xe7, 0x83, 0x90, 0x4d, 0x17, 0x46, 0xc0, 0x24, 0xce, 0xa4, 0xb4, 0x06, 0xb8, 0x20, 0x21,
-	0xd2, 0x72, 0x95, 0x17, 0x19, 0xb4, 0xd0, 0x99, 0x3c, 0x70, 0xc0, 0xb5, 0xc9, 0x1a, 0x5a, 0x0a,
-	0xcd, 0x30, 0xe8, 0x06, 0xc2, 0x11, 0xa8, 0x83, 0xeb, 0xaa, 0xf7, 0x81, 0x8d, 0x4b, 0x68, 0x15,
-	0x67, 0x2e, 0xa1, 0x35, 0x9c, 0x4d, 0x52, 0xf5, 0xb2, 0x75, 0x84, 0xdc, 0x25, 0xb4, 0x8a, 0xe7,
-	0x2f, 0xa1, 0x35, 0x9c, 0x4f, 0xd2, 0x96, 0x84, 0x91, 0x8e, 0xb0, 0x70, 0x09, 0xad, 0xe2, 0xc5,
-	0x4b, 0x68, 0x0d, 0x17, 0x92, 0xb4, 0x69, 0x3b, 0xfa, 0xdb, 0x8a, 0xd1, 0x25, 0xb4, 0x8a, 0x97,
-	0x2e, 0xa1, 0x35, 0xbc, 0x4c, 0x36, 0xd1, 0x7a, 0x5c, 0x98, 0xf1, 0x48, 0x1b, 0x3e, 0x5e, 0x49,
-	0xe2, 0x43, 0x3a, 0x09, 0xb1, 0x59, 0x39, 0x40, 0x8b, 0x6d, 0x70, 0xa1, 0x27, 0x8f, 0x3d, 0x15,
-	0x2f, 0xb2, 0xbb, 0x47, 0x30, 0x96, 0x82, 0x86, 0x75, 0x8d, 0x69, 0x8b, 0xf5, 0xdc, 0xb1, 0x0d,
-	0x41, 0x5d, 0x63, 0xda, 0x9c, 0x04, 0x34, 0x53, 0x39, 0x43, 0x8b, 0xd1, 0xaf, 0x14, 0x75, 0xd9,
-	0x22, 0xbb, 0x7b, 0xc4, 0x65, 0x5b, 0x52, 0x21, 0xc1, 0x0e, 0x02, 0xc6, 0x82, 0x6a, 0x89, 0x0e,
-	0x1b, 0x60, 0x83, 0xac, 0xa3, 0x95, 0x98, 0xee, 0x8f, 0xfd, 0x29, 0xce, 0x90, 0x37, 0xd0, 0x5a,
-	0xca, 0x11, 0x6c, 0x9c, 0x4d, 0xc1, 0xba, 0xcb, 0x7d, 0xb0, 0xf1, 0x42, 0xc5, 0x4a, 0xb4, 0x60,
-	0x42, 0xd0, 0x6a, 0x3c, 0xe8, 0x1e, 0x71, 0x06, 0x78, 0x8e, 0x5c, 0x45, 0x9b, 0x33, 0xa6, 0xa7,
-	0x1d, 0x33, 0x65, 0x63, 0x83, 0x6c, 0x21, 0x32, 0x93, 0x0e, 0xa9, 0xc3, 0x24, 0x75, 0x18, 0xce,
-	0x54, 0xde, 0x45, 0xf9, 0x26, 0xa3, 0x4f, 0x5d, 0x50, 0x09, 0x07, 0x56, 0xf7, 0x80, 0xaa, 0x3e,
-	0x79, 0xdc, 0xef, 0xe3, 0x39, 0x95, 0x48, 0x9a, 0x32, 0x6c, 0x24, 0xe0, 0x5e, 0x4f, 0x3a, 0x67,
-	0x70, 0xcc, 0x82, 0xdb, 0x96, 0x86, 0xfd, 0x3e, 0xce, 0x56, 0x3e, 0x30, 0x50, 0xe1, 0x54, 0xb8,
-	0xed, 0xde, 0x10, 0x46, 0xa0, 0xb6, 0x1f, 0x0f, 0x66, 0xaf, 0x64, 0x86, 0x4e, 0x99, 0x80, 0x1e,
-	0x1f, 0x30, 0xe7, 0x39, 0xd8, 0xd8, 0x50, 0x7b, 0x9c, 0x69, 0x8f, 0xa4, 0xf4, 0x70, 0x26, 0xcd,
-	0x1a, 0x54, 0x52, 0x9c, 0x4d, 0xb3, 0x07, 0x8e, 0x0b, 0x38, 0x97, 0x5e, 0x6a, 0x6f, 0xe4, 0xe1,
-	0x85, 0x34, 0x7a, 0xe8, 0x48, 0x8c, 0x2b, 0x7f, 0x36, 0xa2, 0x86, 0xae, 0xba, 0x4c, 0x60, 0x85,
-	0x89, 0x6d, 0xa2, 0xf5, 0x70, 0x7c, 0x2c, 0xe4, 0x90, 0x9f, 0x38, 0x13, 0x70, 0xb1, 0x71, 0x11,
-	0x1f, 0x82, 0x04, 0x11, 0x3c, 0xe8, 0x14, 0x76, 0x5c, 0xd7, 0x19, 0x69, 0x2d, 0xfb, 0x85, 0x48,
-	0x2e, 0x65, 0xcf, 0x70, 0x8e, 0x6c, 0x23, 0x33, 0xc4, 0x8f, 0x60, 0xf2, 0x50, 0x38, 0x76, 0x62,
-	0xd2, 0x3c, 0xd9, 0x45, 0xb7, 0x42, 0xb5, 0x23, 0xa8, 0x07, 0xcf, 0x79, 0x83, 0xdb, 0xd0, 0xa3,
-	0x43, 0xb0, 0x05, 0x67, 0x09, 0xcf, 0x7c, 0xe5, 0xa7, 0x46, 0xaa, 0xb3, 0xab, 0x6d, 0xc6, 0xc3,
-	0x70, 0x2f, 0xdb, 0xc8, 0x9c, 0xa1, 0x36, 0xf4, 0x04, 0xc8, 0x7d, 0x3e, 0xe9, 0x1e, 0xd1, 0xba,
-	0x8b, 0x6d, 0xdd, 0x17, 0x63, 0x75, 0xcf, 0x9f, 0x8e, 0x0e, 0xfd, 0x41, 0xa0, 0x41, 0x5a, 0x6b,
-	0x3b, 0x03, 0xe6, 0xb0, 0x50, 0xeb, 0x93, 0x22, 0xba, 0xfa, 0x45, 0xad, 0xd9, 0xa8, 0xbd, 0xf5,
-	0x56, 0xf5, 0x2b, 0xf8, 0x6f, 0x46, 0xe5, 0x3f, 0x79, 0xb4, 0x10, 0x7e, 0x0a, 0x54, 0x52, 0xa1,
-	0xd9, 0x3d, 0xe2, 0x4d, 0x21, 0xf0, 0x1c, 0xb9, 0x82, 0x48, 0x84, 0x4e, 0x19, 0xa3, 0x23, 0xb0,
-	0x15, 0xff, 0x4e, 0x99, 0x98, 0xe8, 0x8d, 0x48, 0x68, 0x31, 0x09, 0x82, 0x51, 0x57, 0x29, 0xdf,
-	0x2d, 0x93, 0x6b, 0x68, 0x73, 0x36, 0xc5, 0x1f, 0x7b, 0x1e, 0x57, 0xaf, 0xed, 0xd8, 0xc3, 0xef,
-	0x5d, 0xd0, 0x9c, 0x91, 0x17, 0xf4, 0x53, 0xb0, 0xf1, 0xf7, 0xca, 0x64, 0x03, 0xad, 0x45, 0x5a,
-	0xc7, 0x19, 0x01, 0x1f, 0x4b, 0xfc, 0xfd, 0x32, 0xb9, 0x8a, 0x36, 0x22, 0xda, 0x1e, 0x8e, 0xa5,
-	0x74, 0xd8, 0xa0, 0xc1, 0xbf, 0xc9, 0xf0, 0x0f, 0x52, 0xd2, 0x11, 0x97, 0x75, 0xce, 0x18, 0xf4,
-	0x54, 0xac, 0x1f, 0x96, 0x93, 0x69, 0xef, 0x8d, 0xe5, 0xf0, 0x01, 0x75, 0x5c, 0xb0, 0xf1, 0x8f,
-	0x52, 0x69, 0xeb, 0xdf, 0x8f, 0xa1, 0xf2, 0x7e, 0x99, 0xfc, 0x1f, 0xda, 0x8a, 0x17, 0x02, 0x5f,
-	0x7d, 0x71, 0xf4, 0x6f, 0x3b, 0xb0, 0xf1, 0x8f, 0xcb, 0xea, 0xdb, 0x92, 0x58, 0xca, 0x02, 0x6a,
-	0x4f, 0xf1, 0x4f, 0xca, 0x64, 0x1b, 0x5d, 0x89, 0x70, 0xf8, 0x83, 0xee, 0x88, 0xcb, 0x07, 0x7c,
-	0xcc, 0x6c, 0xfc, 0x41, 0x6a, 0xb3, 0xa1, 0x1a, 0x76, 0x89, 0x9f, 0xa5, 0x12, 0xdc, 0xa7, 0x76,
-	0x28, 0xe3, 0x9f, 0xa7, 0x84, 0x16, 0x3b, 0xa3, 0xae, 0x63, 0x9f, 0x5a, 0x2d, 0xfc, 0x8b, 0x54,
-	0x0a, 0xfb, 0xd4, 0x7e, 0x4c, 0xdd, 0x31, 0xe0, 0x0f, 0x2f, 0xf3, 0xef, 0xd0, 0x01, 0xfe, 0x65,
-	0xaa, 0x3a, 0xea, 0xb3, 0x10, 0x27, 0xf6, 0xab, 0x54, 0xda, 0x47, 0x5c, 0x0e, 0x1d, 0x36, 0xe8,
-	0xf0, 0x3a, 0x1f, 0x8d, 0x1c, 0x89, 0x3f, 0x4a, 0x4d, 0x0c, 0x60, 0x58, 0xa3, 0x5f, 0xa7, 0x76,
-	0xd4, 0xf6, 0x68, 0x0f, 0xe2, 0xa0, 0x1f, 0xa7, 0xeb, 0x27, 0xb9, 0xa0, 0x03, 0x50, 0xf3, 0xc6,
-	0x02, 0xf0, 0x6f, 0x52, 0x65, 0xdf, 0xf3, 0xbc, 0x78, 0xda, 0x27, 0x29, 0xe5, 0x90, 0xba, 0x7d,
-	0x2e, 0x46, 0x60, 0x77, 0x26, 0xf8, 0xb7, 0x65, 0xb2, 0x85, 0xd6, 0x13, 0x1b, 0xd6, 0x1d, 0x81,
-	0xe2, 0x3f, 0xa4, 0x66, 0xa8, 0xd6, 0x12, 0xad, 0xf2, 0x69, 0x6a, 0x46, 0x73, 0xa2, 0xae, 0x9d,
-	0xba, 0x91, 0x7f, 0x4c, 0xf1, 0x93, 0xf8, 0xc8, 0xff, 0x94, 0xde, 0x29, 0xb8, 0x6e, 0x9c, 0xd6,
-	0x5f, 0x52, 0x8b, 0x9c, 0x08, 0x7e, 0xe6, 0xd8, 0x20, 0x54, 0xb0, 0xbf, 0x96, 0xc9, 0x0d, 0x74,
-	0x2d, 0x52, 0x1e, 0x3b, 0xdc, 0xa5, 0x12, 0xfc, 0x3d, 0xcf, 0x03, 0x66, 0x1f, 0x33, 0x77, 0x8a,
-	0xff, 0x55, 0x26, 0xb7, 0xd0, 0x8d, 0xd9, 0x89, 0xf8, 0xe3, 0x7e, 0xdf, 0xe9, 0x39, 0xc0, 0xe4,
-	0x09, 0x88, 0x91, 0xa3, 0xef, 0x95, 0x8f, 0xff, 0x5d, 0xae, 0x34, 0xd0, 0x62, 0xf4, 0xf3, 0x4a,
-	0xb5, 0xc6, 0xc8, 0xee, 0x36, 0x85, 0xe0, 0xea, 0xe1, 0xad, 0xeb, 0x3f, 0x8b, 0x02, 0xf6, 0x55,
-	0x2a, 0x54, 0xf3, 0x4e, 0xa2, 0x16, 0xeb, 0x73, 0x9c, 0xdb, 0x1f, 0xbe, 0x78, 0x59, 0x9c, 0xfb,
-	0xfc, 0x65, 0x71, 0xee, 0xf5, 0xcb, 0xa2, 0xf1, 0xad, 0xf3, 0xa2, 0xf1, 0xf1, 0x79, 0xd1, 0xf8,
-	0xec, 0xbc, 0x68, 0xbc, 0x38, 0x2f, 0x1a, 0xff, 0x38, 0x2f, 0x1a, 0xff, 0x3c, 0x2f, 0xce, 0xbd,
-	0x3e, 0x2f, 0x1a, 0xef, 0xbf, 0x2a, 0xce, 0xbd, 0x78, 0x55, 0x9c, 0xfb, 0xfc, 0x55, 0x71, 0xee,
-	0xc9, 0x9b, 0x03, 0x47, 0x0e, 0xc7, 0x4f, 0xef, 0xf4, 0xf8, 0xe8, 0x2e, 0x15, 0xf2, 0xf6, 0x08,
-	0x6c, 0x87, 0xde, 0xf6, 0x5c, 0x2a, 0x55, 0xfd, 0xef, 0xd2, 0x91, 0x77, 0xdb, 0xb7, 0x9f, 0xdd,
-	0x1e, 0x70, 0x65, 0x7e, 0x92, 0xc9, 0xee, 0x1d, 0x9e, 0x3c, 0xcd, 0xeb, 0xff, 0x11, 0xdc, 0xff,
-	0x5f, 0x00, 0x00, 0x00, 0xff, 0xff, 0xf4, 0x89, 0xe1, 0x91, 0x34, 0x10, 0x00, 0x00,
+	// 2205 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8c, 0x98, 0x4d, 0x70, 0x23, 0x47,
+	0xd9, 0xc7, 0x3d, 0x92, 0x2c, 0x5b, 0xed, 0x8f, 0x6d, 0x77, 0x6c, 0xef, 0x64, 0x5f, 0xaf, 0xe2,
+	0x52, 0xf6, 0x45, 0x2e, 0x55, 0x36, 0x89, 0x15, 0x72, 0xe0, 0x68, 0x4b, 0xda, 0xb5, 0x2a, 0xfe,
+	0xaa, 0x91, 0x1c, 0xc8, 0x52, 0x15, 0x55, 0xaf, 0xe6, 0x91, 0xd4, 0xe5, 0x51, 0xf7, 0x30, 0xd3,
+	0x32, 0xd2, 0x9e, 0xb8, 0x50, 0x15, 0xc2, 0x57, 0x80, 0x6b, 0x80, 0x70, 0x20, 0x84, 0x1c, 0x28,
+	0x6e, 0x1c, 0x20, 0x50, 0xc0, 0x25, 0xc5, 0x69, 0x8f, 0x29, 0x4e, 0xc4, 0xb9, 0x70, 0x80, 0xaa,
+	0x3d, 0xf1, 0x71, 0x82, 0xea, 0x9e, 0x0f, 0xcd, 0x68, 0x75, 0xe0, 0xf6, 0xf4, 0xef, 0xff, 0xf4,
+	0xd7, 0x33, 0x4f, 0x3f, 0xdd, 0x12, 0x5a, 0xa3, 0x43, 0xf7, 0x25, 0x3a, 0x74, 0x5f, 0x74, 0x3d,
+	0x21, 0x05, 0xc9, 0xd2, 0xa1, 0x5b, 0x7a, 0x3b, 0x8b, 0x50, 0x7b, 0xdc, 0xe0, 0x57, 0xe0, 0x08,
+	0x17, 0xc8, 0xff, 0xa3, 0x7c, 0x4b, 0x52, 0x39, 0xf2, 0xcd, 0xcc, 0xae, 0xb1, 0xb7, 0x5e, 0x5d,
+	0x7b, 0x51, 0xf9, 0x9f, 0xb9, 0x01, 0xb4, 0x42, 0x91, 0x98, 0x68, 0xe9, 0xcc, 0xad, 0x89, 0x11,
+	0x97, 0x66, 0x6e, 0xd7, 0xd8, 0xcb, 0x59, 0x51, 0x93, 0x3c, 0x87, 0x56, 0xee, 0x03, 0x07, 0x9f,
+	0xf9, 0xcd, 0x7a, 0xe7, 0x65, 0x73, 0x71, 0xd7, 0xd8, 0xcb, 0x5a, 0x28, 0x46, 0x2f, 0xa7, 0x1d,
+	0xf6, 0xcd, 0xfc, 0xae, 0xb1, 0x97, 0x4f, 0x38, 0xec, 0xa7, 0x1d, 0xaa, 0xe6, 0xd2, 0x8c, 0x43,
+	0x55, 0x39, 0xd4, 0x04, 0x97, 0x30, 0x96, 0x7a, 0x0a, 0x14, 0x4c, 0x11, 0xa3, 0x97, 0xd3, 0x0e,
+	0xfb, 0xe6, 0x4a, 0x30, 0x42, 0x8c, 0xf6, 0xd3, 0x0e, 0x55, 0x73, 0x75, 0xc6, 0xa1, 0x4a, 0x76,
+	0x50, 0xee, 0x9e, 0x27, 0x86, 0xe6, 0xfa, 0xae, 0xb1, 0xb7, 0x52, 0x5d, 0xd6, 0x41, 0x68, 0xd3,
+	0xbe, 0xa5, 0x29, 0x31, 0x51, 0xa6, 0x2d, 0xcc, 0x1b, 0x33, 0x5a, 0xa6, 0x2d, 0x48, 0x11, 0x2d,
+	0x36, 0x5c, 0xd1, 0x1d, 0x98, 0x78, 0x46, 0x0c, 0x30, 0xb9, 0x8d, 0x72, 0x6d, 0xda, 0xf7, 0xcd,
+	0x0d, 0x2d, 0x17, 0x22, 0xd9, 0xb7, 0x34, 0x2e, 0xfd, 0xda, 0x40, 0x8b, 0xc7, 0xa2, 0xcf, 0x38,
+	0xd9, 0x45, 0xf9, 0x0b, 0x1f, 0xbc, 0x66, 0xdd, 0x34, 0x66, 0x46, 0x0a, 0x39, 0xb9, 0x83, 0x96,
+	0xeb, 0x70, 0xc5, 0xba, 0xd0, 0xac, 0x9b, 0x8b, 0x33, 0x3e, 0xb1, 0x42, 0x76, 0xd1, 0xca, 0x91,
+	0xf0, 0xe5, 0x81, 0x6d, 0x7b, 0xe0, 0xfb, 0xe6, 0xf2, 0xae, 0xb1, 0x57, 0xb0, 0x92, 0x88, 0x90,
+	0x70, 0x49, 0x05, 0x2d, 0x69, 0x9b, 0x7c, 0x1e, 0xa1, 0xda, 0x00, 0xba, 0x97, 0xae, 0x60, 0x5c,
+	0xea, 0xf0, 0xac, 0x54, 0x37, 0xf5, 0xe8, 0x7a, 0x75, 0x53, 0xcd, 0x4a, 0xf8, 0x95, 0xee, 0xa0,
+	0xf5, 0x50, 0xa6, 0x8e, 0x03, 0xbc, 0x0f, 0x6a, 0xec, 0x23, 0xea, 0x0f, 0xf4, 0x1e, 0x56, 0x2d,
+	0x6d, 0x97, 0x5e, 0x41, 0x6b, 0xda, 0xcb, 0x02, 0xdf, 0x15, 0xdc, 0x07, 0x52, 0x42, 0xab, 0x4a,
+	0x88, 0xda, 0xa1, 0x73, 0x8a, 0x95, 0x7e, 0x65, 0xa0, 0x1b, 0x33, 0x53, 0x93, 0x1d, 0x54, 0x68,
+	0x8b, 0x4b, 0xe0, 0xed, 0x89, 0x1b, 0x74, 0x2a, 0x58, 0x53, 0xa0, 0x36, 0x7e, 0xd0, 0xed, 0x82,
+	0xef, 0x6b, 0xa4, 0xb3, 0xb9, 0x60, 0x25, 0x91, 0x9a, 0xd7, 0x82, 0x9e, 0x07, 0xfe, 0x20, 0x70,
+	0xc9, 0x6a, 0x97, 0x14, 0x23, 0xdb, 0x28, 0xdf, 0x18, 0xbb, 0xcc, 0x9b, 0xe8, 0x34, 0xcf, 0x5a,
+	0x61, 0x4b, 0xf1, 0xf0, 0xf3, 0xac, 0xe8, 0x5e, 0x61, 0x8b, 0x60, 0x94, 0xbd, 0xb0, 0x9a, 0x3a,
+	0x62, 0x05, 0x4b, 0x99, 0x25, 0x86, 0xd6, 0x5a, 0xe0, 0xfb, 0x4c, 0xa8, 0x0d, 0x8f, 0x86, 0x30,
+	0x13, 0x5b, 0xe3, 0x7f, 0x8b, 0x2d, 0x29, 0xa1, 0xdc, 0x39, 0xe3, 0xea, 0x54, 0x66, 0xf7, 0x56,
+	0xaa, 0xeb, 0xda, 0x3f, 0x18, 0xf0, 0x9c, 0x71, 0x4b, 0x6b, 0x25, 0x8a, 0x0a, 0x31, 0x22, 0x9f,
+	0x53, 0x8d, 0xaf, 0x8c, 0xc0, 0x97, 0x73, 0x72, 0x68, 0x2a, 0x91, 0x17, 0xd0, 0xea, 0x31, 0xf5,
+	0x65, 0x6b, 0xc2, 0xbb, 0x60, 0xb7, 0xc7, 0x66, 0x66, 0xc6, 0x35, 0xa5, 0x96, 0x3e, 0x32, 0x50,
+	0xe1, 0x88, 0x72, 0xdb, 0x1f, 0xd0, 0x4b, 0x20, 0x15, 0x84, 0x4f, 0x18, 0x3f, 0x02, 0x6a, 0x83,
+	0xf7, 0x3a, 0x78, 0x6a, 0x93, 0x7a, 0xaa, 0x35, 0xeb, 0x29, 0xae, 0x7d, 0xe9, 0x38, 0xed, 0x9b,
+	0x09, 0x7d, 0x67, 0x38, 0xb9, 0x83, 0xf2, 0x35, 0x61, 0x43, 0xd7, 0x37, 0xb3, 0xbb, 0xd9, 0xbd,
+	0xf5, 0xea, 0x6a, 0xb0, 0x9a, 0xb1, 0x86, 0x56, 0xa8, 0x91, 0x2a, 0x5a, 0x69, 0xb1, 0x3e, 0x67,
+	0xbc, 0xff, 0x1a, 0x93, 0xbe, 0x99, 0xd3, 0xae, 0x58, 0xbb, 0xd6, 0xbc, 0x89, 0x2b, 0xc5, 0x6b,
+	0x4c, 0x36, 0xeb, 0x56, 0xd2, 0xa9, 0xf4, 0xbe, 0x81, 0x90, 0x0a, 0x58, 0xb0, 0x7d, 0x15, 0xa4,
+	0x73, 0xc6, 0xdb, 0xd4, 0xeb, 0x83, 0x7c, 0x6a, 0xe7, 0x53, 0x49, 0x9d, 0xb5, 0x73, 0xc6, 0x0f,
+	0xa4, 0xf4, 0x82, 0x79, 0x52, 0x67, 0x2d, 0x52, 0xc8, 0x0b, 0xa8, 0xa0, 0xca, 0x23, 0xa8, 0x68,
+	0xe9, 0xba, 0xb6, 0x1e, 0x7e, 0xa8, 0x98, 0x5a, 0x53, 0x07, 0x55, 0x62, 0x12, 0xa5, 0x20, 0x51,
+	0x62, 0x74, 0x25, 0xb8, 0x8d, 0x0a, 0xc7, 0x74, 0xc4, 0xbb, 0x83, 0x0b, 0xeb, 0x38, 0xc8, 0xaa,
+	0xe3, 0x30, 0xc7, 0x95, 0x59, 0xfa, 0x8f, 0x81, 0xb2, 0x6d, 0xda, 0x27, 0x1b, 0x28, 0xa7, 0x6b,
+	0x60, 0x46, 0x67, 0x67, 0x56, 0x15, 0xbf, 0x00, 0xed, 0xeb, 0x74, 0xce, 0x2b, 0xb4, 0x1f, 0xa2,
+	0xaa, 0x99, 0x8b, 0x50, 0x55, 0x1d, 0x0f, 0x5d, 0xee, 0xb8, 0xd4, 0xc7, 0x07, 0x05, 0xc7, 0x23,
+	0x81, 0xf4, 0xa4, 0xcd, 0x7a, 0x9c, 0xca, 0xcd, 0xba, 0xae, 0x14, 0x30, 0x96, 0xe6, 0x5a, 0x58,
+	0x29, 0x60, 0x2c, 0xa3, 0xa5, 0xdd, 0x88, 0x97, 0x46, 0x9e, 0x47, 0xf9, 0x13, 0x90, 0x1e, 0xeb,
+	0x9a, 0x9b, 0x3a, 0x04, 0x2b, 0x7a, 0x67, 0x01, 0xb2, 0x42, 0x89, 0x6c, 0xa2, 0xc5, 0x16, 0x7b,
+	0x04, 0x5f, 0x32, 0xb7, 0xf4, 0xc2, 0x83, 0x46, 0x44, 0xdf, 0x30, 0xb7, 0xa7, 0xf4, 0x8d, 0x88,
+	0x3e, 0x30, 0x6f, 0x4e, 0xe9, 0x83, 0x52, 0x23, 0x08, 0x9f, 0xaa, 0xc5, 0x73, 0x12, 0x3c, 0xd3,
+	0xac, 0x93, 0xe7, 0xd1, 0x52, 0x6b, 0xf4, 0x50, 0xc7, 0x78, 0x79, 0x37, 0x9b, 0x2e, 0xb7, 0x91,
+	0x52, 0xfa, 0x32, 0x2a, 0x84, 0xc9, 0x02, 0x13, 0x95, 0x51, 0x89, 0xcc, 0xd1, 0x83, 0xce, 0xcd,
+	0xa8, 0x44, 0x83, 0xdc, 0x42, 0xcb, 0xaf, 0xc1, 0xe4, 0x70, 0x22, 0xc1, 0xd7, 0xf1, 0x5d, 0xb5,
+	0xe2, 0x76, 0xe9, 0x4d, 0x94, 0x6d, 0x78, 0x1e, 0xd9, 0x45, 0x39, 0x95, 0xb2, 0xe1, 0x78, 0x41,
+	0x32, 0x37, 0x3c, 0x4f, 0x31, 0x4b, 0x2b, 0xe4, 0x79, 0xb4, 0x78, 0x0c, 0x57, 0xe0, 0xa4, 0x2e,
+	0xdd, 0x63, 0xd1, 0xd7, 0xd0, 0x0a, 0x34, 0x15, 0xea, 0x13, 0xbf, 0xaf, 0x27, 0x29, 0x58, 0xca,
+	0xac, 0xbc, 0x67, 0xa0, 0xc5, 0x9a, 0xe0, 0xbe, 0x24, 0xeb, 0x08, 0x69, 0xa3, 0x53, 0x87, 0x9e,
+	0x8f, 0x17, 0xc8, 0x6d, 0x64, 0xc6, 0x6d, 0x3a, 0x72, 0x64, 0x0b, 0x3c, 0x75, 0x21, 0x9c, 0x0b,
+	0x4f, 0xe2, 0x8f, 0xf7, 0xc8, 0x4d, 0xf4, 0x4c, 0x20, 0xb7, 0xc3, 0x93, 0xd7, 0x51, 0x41, 0xc5,
+	0x98, 0xdc, 0x42, 0xdb, 0x33, 0x42, 0x78, 0x26, 0xf1, 0x2b, 0x64, 0x07, 0x6d, 0xcd, 0x68, 0x27,
+	0xd4, 0xbb, 0x04, 0x0f, 0x3f, 0xf9, 0xf3, 0xd7, 0xb3, 0x64, 0x0b, 0xe1, 0x40, 0x6d, 0xf2, 0x2b,
+	0xd1, 0xa5, 0x52, 0xf5, 0xf9, 0xe8, 0x76, 0xa5, 0x8d, 0x96, 0xdb, 0x63, 0xf5, 0x36, 0xb0, 0x55,
+	0x46, 0xad, 0x46, 0x76, 0xe7, 0x94, 0x39, 0x78, 0x41, 0x4d, 0x17, 0x93, 0x0b, 0xd7, 0x07, 0x4f,
+	0x36, 0x1c, 0x18, 0x02, 0x97, 0x38, 0x93, 0xd2, 0xea, 0xe0, 0x80, 0x84, 0x48, 0xcb, 0x55, 0x1e,
+	0x67, 0xd0, 0x52, 0x7b, 0x7c, 0x8f, 0x81, 0x63, 0x93, 0x1b, 0x68, 0x25, 0x34, 0xc3, 0x41, 0x37,
+	0x11, 0x8e, 0x40, 0x0d, 0x1c, 0x47, 0x9d, 0x0f, 0x6c, 0xcc, 0xa1, 0xfb, 0x38, 0x33, 0x87, 0x56,
+	0x71, 0x36, 0x49, 0xd5, 0xc9, 0xd6, 0x23, 0xe4, 0xe6, 0xd0, 0x7d, 0xbc, 0x38, 0x87, 0x56, 0x71,
+	0x3e, 0x49, 0x9b, 0x12, 0x86, 0x7a, 0x84, 0xa5, 0x39, 0x74, 0x1f, 0x2f, 0xcf, 0xa1, 0x55, 0x5c,
+	0x48, 0xd2, 0x86, 0xcd, 0xf4, 0x4b, 0x07, 0xa3, 0x39, 0x74, 0x1f, 0xaf, 0xcc, 0xa1, 0x55, 0xbc,
+	0x4a, 0xb6, 0xd0, 0x46, 0x1c, 0x98, 0xd1, 0x50, 0x1b, 0x3e, 0x5e, 0x4b, 0xe2, 0x13, 0x3a, 0x0e,
+	0xb1, 0x59, 0x69, 0xab, 0x88, 0xea, 0xca, 0x1a, 0x7c, 0x27, 0x6d, 0x76, 0x4e, 0x05, 0x07, 0xbc,
+	0x40, 0x9e, 0x41, 0x37, 0x22, 0x52, 0x87, 0x9e, 0x43, 0x25, 0x60, 0x23, 0xe9, 0xf6, 0xc0, 0x61,
+	0x0f, 0x71, 0x26, 0x49, 0xee, 0x3f, 0x62, 0x2e, 0xce, 0x56, 0x8e, 0xd1, 0x72, 0x0b, 0x1c, 0xe8,
+	0xca, 0x33, 0x57, 0xad, 0x32, 0xb2, 0x3b, 0xa7, 0x30, 0x92, 0x1e, 0x0d, 0xbf, 0x56, 0x4c, 0x9b,
+	0xbc, 0xeb, 0x8c, 0x6c, 0xc0, 0x46, 0x8a, 0x36, 0xc6, 0x01, 0xcd, 0x54, 0xae, 0xd0, 0x72, 0xf4,
+	0x12, 0x55, 0x29, 0x1c, 0xd9, 0x9d, 0x53, 0x21, 0x5b, 0x92, 0x7a, 0x12, 0xec, 0x60, 0xc0, 0x58,
+	0x50, 0x85, 0x96, 0xf1, 0x3e, 0x36, 0xc8, 0x06, 0x5a, 0x8b, 0xe9, 0xe1, 0xc8, 0x9f, 0xe0, 0x8c,
+	0xda, 0x54, 0xca, 0x11, 0x6c, 0x9c, 0x4d, 0xc1, 0x9a, 0x23, 0x7c, 0xb0, 0xf1, 0x52, 0xc5, 0x4a,
+	0x14, 0x76, 0x42, 0xd0, 0x7a, 0xdc, 0x88, 0xe2, 0xf3, 0x2c, 0xda, 0x9a, 0x32, 0xdd, 0xed, 0x8c,
+	0x2b, 0x1b, 0x1b, 0x64, 0x1b, 0x91, 0xa9, 0x74, 0x42, 0x19, 0x97, 0x94, 0x71, 0x9c, 0xa9, 0xbc,
+	0x89, 0xf2, 0x0d, 0x4e, 0x1f, 0x3a, 0xa0, 0x16, 0x1c, 0x58, 0x9d, 0x63, 0xaa, 0xaa, 0xef, 0x59,
+	0xaf, 0x17, 0x84, 0x3c, 0x4d, 0x39, 0x36, 0x12, 0xf0, 0xa0, 0x2b, 0xd9, 0x15, 0x9c, 0xf1, 0x20,
+	0x87, 0xd3, 0xb0, 0xd7, 0xc3, 0xd9, 0xca, 0xbb, 0x06, 0x2a, 0x5c, 0x78, 0x4e, 0xab, 0x3b, 0x80,
+	0x21, 0xa8, 0xed, 0xc7, 0x8d, 0xe9, 0xd9, 0x9b, 0xa2, 0x0b, 0xee, 0x41, 0x57, 0xf4, 0x39, 0x7b,
+	0x04, 0x36, 0x36, 0xd4, 0x1e, 0xa7, 0xda, 0x91, 0x94, 0x2e, 0xce, 0xa4, 0x59, 0x9d, 0x4a, 0x8a,
+	0xb3, 0x69, 0x76, 0x8f, 0x39, 0x80, 0x73, 0xe9, 0xa9, 0x0e, 0x86, 0x2e, 0x5e, 0x4a, 0xa3, 0xfb,
+	0x4c, 0x62, 0x5c, 0xf9, 0xbd, 0x11, 0x5d, 0x13, 0xaa, 0x76, 0x05, 0x56, 0xb8, 0xb0, 0x2d, 0xb4,
+	0x11, 0xb6, 0xcf, 0x3c, 0x39, 0x10, 0xe7, 0x6c, 0x0c, 0x0e, 0x36, 0x66, 0xf1, 0x09, 0x48, 0xf0,
+	0x82, 0x32, 0x91, 0xc2, 0xcc, 0x71, 0xd8, 0x50, 0x6b, 0xd9, 0xa7, 0x46, 0x72, 0x28, 0xbf, 0xc4,
+	0x39, 0xb2, 0x83, 0xcc, 0x10, 0x1f, 0xc1, 0xf8, 0xbe, 0xc7, 0xec, 0x44, 0xa7, 0x45, 0xb2, 0x87,
+	0xee, 0x84, 0x6a, 0xdb, 0xa3, 0x2e, 0x3c, 0x12, 0x75, 0x95, 0xd0, 0x74, 0x00, 0xb6, 0x27, 0x78,
+	0xc2, 0x33, 0x5f, 0xf9, 0x85, 0x91, 0xba, 0x2f, 0xd4, 0x36, 0xe3, 0x66, 0xb8, 0x97, 0x1d, 0x64,
+	0x4e, 0x51, 0x0b, 0xba, 0x1e, 0xc8, 0x43, 0x31, 0xee, 0x9c, 0xd2, 0x9a, 0x83, 0x6d, 0x5d, 0x6d,
+	0x63, 0xf5, 0xc0, 0x9f, 0x0c, 0x4f, 0xfc, 0x7e, 0xa0, 0x41, 0x5a, 0x0b, 0x9f, 0x30, 0x81, 0xd6,
+	0x23, 0xdb, 0x68, 0x23, 0xd1, 0xaf, 0xd1, 0xea, 0xdc, 0xaf, 0x9d, 0xe0, 0x8f, 0x0d, 0x52, 0x44,
+	0xcf, 0x3e, 0xdd, 0xa7, 0x51, 0xaf, 0xbe, 0xfa, 0xea, 0xfe, 0x17, 0xf0, 0x9f, 0x8c, 0xca, 0x5b,
+	0xcb, 0x68, 0x29, 0xbc, 0x78, 0xd4, 0x62, 0x43, 0xb3, 0x73, 0x2a, 0x1a, 0x9e, 0x87, 0x17, 0xc8,
+	0x4d, 0x44, 0x22, 0x74, 0xc1, 0x39, 0x1d, 0x82, 0xad, 0xf8, 0x5b, 0x65, 0x62, 0xa2, 0x67, 0x22,
+	0xa1, 0xc9, 0x25, 0x78, 0x9c, 0x3a, 0x4a, 0xf9, 0x46, 0x99, 0xdc, 0x42, 0x5b, 0xd3, 0x2e, 0xfe,
+	0xc8, 0x75, 0x85, 0x3a, 0x85, 0x67, 0x2e, 0x7e, 0x7b, 0x46, 0x63, 0x43, 0x37, 0xa8, 0xde, 0x60,
+	0xe3, 0x6f, 0x96, 0xc9, 0x26, 0xba, 0x11, 0x69, 0x6d, 0x36, 0x04, 0x31, 0x92, 0xf8, 0x5b, 0x65,
+	0xf2, 0x2c, 0xda, 0x8c, 0x68, 0x6b, 0x30, 0x92, 0x92, 0xf1, 0x7e, 0x5d, 0x7c, 0x95, 0xe3, 0x6f,
+	0xa7, 0xa4, 0x53, 0x21, 0x6b, 0x82, 0x73, 0xe8, 0xaa, 0xb1, 0xbe, 0x53, 0x4e, 0x2e, 0xfb, 0x60,
+	0x24, 0x07, 0xf7, 0x28, 0x73, 0xc0, 0xc6, 0xdf, 0x4d, 0x2d, 0x5b, 0x3f, 0xad, 0x43, 0xe5, 0x9d,
+	0x32, 0xf9, 0x3f, 0xb4, 0x1d, 0x4f, 0x14, 0x3c, 0xce, 0xf5, 0xbb, 0x1e, 0x6c, 0xfc, 0xbd, 0xb2,
+	0xba, 0xc9, 0x12, 0x53, 0x59, 0x40, 0xed, 0x09, 0xfe, 0x7e, 0x6a, 0x3b, 0x75, 0xe8, 0xaa, 0x30,
+	0x87, 0xe3, 0xfd, 0xa0, 0x4c, 0x76, 0xd0, 0xcd, 0x48, 0x0b, 0x9f, 0x96, 0xa7, 0x42, 0xde, 0x13,
+	0x23, 0x6e, 0xe3, 0x77, 0x53, 0x3d, 0x43, 0x35, 0xac, 0x2c, 0x3f, 0x4c, 0x2d, 0xfe, 0x90, 0xda,
+	0xa1, 0x8c, 0x7f, 0x94, 0x12, 0x9a, 0xfc, 0x8a, 0x3a, 0xcc, 0xbe, 0xb0, 0x9a, 0xf8, 0xc7, 0xa9,
+	0xe5, 0x1d, 0x52, 0xfb, 0x75, 0xea, 0x8c, 0x00, 0xbf, 0x37, 0xcf, 0xbf, 0x4d, 0xfb, 0xf8, 0x27,
+	0xa9, 0xc8, 0xa9, 0x0b, 0x2a, 0x5e, 0xd8, 0x4f, 0x53, 0xcb, 0x3e, 0x15, 0x72, 0xc0, 0x78, 0xbf,
+	0x2d, 0x6a, 0x62, 0x38, 0x64, 0x12, 0xbf, 0x9f, 0xea, 0x18, 0xc0, 0x70, 0xbf, 0x3f, 0x4b, 0xed,
+	0xa8, 0xe5, 0xd2, 0x2e, 0xc4, 0x83, 0x7e, 0x90, 0x8e, 0xad, 0x14, 0x1e, 0xed, 0x83, 0xea, 0x37,
+	0xf2, 0x00, 0xff, 0x3c, 0xf5, 0x49, 0x0e, 0x5c, 0x37, 0xee, 0xf6, 0x61, 0x4a, 0x39, 0xa1, 0x4e,
+	0x4f, 0x78, 0x43, 0xf5, 0x83, 0x03, 0xff, 0xb2, 0xac, 0xb2, 0x3d, 0xb1, 0x61, 0x5d, 0x45, 0x28,
+	0xfe, 0x4d, 0xaa, 0x87, 0x2a, 0x47, 0xd1, 0x2c, 0x1f, 0xa5, 0x7a, 0x34, 0xc6, 0x2a, 0x25, 0x55,
+	0xb6, 0xfe, 0x36, 0xc5, 0xcf, 0xe3, 0x74, 0xf8, 0x5d, 0x7a, 0xa7, 0xe0, 0x38, 0xf1, 0xb2, 0xfe,
+	0x90, 0x9a, 0xe4, 0xdc, 0x13, 0x57, 0xcc, 0x06, 0x4f, 0x0d, 0xf6, 0xc7, 0x32, 0x79, 0x0e, 0xdd,
+	0x8a, 0x94, 0xd7, 0x99, 0x50, 0x77, 0xa2, 0x7f, 0xe0, 0xba, 0xc0, 0xed, 0x33, 0xee, 0x4c, 0xf0,
+	0xdf, 0xca, 0xe4, 0x0e, 0x7a, 0x6e, 0xfa, 0x45, 0xfc, 0x51, 0xaf, 0xc7, 0xba, 0x0c, 0xb8, 0x3c,
+	0x07, 0x6f, 0xc8, 0x74, 0xce, 0xf9, 0xf8, 0xef, 0xa9, 0x6f, 0x70, 0xce, 0xf8, 0x31, 0x1b, 0x32,
+	0x95, 0x72, 0xdd, 0x01, 0xd8, 0xf8, 0x1f, 0xa9, 0x60, 0xb6, 0xc7, 0x16, 0x95, 0xd0, 0x18, 0x77,
+	0x01, 0x6c, 0xb0, 0xf1, 0x3f, 0xcb, 0xea, 0x91, 0x17, 0x07, 0x66, 0x22, 0x21, 0x25, 0xff, 0x2b,
+	0xd5, 0x37, 0xf8, 0x7e, 0x6d, 0x21, 0x8e, 0xd5, 0xef, 0x19, 0xfc, 0xef, 0x72, 0xa5, 0x8e, 0x96,
+	0xa3, 0xf7, 0xa5, 0xaa, 0xe2, 0x91, 0xdd, 0x69, 0x78, 0x9e, 0x50, 0xb5, 0x60, 0x03, 0xad, 0xc5,
+	0xec, 0x8b, 0xd4, 0x53, 0xf7, 0x4c, 0x12, 0x35, 0x79, 0x4f, 0xe0, 0xdc, 0xe1, 0xe0, 0xf1, 0xa7,
+	0xc5, 0x85, 0x4f, 0x3e, 0x2d, 0x2e, 0x3c, 0xf9, 0xb4, 0x68, 0x7c, 0xed, 0xba, 0x68, 0x7c, 0x70,
+	0x5d, 0x34, 0x3e, 0xbe, 0x2e, 0x1a, 0x8f, 0xaf, 0x8b, 0xc6, 0x5f, 0xae, 0x8b, 0xc6, 0x5f, 0xaf,
+	0x8b, 0x0b, 0x4f, 0xae, 0x8b, 0xc6, 0x3b, 0x9f, 0x15, 0x17, 0x1e, 0x7f, 0x56, 0x5c, 0xf8, 0xe4,
+	0xb3, 0xe2, 0xc2, 0x83, 0x17, 0xfa, 0x4c, 0x0e, 0x46, 0x0f, 0x5f, 0xec, 0x8a, 0xe1, 0x4b, 0xd4,
+	0x93, 0x77, 0x87, 0x60, 0x33, 0x7a, 0xd7, 0x75, 0xa8, 0x54, 0x9f, 0x5d, 0xfd, 0x41, 0x75, 0xd7,
+	0xb7, 0x2f, 0xef, 0xf6, 0x85, 0x32, 0x3f, 0xcc, 0x64, 0x0f, 0x4e, 0xce, 0x1f, 0xe6, 0xf5, 0x5f,
+	0x56, 0xaf, 0xfc, 0x77, 0x00, 0xbe, 0xb1, 0x72, 0x82, 0xc3, 0x12, 0x00, 0x00,
 }
 
 func (x Const) String() string {
@@ -1508,6 +1752,13 @@ func (x TxField) String() string {
 	}
 	return strconv.Itoa(int(x))
 }
+func (x TxCodec) String() string {
+	s, ok := TxCodec_name[int32(x)]
+	if ok {
+		return s
+	}
+	return strconv.Itoa(int(x))
+}
 func (x SelectOp) String() string {
 	s, ok := SelectOp_name[int32(x)]
 	if ok {
@@ -1886,7 +2137,7 @@ func (m *LoginCheckpoint) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *PinRequest) Marshal() (dAtA []byte, err error) {
+func (m *SessionResume) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1896,39 +2147,20 @@ func (m *PinRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *PinRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *SessionResume) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *PinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *SessionResume) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.Tags != nil {
-		{
-			size, err := m.Tags.MarshalToSizedBuffer(dAtA[:i])
-			if err != nil {
-				return 0, err
-			}
-			i -= size
-			i = encodeVarintAmp(dAtA, i, uint64(size))
-		}
-		i--
-		dAtA[i] = 0x1
-		i--
-		dAtA[i] = 0x8a
-	}
-	if m.StateSync != 0 {
-		i = encodeVarintAmp(dAtA, i, uint64(m.StateSync))
-		i--
-		dAtA[i] = 0x30
-	}
-	if len(m.PinAttrs) > 0 {
-		for iNdEx := len(m.PinAttrs) - 1; iNdEx >= 0; iNdEx-- {
+	if len(m.Pins) > 0 {
+		for iNdEx := len(m.Pins) - 1; iNdEx >= 0; iNdEx-- {
 			{
-				size, err := m.PinAttrs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				size, err := m.Pins[iNdEx].MarshalToSizedBuffer(dAtA[:i])
 				if err != nil {
 					return 0, err
 				}
@@ -1936,12 +2168,12 @@ func (m *PinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 				i = encodeVarintAmp(dAtA, i, uint64(size))
 			}
 			i--
-			dAtA[i] = 0x22
+			dAtA[i] = 0x12
 		}
 	}
-	if m.PinTarget != nil {
+	if m.Checkpoint != nil {
 		{
-			size, err := m.PinTarget.MarshalToSizedBuffer(dAtA[:i])
+			size, err := m.Checkpoint.MarshalToSizedBuffer(dAtA[:i])
 			if err != nil {
 				return 0, err
 			}
@@ -1949,12 +2181,12 @@ func (m *PinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 			i = encodeVarintAmp(dAtA, i, uint64(size))
 		}
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *LaunchURL) Marshal() (dAtA []byte, err error) {
+func (m *ResumePin) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1964,27 +2196,44 @@ func (m *LaunchURL) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *LaunchURL) MarshalTo(dAtA []byte) (int, error) {
+func (m *ResumePin) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *LaunchURL) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *ResumePin) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if len(m.URL) > 0 {
-		i -= len(m.URL)
-		copy(dAtA[i:], m.URL)
-		i = encodeVarintAmp(dAtA, i, uint64(len(m.URL)))
+	if m.LastSyncedTx != nil {
+		{
+			size, err := m.LastSyncedTx.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	if m.RequestID != nil {
+		{
+			size, err := m.RequestID.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintAmp(dAtA, i, uint64(size))
+		}
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func (m *Tag) Marshal() (dAtA []byte, err error) {
+func (m *Handshake) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -1994,57 +2243,224 @@ func (m *Tag) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *Tag) MarshalTo(dAtA []byte) (int, error) {
+func (m *Handshake) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *Tag) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *Handshake) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
 	_ = l
-	if m.SizeZ != 0 {
-		i = encodeVarintAmp(dAtA, i, uint64(m.SizeZ))
-		i--
-		dAtA[i] = 0x1
+	if len(m.SigningKits) > 0 {
+		dAtA12 := make([]byte, len(m.SigningKits)*10)
+		var j11 int
+		for _, num := range m.SigningKits {
+			for num >= 1<<7 {
+				dAtA12[j11] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j11++
+			}
+			dAtA12[j11] = uint8(num)
+			j11++
+		}
+		i -= j11
+		copy(dAtA[i:], dAtA12[:j11])
+		i = encodeVarintAmp(dAtA, i, uint64(j11))
 		i--
-		dAtA[i] = 0xb8
+		dAtA[i] = 0x22
 	}
-	if m.SizeY != 0 {
-		i = encodeVarintAmp(dAtA, i, uint64(m.SizeY))
-		i--
-		dAtA[i] = 0x1
+	if len(m.Codecs) > 0 {
+		dAtA14 := make([]byte, len(m.Codecs)*10)
+		var j13 int
+		for _, num := range m.Codecs {
+			for num >= 1<<7 {
+				dAtA14[j13] = uint8(uint64(num)&0x7f | 0x80)
+				num >>= 7
+				j13++
+			}
+			dAtA14[j13] = uint8(num)
+			j13++
+		}
+		i -= j13
+		copy(dAtA[i:], dAtA14[:j13])
+		i = encodeVarintAmp(dAtA, i, uint64(j13))
 		i--
-		dAtA[i] = 0xb0
+		dAtA[i] = 0x1a
 	}
-	if m.SizeX != 0 {
-		i = encodeVarintAmp(dAtA, i, uint64(m.SizeX))
-		i--
-		dAtA[i] = 0x1
+	if m.MaxHeaderVersion != 0 {
+		i = encodeVarintAmp(dAtA, i, uint64(m.MaxHeaderVersion))
 		i--
-		dAtA[i] = 0xa8
+		dAtA[i] = 0x10
 	}
-	if m.Metric != 0 {
-		i = encodeVarintAmp(dAtA, i, uint64(m.Metric))
-		i--
-		dAtA[i] = 0x1
+	if m.MinHeaderVersion != 0 {
+		i = encodeVarintAmp(dAtA, i, uint64(m.MinHeaderVersion))
 		i--
-		dAtA[i] = 0xa0
+		dAtA[i] = 0x8
 	}
-	if len(m.URL) > 0 {
-		i -= len(m.URL)
-		copy(dAtA[i:], m.URL)
-		i = encodeVarintAmp(dAtA, i, uint64(len(m.URL)))
-		i--
-		dAtA[i] = 0x7a
+	return len(dAtA) - i, nil
+}
+
+func (m *PinRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	if len(m.Text) > 0 {
-		i -= len(m.Text)
-		copy(dAtA[i:], m.Text)
-		i = encodeVarintAmp(dAtA, i, uint64(len(m.Text)))
-		i--
-		dAtA[i] = 0x6a
+	return dAtA[:n], nil
+}
+
+func (m *PinRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *PinRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.Tags != nil {
+		{
+			size, err := m.Tags.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
+	if m.StateSync != 0 {
+		i = encodeVarintAmp(dAtA, i, uint64(m.StateSync))
+		i--
+		dAtA[i] = 0x30
+	}
+	if len(m.PinAttrs) > 0 {
+		for iNdEx := len(m.PinAttrs) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.PinAttrs[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintAmp(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x22
+		}
+	}
+	if m.PinTarget != nil {
+		{
+			size, err := m.PinTarget.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintAmp(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x12
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *LaunchURL) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LaunchURL) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *LaunchURL) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if len(m.URL) > 0 {
+		i -= len(m.URL)
+		copy(dAtA[i:], m.URL)
+		i = encodeVarintAmp(dAtA, i, uint64(len(m.URL)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *Tag) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Tag) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *Tag) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.SizeZ != 0 {
+		i = encodeVarintAmp(dAtA, i, uint64(m.SizeZ))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb8
+	}
+	if m.SizeY != 0 {
+		i = encodeVarintAmp(dAtA, i, uint64(m.SizeY))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xb0
+	}
+	if m.SizeX != 0 {
+		i = encodeVarintAmp(dAtA, i, uint64(m.SizeX))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa8
+	}
+	if m.Metric != 0 {
+		i = encodeVarintAmp(dAtA, i, uint64(m.Metric))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa0
+	}
+	if len(m.URL) > 0 {
+		i -= len(m.URL)
+		copy(dAtA[i:], m.URL)
+		i = encodeVarintAmp(dAtA, i, uint64(len(m.URL)))
+		i--
+		dAtA[i] = 0x7a
+	}
+	if len(m.Text) > 0 {
+		i -= len(m.Text)
+		copy(dAtA[i:], m.Text)
+		i = encodeVarintAmp(dAtA, i, uint64(len(m.Text)))
+		i--
+		dAtA[i] = 0x6a
 	}
 	if len(m.UID) > 0 {
 		i -= len(m.UID)
@@ -2395,6 +2811,108 @@ func (this *LoginCheckpoint) Equal(that interface{}) bool {
 	}
 	return true
 }
+func (this *SessionResume) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*SessionResume)
+	if !ok {
+		that2, ok := that.(SessionResume)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Checkpoint.Equal(that1.Checkpoint) {
+		return false
+	}
+	if len(this.Pins) != len(that1.Pins) {
+		return false
+	}
+	for i := range this.Pins {
+		if !this.Pins[i].Equal(that1.Pins[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *ResumePin) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ResumePin)
+	if !ok {
+		that2, ok := that.(ResumePin)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.RequestID.Equal(that1.RequestID) {
+		return false
+	}
+	if !this.LastSyncedTx.Equal(that1.LastSyncedTx) {
+		return false
+	}
+	return true
+}
+func (this *Handshake) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Handshake)
+	if !ok {
+		that2, ok := that.(Handshake)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.MinHeaderVersion != that1.MinHeaderVersion {
+		return false
+	}
+	if this.MaxHeaderVersion != that1.MaxHeaderVersion {
+		return false
+	}
+	if len(this.Codecs) != len(that1.Codecs) {
+		return false
+	}
+	for i := range this.Codecs {
+		if this.Codecs[i] != that1.Codecs[i] {
+			return false
+		}
+	}
+	if len(this.SigningKits) != len(that1.SigningKits) {
+		return false
+	}
+	for i := range this.SigningKits {
+		if this.SigningKits[i] != that1.SigningKits[i] {
+			return false
+		}
+	}
+	return true
+}
 func (this *PinRequest) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
@@ -2684,41 +3202,84 @@ func (this *LoginCheckpoint) GoString() string {
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
-func (this *PinRequest) GoString() string {
+func (this *SessionResume) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 8)
-	s = append(s, "&amp.PinRequest{")
-	if this.PinTarget != nil {
-		s = append(s, "PinTarget: "+fmt.Sprintf("%#v", this.PinTarget)+",\n")
-	}
-	if this.PinAttrs != nil {
-		s = append(s, "PinAttrs: "+fmt.Sprintf("%#v", this.PinAttrs)+",\n")
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.SessionResume{")
+	if this.Checkpoint != nil {
+		s = append(s, "Checkpoint: "+fmt.Sprintf("%#v", this.Checkpoint)+",\n")
 	}
-	s = append(s, "StateSync: "+fmt.Sprintf("%#v", this.StateSync)+",\n")
-	if this.Tags != nil {
-		s = append(s, "Tags: "+fmt.Sprintf("%#v", this.Tags)+",\n")
+	if this.Pins != nil {
+		s = append(s, "Pins: "+fmt.Sprintf("%#v", this.Pins)+",\n")
 	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
-func (this *LaunchURL) GoString() string {
+func (this *ResumePin) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&amp.LaunchURL{")
-	s = append(s, "URL: "+fmt.Sprintf("%#v", this.URL)+",\n")
+	s := make([]string, 0, 6)
+	s = append(s, "&amp.ResumePin{")
+	if this.RequestID != nil {
+		s = append(s, "RequestID: "+fmt.Sprintf("%#v", this.RequestID)+",\n")
+	}
+	if this.LastSyncedTx != nil {
+		s = append(s, "LastSyncedTx: "+fmt.Sprintf("%#v", this.LastSyncedTx)+",\n")
+	}
 	s = append(s, "}")
 	return strings.Join(s, "")
 }
-func (this *Tag) GoString() string {
+func (this *Handshake) GoString() string {
 	if this == nil {
 		return "nil"
 	}
-	s := make([]string, 0, 15)
-	s = append(s, "&amp.Tag{")
+	s := make([]string, 0, 8)
+	s = append(s, "&amp.Handshake{")
+	s = append(s, "MinHeaderVersion: "+fmt.Sprintf("%#v", this.MinHeaderVersion)+",\n")
+	s = append(s, "MaxHeaderVersion: "+fmt.Sprintf("%#v", this.MaxHeaderVersion)+",\n")
+	s = append(s, "Codecs: "+fmt.Sprintf("%#v", this.Codecs)+",\n")
+	s = append(s, "SigningKits: "+fmt.Sprintf("%#v", this.SigningKits)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *PinRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&amp.PinRequest{")
+	if this.PinTarget != nil {
+		s = append(s, "PinTarget: "+fmt.Sprintf("%#v", this.PinTarget)+",\n")
+	}
+	if this.PinAttrs != nil {
+		s = append(s, "PinAttrs: "+fmt.Sprintf("%#v", this.PinAttrs)+",\n")
+	}
+	s = append(s, "StateSync: "+fmt.Sprintf("%#v", this.StateSync)+",\n")
+	if this.Tags != nil {
+		s = append(s, "Tags: "+fmt.Sprintf("%#v", this.Tags)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LaunchURL) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&amp.LaunchURL{")
+	s = append(s, "URL: "+fmt.Sprintf("%#v", this.URL)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Tag) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 15)
+	s = append(s, "&amp.Tag{")
 	s = append(s, "ID_0: "+fmt.Sprintf("%#v", this.ID_0)+",\n")
 	s = append(s, "ID_1: "+fmt.Sprintf("%#v", this.ID_1)+",\n")
 	s = append(s, "ID_2: "+fmt.Sprintf("%#v", this.ID_2)+",\n")
@@ -2915,6 +3476,71 @@ func (m *LoginCheckpoint) Size() (n int) {
 	return n
 }
 
+func (m *SessionResume) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Checkpoint != nil {
+		l = m.Checkpoint.Size()
+		n += 1 + l + sovAmp(uint64(l))
+	}
+	if len(m.Pins) > 0 {
+		for _, e := range m.Pins {
+			l = e.Size()
+			n += 1 + l + sovAmp(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *ResumePin) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.RequestID != nil {
+		l = m.RequestID.Size()
+		n += 1 + l + sovAmp(uint64(l))
+	}
+	if m.LastSyncedTx != nil {
+		l = m.LastSyncedTx.Size()
+		n += 1 + l + sovAmp(uint64(l))
+	}
+	return n
+}
+
+func (m *Handshake) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.MinHeaderVersion != 0 {
+		n += 1 + sovAmp(uint64(m.MinHeaderVersion))
+	}
+	if m.MaxHeaderVersion != 0 {
+		n += 1 + sovAmp(uint64(m.MaxHeaderVersion))
+	}
+	if len(m.Codecs) > 0 {
+		l = 0
+		for _, e := range m.Codecs {
+			l += sovAmp(uint64(e))
+		}
+		n += 1 + sovAmp(uint64(l)) + l
+	}
+	if len(m.SigningKits) > 0 {
+		l = 0
+		for _, e := range m.SigningKits {
+			l += sovAmp(uint64(e))
+		}
+		n += 1 + sovAmp(uint64(l)) + l
+	}
+	return n
+}
+
 func (m *PinRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -3130,6 +3756,46 @@ func (this *LoginCheckpoint) String() string {
 	}, "")
 	return s
 }
+func (this *SessionResume) String() string {
+	if this == nil {
+		return "nil"
+	}
+	repeatedStringForPins := "[]*ResumePin{"
+	for _, f := range this.Pins {
+		repeatedStringForPins += strings.Replace(f.String(), "ResumePin", "ResumePin", 1) + ","
+	}
+	repeatedStringForPins += "}"
+	s := strings.Join([]string{`&SessionResume{`,
+		`Checkpoint:` + strings.Replace(this.Checkpoint.String(), "LoginCheckpoint", "LoginCheckpoint", 1) + `,`,
+		`Pins:` + repeatedStringForPins + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ResumePin) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ResumePin{`,
+		`RequestID:` + strings.Replace(this.RequestID.String(), "Tag", "Tag", 1) + `,`,
+		`LastSyncedTx:` + strings.Replace(this.LastSyncedTx.String(), "Tag", "Tag", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Handshake) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Handshake{`,
+		`MinHeaderVersion:` + fmt.Sprintf("%v", this.MinHeaderVersion) + `,`,
+		`MaxHeaderVersion:` + fmt.Sprintf("%v", this.MaxHeaderVersion) + `,`,
+		`Codecs:` + fmt.Sprintf("%v", this.Codecs) + `,`,
+		`SigningKits:` + fmt.Sprintf("%v", this.SigningKits) + `,`,
+		`}`,
+	}, "")
+	return s
+}
 func (this *PinRequest) String() string {
 	if this == nil {
 		return "nil"
@@ -4154,6 +4820,474 @@ func (m *LoginCheckpoint) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *SessionResume) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SessionResume: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SessionResume: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Checkpoint", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Checkpoint == nil {
+				m.Checkpoint = &LoginCheckpoint{}
+			}
+			if err := m.Checkpoint.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Pins", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Pins = append(m.Pins, &ResumePin{})
+			if err := m.Pins[len(m.Pins)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ResumePin) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ResumePin: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ResumePin: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestID", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RequestID == nil {
+				m.RequestID = &Tag{}
+			}
+			if err := m.RequestID.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field LastSyncedTx", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthAmp
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthAmp
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.LastSyncedTx == nil {
+				m.LastSyncedTx = &Tag{}
+			}
+			if err := m.LastSyncedTx.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Handshake) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowAmp
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Handshake: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Handshake: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MinHeaderVersion", wireType)
+			}
+			m.MinHeaderVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MinHeaderVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxHeaderVersion", wireType)
+			}
+			m.MaxHeaderVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowAmp
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxHeaderVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType == 0 {
+				var v TxCodec
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAmp
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= TxCodec(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.Codecs = append(m.Codecs, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAmp
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthAmp
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthAmp
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				if elementCount != 0 && len(m.Codecs) == 0 {
+					m.Codecs = make([]TxCodec, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v TxCodec
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowAmp
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= TxCodec(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.Codecs = append(m.Codecs, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field Codecs", wireType)
+			}
+		case 4:
+			if wireType == 0 {
+				var v CryptoKitID
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAmp
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					v |= CryptoKitID(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				m.SigningKits = append(m.SigningKits, v)
+			} else if wireType == 2 {
+				var packedLen int
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowAmp
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					packedLen |= int(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				if packedLen < 0 {
+					return ErrInvalidLengthAmp
+				}
+				postIndex := iNdEx + packedLen
+				if postIndex < 0 {
+					return ErrInvalidLengthAmp
+				}
+				if postIndex > l {
+					return io.ErrUnexpectedEOF
+				}
+				var elementCount int
+				if elementCount != 0 && len(m.SigningKits) == 0 {
+					m.SigningKits = make([]CryptoKitID, 0, elementCount)
+				}
+				for iNdEx < postIndex {
+					var v CryptoKitID
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowAmp
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						v |= CryptoKitID(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					m.SigningKits = append(m.SigningKits, v)
+				}
+			} else {
+				return fmt.Errorf("proto: wrong wireType = %d for field SigningKits", wireType)
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipAmp(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthAmp
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *PinRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
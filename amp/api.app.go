@@ -14,7 +14,7 @@ type App struct {
 	AppSpec      tag.Spec // unique and persistent ID for this module
 	Desc         string   // human-readable description of this app
 	Version      string   // "v{MajorVers}.{MinorID}.{RevID}"
-	Dependencies []tag.ID // module Tags this app may access
+	Dependencies []tag.ID // AppSpec IDs of the apps this app may access via AppContext.Session()
 	Invocations  []string // additional aliases that invoke this app
 
 	// NewAppInstance is the instantiation entry point for an App called when an App is first invoked on a User session and is not yet running.
@@ -65,7 +65,8 @@ type Session interface {
 	// Returns the active media.Publisher instance for this session.
 	AssetPublisher() media.Publisher
 
-	// Returns info about this user and session
+	// Returns info about this user and session.
+	// If the Host has an Authenticator, this is the identity it verified (or a zero Login until then).
 	Login() Login
 
 	// Sends a readied Msg to the client for handling.
@@ -100,6 +101,9 @@ type Registry interface {
 
 	// Instantiates an attr element value for a given attr spec -- typically followed by tag.Value.Unmarshal()
 	MakeValue(attrSpec tag.ID) (tag.Value, error)
+
+	// Looks up the tag.Spec (and its canonic string) of a registered attr or element type.
+	GetAttrSpec(attrSpec tag.ID) (tag.Spec, error)
 }
 
 // Requester wraps a client request to receive a cell's state / updates.
@@ -120,7 +124,24 @@ type Requester interface {
 type Request struct {
 	PinRequest            // Raw client request
 	ID         tag.ID     // Universally unique genesis ID for this request
-	CommitTx   *TxMsg     // if non-nil, this tx is committed to be merged
+	CommitTx   *TxMsg     // if non-nil, this tx is committed to be merged; valid until the request completes
 	URL        *url.URL   // Initialized from PinRequest.PinTarget.URL (or nil if missing)
 	Values     url.Values // Initialized from PinRequest.PinTarget.URL (or nil if missing)
 }
+
+// Authenticator verifies the identity claimed by a client's Login using a challenge-response exchange:
+//
+//	STEP 1: the client sends a Login
+//	STEP 2: the host replies with the LoginChallenge returned by NewChallenge()
+//	STEP 3: the client replies with a LoginResponse, which the host passes to CheckResponse()
+//
+// An Authenticator must be concurrency safe since it is shared by all sessions of a Host.
+type Authenticator interface {
+
+	// NewChallenge returns a new challenge to send to the client that sent the given Login.
+	NewChallenge(login *Login) (*LoginChallenge, error)
+
+	// CheckResponse returns the verified identity of the client that sent the given Login, or an ErrCode_LoginFailed error
+	// if the given response does not answer the challenge it was sent.
+	CheckResponse(login *Login, challenge *LoginChallenge, response *LoginResponse) (Login, error)
+}
@@ -0,0 +1,28 @@
+// Package client offers a request multiplexer that sends requests to an amp.Host over any amp.Transport and routes each response to its request by ContextID.
+package client
+
+import (
+	"github.com/art-media-platform/amp-sdk-go/amp"
+)
+
+var (
+	// PinRequestAttr is the AttrID of the MetaNodeID attr that carries a PinRequest.
+	PinRequestAttr = amp.AttrSpec.With("PinRequest").ID
+
+	// ErrAttr is the AttrID of the MetaNodeID attr that carries an amp.Err for the request named by a TxMsg's ContextID.
+	ErrAttr = amp.AttrSpec.With("Err").ID
+)
+
+// Opts configures a Client.
+type Opts struct {
+
+	// Unrouted, if set, is called with each received TxMsg whose ContextID matches no open request (e.g. a session meta attr), which it then owns.
+	// If nil, such TxMsgs are released.  It is called from the Client's receive goroutine, so it should not block.
+	Unrouted func(tx *amp.TxMsg)
+
+	// Reconnect, if set, is called once the transport fails to dial a new transport, over which the Client resumes its session.
+	// Open requests then carry on, receiving what they missed (see amp.SessionResume).
+	// The session is only resumable once Login() is verified by a host having an Authenticator; if Reconnect returns an error or the host refuses to resume,
+	// the Client stops as it would with no Reconnect.  It is called from the Client's receive goroutine and may block.
+	Reconnect func() (amp.Transport, error)
+}
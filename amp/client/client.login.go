@@ -0,0 +1,67 @@
+package client
+
+import (
+	"github.com/art-media-platform/amp-sdk-go/amp"
+)
+
+// Login sends the given Login to the host and answers the host's LoginChallenge (if any) by signing it with the given private device key.
+// On success, the identity verified by the host is returned and its Checkpoint is retained to resume the session (see Opts.Reconnect);
+// otherwise the returned error is typically an ErrCode_LoginFailed error.
+// deviceKey may be nil if the host does not authenticate logins.
+func (c *Client) Login(login *amp.Login, deviceKey *amp.CryptoKey) (amp.Login, error) {
+	tx, err := amp.MarshalAttr(amp.MetaNodeID, amp.LoginAttr, login)
+	if err != nil {
+		return amp.Login{}, err
+	}
+	req, err := c.Send(tx)
+	if err != nil {
+		return amp.Login{}, err
+	}
+	defer req.Close()
+
+	for {
+		tx, err := req.Recv()
+		if err != nil {
+			return amp.Login{}, err
+		}
+		verified, done, err := answerLogin(req, tx, deviceKey)
+		tx.ReleaseRef()
+		if done && err == nil && verified.Checkpoint != nil {
+			c.mu.Lock()
+			c.checkpoint = verified.Checkpoint
+			c.mu.Unlock()
+		}
+		if done || err != nil {
+			return verified, err
+		}
+	}
+}
+
+// answerLogin handles a TxMsg received for a login request, returning done once the verified Login is received.
+func answerLogin(req *Request, tx *amp.TxMsg, deviceKey *amp.CryptoKey) (verified amp.Login, done bool, err error) {
+	if ops := tx.AttrItems(amp.MetaNodeID, amp.LoginAttr); ops.Next() {
+		err = ops.Load(&verified)
+		return verified, true, err
+	}
+
+	ops := tx.AttrItems(amp.MetaNodeID, amp.LoginChallengeAttr)
+	if !ops.Next() {
+		return amp.Login{}, false, nil
+	}
+	challenge := &amp.LoginChallenge{}
+	if err = ops.Load(challenge); err != nil {
+		return amp.Login{}, false, err
+	}
+	if deviceKey == nil {
+		return amp.Login{}, false, amp.ErrCode_LoginFailed.Error("host requires a device key")
+	}
+	response, err := challenge.Sign(deviceKey)
+	if err != nil {
+		return amp.Login{}, false, err
+	}
+	reply, err := amp.MarshalAttr(amp.MetaNodeID, amp.LoginResponseAttr, response)
+	if err != nil {
+		return amp.Login{}, false, err
+	}
+	return amp.Login{}, false, req.SendTx(reply)
+}
@@ -0,0 +1,390 @@
+package client
+
+import (
+	"sync"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// Client sends requests over an amp.Transport and routes each received TxMsg to the open Request whose ID matches the TxMsg's ContextID -- concurrency safe.
+type Client struct {
+	opts Opts
+	done chan struct{} // closed once the receive loop exits
+
+	mu         sync.Mutex
+	via        amp.Transport        // replaced when the session is resumed (see Opts.Reconnect)
+	reqs       map[tag.ID]*Request  // open requests by ID
+	err        error                // set once the receive loop exits
+	closing    bool                 // set once Close() is called
+	checkpoint *amp.LoginCheckpoint // issued by the host at login; used to resume the session
+	proto      *amp.Protocol        // negotiated with the host (see Connect); nil if no Handshake was exchanged
+}
+
+// NewClient returns a Client that sends and receives over the given transport, which it then owns.
+func NewClient(via amp.Transport, opts Opts) *Client {
+	return newClient(via, opts, nil)
+}
+
+// Connect exchanges Handshakes with the host over the given transport (see amp.ExchangeHandshake) and then returns a Client that sends and receives over it.
+// The transport is then owned by the returned Client, or closed if the exchange fails.
+//
+// Unlike NewClient, the Client then writes the negotiated TxHeader version and codecs, refuses to send a tx signed using a kit the host
+// did not agree to, and exchanges Handshakes again over each transport it resumes its session over (see Opts.Reconnect).
+func Connect(via amp.Transport, opts Opts) (*Client, error) {
+	proto, err := amp.ExchangeHandshake(via, amp.NewHandshake())
+	if err != nil {
+		via.Close()
+		return nil, err
+	}
+	return newClient(via, opts, &proto), nil
+}
+
+func newClient(via amp.Transport, opts Opts, proto *amp.Protocol) *Client {
+	c := &Client{
+		via:   via,
+		opts:  opts,
+		done:  make(chan struct{}),
+		reqs:  make(map[tag.ID]*Request),
+		proto: proto,
+	}
+	go c.recvLoop()
+	return c
+}
+
+// Protocol returns the Protocol negotiated with the host, or false if no Handshake was exchanged (see Connect).
+func (c *Client) Protocol() (amp.Protocol, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.proto == nil {
+		return amp.Protocol{}, false
+	}
+	return *c.proto, true
+}
+
+// checkSigned returns an error if the given tx is signed using a kit not negotiated with the host.
+func (c *Client) checkSigned(tx *amp.TxMsg) error {
+	if proto, ok := c.Protocol(); ok {
+		return proto.CheckSigned(tx)
+	}
+	return nil
+}
+
+// Pin sends the given PinRequest, returning the open Request that receives the host's responses.
+func (c *Client) Pin(pin *amp.PinRequest) (*Request, error) {
+	tx, err := amp.MarshalAttr(amp.MetaNodeID, PinRequestAttr, pin)
+	if err != nil {
+		return nil, err
+	}
+	tx.Status = amp.OpStatus_Syncing
+	return c.Send(tx)
+}
+
+// Send sends the given TxMsg (taking ownership of it) as a new request whose ID is the TxMsg's GenesisID.
+func (c *Client) Send(tx *amp.TxMsg) (*Request, error) {
+	reqID := tx.GenesisID()
+	if reqID.IsNil() {
+		tx.ReleaseRef()
+		return nil, amp.ErrCode_MalformedTx.Error("missing tx.GenesisID")
+	}
+	if err := c.checkSigned(tx); err != nil {
+		tx.ReleaseRef()
+		return nil, err
+	}
+
+	req := &Request{
+		ID:     reqID,
+		client: c,
+		status: amp.OpStatus_NotStarted,
+		signal: make(chan struct{}, 1),
+		synced: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		tx.ReleaseRef()
+		return nil, c.err
+	}
+	c.reqs[reqID] = req
+	c.mu.Unlock()
+
+	if err := c.transport().SendTx(tx); err != nil {
+		c.remove(req)
+		req.finish(err)
+		return nil, err
+	}
+	return req, nil
+}
+
+// Done returns a channel that is closed once this Client stops receiving, after which every request is complete.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns the error that stopped this Client (amp.ErrStreamClosed on a normal close), or nil if it is still receiving.
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// Close closes the underlying transport and blocks until every open request is complete.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	c.closing = true
+	via := c.via
+	c.mu.Unlock()
+
+	err := via.Close()
+	<-c.done
+	return err
+}
+
+// transport returns the current transport to the host.
+func (c *Client) transport() amp.Transport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.via
+}
+
+func (c *Client) recvLoop() {
+	defer close(c.done)
+
+	for via := c.transport(); ; {
+		tx, err := via.RecvTx()
+		if err != nil {
+			if via = c.resume(); via != nil {
+				continue
+			}
+			c.shutdown(err)
+			return
+		}
+		c.route(tx)
+	}
+}
+
+// route passes the given TxMsg to the open request named by its ContextID.
+func (c *Client) route(tx *amp.TxMsg) {
+	c.mu.Lock()
+	req := c.reqs[tx.ContextID()]
+	c.mu.Unlock()
+
+	switch {
+	case req != nil:
+		if req.push(tx) {
+			c.remove(req)
+		}
+	case c.opts.Unrouted != nil:
+		c.opts.Unrouted(tx)
+	default:
+		tx.ReleaseRef()
+	}
+}
+
+// shutdown completes every open request with the given error.
+func (c *Client) shutdown(err error) {
+	c.mu.Lock()
+	c.err = err
+	reqs := c.reqs
+	c.reqs = nil
+	c.mu.Unlock()
+
+	for _, req := range reqs {
+		req.finish(err)
+	}
+}
+
+func (c *Client) remove(req *Request) {
+	c.mu.Lock()
+	if c.reqs[req.ID] == req {
+		delete(c.reqs, req.ID)
+	}
+	c.mu.Unlock()
+}
+
+// Request is an open request sent by a Client, receiving the TxMsgs whose ContextID is the request's ID.
+//
+// A Request completes when the host sends OpStatus_Closed or an amp.Err, when it is closed by the client, or when its Client stops.
+type Request struct {
+	ID     tag.ID // GenesisID of the TxMsg that started this request
+	client *Client
+	signal chan struct{} // pinged when a TxMsg is queued or this request completes
+	synced chan struct{} // closed once OpStatus_Synced is received
+	done   chan struct{} // closed once this request completes
+
+	mu         sync.Mutex
+	queue      []*amp.TxMsg // received TxMsgs not yet returned by Recv
+	status     amp.OpStatus // latest status received
+	lastSynced tag.ID       // GenesisID of the latest OpStatus_Synced tx received
+	err        error        // set once this request completes
+}
+
+// Recv blocks until the next TxMsg of this request is received, returning it to the caller, who then owns it.
+// Once the request is complete and all received TxMsgs have been returned, Recv returns Err().
+// Recv is intended to be called from a single goroutine.
+func (req *Request) Recv() (*amp.TxMsg, error) {
+	for {
+		req.mu.Lock()
+		if len(req.queue) > 0 {
+			tx := req.queue[0]
+			req.queue[0] = nil
+			req.queue = req.queue[1:]
+			req.mu.Unlock()
+			return tx, nil
+		}
+		err := req.err
+		req.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+		<-req.signal
+	}
+}
+
+// Status returns the latest OpStatus received for this request.
+func (req *Request) Status() amp.OpStatus {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	return req.status
+}
+
+// Synced returns a channel that is closed once the host reports OpStatus_Synced (i.e. the client state is up to date).
+func (req *Request) Synced() <-chan struct{} {
+	return req.synced
+}
+
+// Done returns a channel that is closed once this request is complete.
+func (req *Request) Done() <-chan struct{} {
+	return req.done
+}
+
+// Err returns nil while this request is open.  Once complete, it returns amp.ErrRequestClosed if the request closed normally,
+// the amp.Err sent by the host, or the error that stopped the Client.
+func (req *Request) Err() error {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+	return req.err
+}
+
+// SendTx sends the given TxMsg (taking ownership of it) to the host as part of this request by setting its ContextID to this request's ID.
+func (req *Request) SendTx(tx *amp.TxMsg) error {
+	if err := req.Err(); err != nil {
+		tx.ReleaseRef()
+		return err
+	}
+	if err := req.client.checkSigned(tx); err != nil {
+		tx.ReleaseRef()
+		return err
+	}
+	tx.SetContextID(req.ID)
+	return req.client.transport().SendTx(tx)
+}
+
+// Close cancels this request by sending OpStatus_Closed to the host, releasing any TxMsgs not yet returned by Recv.
+// If this request is already complete, this is a no-op.
+func (req *Request) Close() error {
+	if !req.finish(amp.ErrRequestClosed) {
+		return nil
+	}
+	req.client.remove(req)
+
+	req.mu.Lock()
+	queue := req.queue
+	req.queue = nil
+	req.mu.Unlock()
+	for _, tx := range queue {
+		tx.ReleaseRef()
+	}
+
+	tx := amp.NewTxMsg(true)
+	tx.SetContextID(req.ID)
+	tx.Status = amp.OpStatus_Closed
+	return req.client.transport().SendTx(tx)
+}
+
+// push queues a TxMsg received for this request, returning true if it completed this request.
+func (req *Request) push(tx *amp.TxMsg) bool {
+	hostErr := readErr(tx)
+
+	req.mu.Lock()
+	if req.err != nil {
+		req.mu.Unlock()
+		tx.ReleaseRef()
+		return true
+	}
+	status := tx.Status
+	if status != amp.OpStatus_NotStarted {
+		req.status = status
+	}
+	if status == amp.OpStatus_Synced {
+		req.lastSynced = tx.GenesisID()
+	}
+	if hostErr == nil {
+		req.queue = append(req.queue, tx)
+	} else {
+		tx.ReleaseRef()
+	}
+	req.mu.Unlock()
+
+	if status == amp.OpStatus_Synced {
+		req.markSynced()
+	}
+	if hostErr != nil {
+		return req.finish(hostErr)
+	}
+	if status == amp.OpStatus_Closed {
+		return req.finish(amp.ErrRequestClosed)
+	}
+	req.ping()
+	return false
+}
+
+// finish completes this request with the given error, returning false if it was already complete.
+func (req *Request) finish(err error) bool {
+	req.mu.Lock()
+	if req.err != nil {
+		req.mu.Unlock()
+		return false
+	}
+	req.err = err
+	req.status = amp.OpStatus_Closed
+	req.mu.Unlock()
+
+	close(req.done)
+	req.ping()
+	return true
+}
+
+func (req *Request) markSynced() {
+	select {
+	case <-req.synced:
+	default:
+		close(req.synced)
+	}
+}
+
+func (req *Request) ping() {
+	select {
+	case req.signal <- struct{}{}:
+	default:
+	}
+}
+
+// readErr returns the amp.Err carried by the given TxMsg, if any.
+func readErr(tx *amp.TxMsg) error {
+	ops := tx.AttrItems(amp.MetaNodeID, ErrAttr)
+	if !ops.Next() {
+		return nil
+	}
+	hostErr := &amp.Err{}
+	if err := ops.Load(hostErr); err != nil {
+		return err
+	}
+	if hostErr.Code == amp.ErrCode_NoErr {
+		hostErr.Code = amp.ErrCode_UnnamedErr
+	}
+	return hostErr
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// resume dials a new transport using Opts.Reconnect and resumes the session over it, returning nil if the session can't be resumed.
+// Called from the receive loop once the current transport fails.
+func (c *Client) resume() amp.Transport {
+	c.mu.Lock()
+	checkpoint := c.checkpoint
+	handshake := c.proto != nil
+	closing := c.closing
+	reqs := make([]*Request, 0, len(c.reqs))
+	for _, req := range c.reqs {
+		reqs = append(reqs, req)
+	}
+	c.mu.Unlock()
+
+	if c.opts.Reconnect == nil || checkpoint == nil || closing {
+		return nil
+	}
+	via, err := c.opts.Reconnect()
+	if err != nil {
+		return nil
+	}
+	if handshake {
+		proto, err := amp.ExchangeHandshake(via, amp.NewHandshake())
+		if err != nil {
+			via.Close()
+			return nil
+		}
+		c.mu.Lock()
+		c.proto = &proto
+		c.mu.Unlock()
+	}
+
+	resume := &amp.SessionResume{
+		Checkpoint: checkpoint,
+	}
+	for _, req := range reqs {
+		req.mu.Lock()
+		lastSynced := req.lastSynced
+		req.mu.Unlock()
+
+		pin := &amp.ResumePin{
+			RequestID: tagFor(req.ID),
+		}
+		if lastSynced.IsSet() {
+			pin.LastSyncedTx = tagFor(lastSynced)
+		}
+		resume.Pins = append(resume.Pins, pin)
+	}
+	tx, err := amp.MarshalAttr(amp.MetaNodeID, amp.SessionResumeAttr, resume)
+	if err != nil {
+		via.Close()
+		return nil
+	}
+	resumeID := tx.GenesisID()
+
+	c.mu.Lock()
+	if c.closing {
+		c.mu.Unlock()
+		tx.ReleaseRef()
+		via.Close()
+		return nil
+	}
+	c.via = via
+	c.mu.Unlock()
+
+	if err = via.SendTx(tx); err == nil {
+		err = c.awaitResumed(via, resumeID)
+	}
+	if err != nil {
+		via.Close()
+		return nil
+	}
+	return via
+}
+
+// awaitResumed routes received TxMsgs until the host replies to the SessionResume with the given ID.
+func (c *Client) awaitResumed(via amp.Transport, resumeID tag.ID) error {
+	for {
+		tx, err := via.RecvTx()
+		if err != nil {
+			return err
+		}
+		if tx.ContextID() != resumeID {
+			c.route(tx)
+			continue
+		}
+
+		err = readErr(tx)
+		if ops := tx.AttrItems(amp.MetaNodeID, amp.LoginAttr); err == nil && ops.Next() {
+			var login amp.Login
+			if err = ops.Load(&login); err == nil && login.Checkpoint != nil {
+				c.mu.Lock()
+				c.checkpoint = login.Checkpoint
+				c.mu.Unlock()
+			}
+		}
+		tx.ReleaseRef()
+		return err
+	}
+}
+
+func tagFor(id tag.ID) *amp.Tag {
+	t := &amp.Tag{}
+	t.SetID(id)
+	return t
+}
@@ -0,0 +1,139 @@
+package client_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/client"
+	"github.com/art-media-platform/amp-sdk-go/amp/client/tests"
+	"github.com/art-media-platform/amp-sdk-go/amp/transport"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// serveTestHost answers each PinRequest received over via according to its target:
+//
+//	"ok"     -- sends a Syncing update, a Synced update, and then closes the request
+//	"fail"   -- sends an amp.Err
+//	"stream" -- sends a Synced update and leaves the request open
+//
+// A received OpStatus_Closed is echoed back to the client as an unrouted TxMsg so that the test can observe it.
+func serveTestHost(t *testing.T, via amp.Transport) {
+	reg := amp.NewRegistry()
+	amp.RegisterBuiltinTypes(reg)
+
+	send := func(reqID tag.ID, status amp.OpStatus, attrID tag.ID, val tag.Value) {
+		tx, err := amp.MarshalAttr(amp.MetaNodeID, attrID, val)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		tx.SetContextID(reqID)
+		tx.Status = status
+		via.SendTx(tx)
+	}
+
+	for {
+		tx, err := via.RecvTx()
+		if err != nil {
+			return
+		}
+		reqID := tx.GenesisID()
+		if tx.Status == amp.OpStatus_Closed {
+			reqID = tx.ContextID()
+			send(tag.ID{}, amp.OpStatus_Closed, tag.ID{}, &amp.Tag{UID: reqID.Base32()})
+			tx.ReleaseRef()
+			continue
+		}
+
+		val, err := tx.CheckMetaAttr(reg)
+		tx.ReleaseRef()
+		pin, _ := val.(*amp.PinRequest)
+		if err != nil || pin == nil {
+			t.Errorf("expected PinRequest, got %v %v", val, err)
+			continue
+		}
+
+		switch pin.PinTarget.URL {
+		case "ok":
+			send(reqID, amp.OpStatus_Syncing, tag.ID{}, &amp.Tag{Text: "first"})
+			send(reqID, amp.OpStatus_Synced, tag.ID{}, &amp.Tag{Text: "second"})
+			send(reqID, amp.OpStatus_Closed, tag.ID{}, &amp.Tag{Text: "last"})
+		case "fail":
+			send(reqID, amp.OpStatus_Closed, tag.ID{}, amp.ErrCellNotFound.(tag.Value))
+		case "stream":
+			send(reqID, amp.OpStatus_Synced, tag.ID{}, &amp.Tag{Text: "state"})
+		}
+	}
+}
+
+func TestClient(t *testing.T) {
+	a, b := transport.NewPipeTransport(transport.PipeOpts{})
+	go serveTestHost(t, b)
+
+	unrouted := make(chan *amp.TxMsg, 8)
+	c := client.NewClient(a, client.Opts{
+		Unrouted: func(tx *amp.TxMsg) { unrouted <- tx },
+	})
+
+	// requests are served concurrently and each receives only its own responses
+	ok := tests.Pin(t, c, "ok", amp.StateSync_Maintain)
+	fail := tests.Pin(t, c, "fail", amp.StateSync_Maintain)
+	stream := tests.Pin(t, c, "stream", amp.StateSync_Maintain)
+
+	for _, expect := range []string{"first", "second", "last"} {
+		if text := tests.RecvText(t, ok, amp.AttrSpec.With("Tag").ID); text != expect {
+			t.Fatalf("expected %q, got %q", expect, text)
+		}
+	}
+	tests.WaitFor(t, ok.Synced(), "Synced")
+	if _, err := ok.Recv(); err != amp.ErrRequestClosed {
+		t.Fatalf("expected ErrRequestClosed, got %v", err)
+	}
+	if ok.Status() != amp.OpStatus_Closed {
+		t.Fatalf("expected OpStatus_Closed, got %v", ok.Status())
+	}
+
+	// an amp.Err from the host surfaces as a Go error
+	tests.WaitFor(t, fail.Done(), "failed request")
+	if _, err := fail.Recv(); amp.GetErrCode(err) != amp.ErrCode_CellNotFound {
+		t.Fatalf("expected ErrCode_CellNotFound, got %v", err)
+	}
+
+	// closing a request sends OpStatus_Closed to the host
+	if text := tests.RecvText(t, stream, amp.AttrSpec.With("Tag").ID); text != "state" {
+		t.Fatalf("expected state, got %q", text)
+	}
+	if stream.Status() != amp.OpStatus_Synced || stream.Err() != nil {
+		t.Fatalf("expected open, synced request, got %v %v", stream.Status(), stream.Err())
+	}
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case tx := <-unrouted:
+		var val amp.Tag
+		if err := tx.LoadItem(amp.AttrSpec.With("Tag").ID, tag.ID{}, &val); err != nil || val.UID != stream.ID.Base32() {
+			t.Fatalf("host did not receive close of request %v", stream.ID)
+		}
+		tx.ReleaseRef()
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for host to receive close")
+	}
+	if _, err := stream.Recv(); err != amp.ErrRequestClosed {
+		t.Fatalf("expected ErrRequestClosed, got %v", err)
+	}
+
+	// closing the client completes open requests
+	open := tests.Pin(t, c, "stream", amp.StateSync_Maintain)
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tests.WaitFor(t, open.Done(), "open request")
+	if err := open.Err(); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+	if _, err := c.Pin(&amp.PinRequest{}); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+}
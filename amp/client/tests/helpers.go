@@ -0,0 +1,57 @@
+// Package tests holds helpers shared by tests that drive a client.Client.
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/client"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// Pin sends a PinRequest for the given URL, failing the test if it can't be sent.
+func Pin(t *testing.T, c *client.Client, url string, sync amp.StateSync) *client.Request {
+	t.Helper()
+	req, err := c.Pin(&amp.PinRequest{
+		PinTarget: &amp.Tag{URL: url},
+		StateSync: sync,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// RecvText receives the next TxMsg of the given request and returns the Text of the last amp.Tag it carries for the given attr.
+func RecvText(t *testing.T, req *client.Request, attrID tag.ID) string {
+	t.Helper()
+	tx, err := req.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tx.ReleaseRef()
+
+	var text string
+	for i, op := range tx.Ops {
+		if op.AttrID != attrID {
+			continue
+		}
+		var val amp.Tag
+		if err = tx.UnmarshalOpValue(i, &val); err != nil {
+			t.Fatal(err)
+		}
+		text = val.Text
+	}
+	return text
+}
+
+// WaitFor fails the test if the given channel is not closed (or sent to) within 5 seconds.
+func WaitFor(t *testing.T, ch <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timed out waiting for %s", what)
+	}
+}
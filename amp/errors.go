@@ -6,6 +6,9 @@ import (
 
 var (
 	ErrMalformedTx   = ErrCode_MalformedTx.Error("bad varint")
+	ErrTxHeader      = ErrCode_MalformedTx.Error("bad tx header")
+	ErrTxBounds      = ErrCode_MalformedTx.Error("tx field out of bounds")
+	ErrTxTooLarge    = ErrCode_MalformedTx.Error("tx exceeds max size")
 	ErrStreamClosed  = ErrCode_NotConnected.Error("stream closed")
 	ErrCellNotFound  = ErrCode_CellNotFound.Error("cell not found")
 	ErrRequestClosed = ErrCode_RequestClosed .Error("client request closed")
@@ -16,6 +19,11 @@ var (
 	ErrShuttingDown  = ErrCode_ShuttingDown.Error("shutting down")
 	ErrTimeout       = ErrCode_Timeout.Error("timeout")
 	ErrNoAuthToken   = ErrCode_AuthFailed.Error("no auth token")
+	ErrTxUnsigned    = ErrCode_AuthFailed.Error("tx is not signed")
+	ErrTxSignature   = ErrCode_AuthFailed.Error("tx signature verification failed")
+	ErrTxWrongSigner = ErrCode_AuthFailed.Error("tx not signed by the expected key")
+	ErrTxDecrypt     = ErrCode_DecryptFailed.Error("tx decryption failed")
+	ErrTxSealed      = ErrCode_DecryptFailed.Error("tx is sealed")
 )
 
 // Error makes our custom error type conform to a standard Go error
@@ -0,0 +1,69 @@
+// Package host offers a stock amp.Host and amp.Session, suitable for tests and small deployments.
+package host
+
+import (
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/store"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/media"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+const (
+	// DefaultResumeGrace is how long a suspended session awaits a SessionResume by default.
+	DefaultResumeGrace = time.Minute
+
+	// DefaultMaxResumeBacklog is the default max number of TxMsgs retained per request for replay on resume.
+	DefaultMaxResumeBacklog = 256
+)
+
+// Opts configures a Host.
+type Opts struct {
+	Label     string          // describes the Host for logging; if empty, "amp.Host" is used
+	Registry  amp.Registry    // apps and types available to each session; if nil, a registry holding amp's builtin types is used
+	DataPath  string          // root directory of each app's LocalDataPath(); if empty, apps have no local data directory
+	AppAttrs  store.Store     // holds each user's app attrs (see AppContext.GetAppAttr); if nil, an in-memory CellStore is used
+	Publisher media.Publisher // publishes session assets; if nil, AssetPublisher().PublishAsset returns an error
+
+	// Router routes each PinRequest by the scheme of its URL (see amp.URLRouter).
+	// If nil, only amp URLs (and targets given by ID) are served.
+	Router *amp.URLRouter
+
+	// Authenticator verifies each client Login (see amp.NewEd25519Authenticator).
+	// If set, a session refuses each PinRequest with an ErrCode_LoginFailed error until its client's Login is verified.
+	// If nil, a session adopts the Login a client sends as-is but, since the claim is unverified, issues no LoginCheckpoint for it.
+	Authenticator amp.Authenticator
+
+	// Once a session's Login is verified, it is issued a LoginCheckpoint and is suspended (rather than closed) if its transport fails.
+	// A suspended session awaits an amp.SessionResume bearing that checkpoint over a new transport for ResumeGrace before closing.
+	// If ResumeGrace <= 0, DefaultResumeGrace is used.
+	ResumeGrace time.Duration
+
+	// CommitPolicy, if set, decides whether each commit a client sends to an open request is accepted (see Session.commit).
+	// A refused commit is answered with the error returned by amp.CommitPolicy.CheckCommit and is not served.
+	CommitPolicy *amp.CommitPolicy
+
+	// MaxResumeBacklog is the max number of TxMsgs retained per request since its latest OpStatus_Synced tx, replayed on resume.
+	// A request that exceeds it is re-served in full on resume.  If <= 0, DefaultMaxResumeBacklog is used.
+	MaxResumeBacklog int
+
+	// SessionLimits bounds the resources each session may consume across all its apps.
+	SessionLimits Limits
+
+	// AppLimits bounds the resources each app (by AppSpec.ID) may consume within a session.
+	// An app without an entry is bounded by DefaultAppLimits.
+	AppLimits        map[tag.ID]Limits
+	DefaultAppLimits Limits
+}
+
+// Limits bounds the resources a client may consume, either across a session or per app within a session.
+// A zero field imposes no limit.  Each TxMsg received beyond a limit is rejected with the ErrCode noted below.
+// A TxMsg closing a request is always admitted so that a throttled client can still shed load.
+// An app's limits apply to the PinRequests that invoke it and to commits to its open pins.
+type Limits struct {
+	MaxPins        int     // max open pins; ErrCode_PinLimitReached
+	MaxTxPerSec    float64 // max TxMsgs received per second, allowing a one second burst; ErrCode_TxRateExceeded
+	MaxBytesPerSec int64   // max (estimated) bytes received per second, allowing a one second burst; ErrCode_ByteRateExceeded
+	MaxCommitSz    int64   // max DataStore size of a single TxMsg; ErrCode_CommitTooLarge
+}
@@ -0,0 +1,170 @@
+package host
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/store"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/media"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/task"
+)
+
+// appContext implements amp.AppContext for an app instance running within a Session.
+type appContext struct {
+	task.Context
+	media.Publisher
+	sess     *Session
+	view     *appSession // the Session as seen by this app
+	app      *amp.App
+	inst     amp.AppInstance
+	dataPath string
+}
+
+// startApp starts a child Context of this session and instantiates the given app within it.
+func (sess *Session) startApp(app *amp.App) (*appContext, error) {
+	actx := &appContext{
+		Publisher: sess.AssetPublisher(),
+		sess:      sess,
+		app:       app,
+	}
+	actx.view = &appSession{
+		Session: sess,
+		caller:  app,
+	}
+	if root := sess.host.opts.DataPath; root != "" {
+		actx.dataPath = filepath.Join(root, app.AppSpec.Canonic)
+		if err := os.MkdirAll(actx.dataPath, 0o700); err != nil {
+			return nil, amp.ErrCode_StorageFailure.Wrap(err)
+		}
+	}
+
+	var err error
+	actx.Context, err = sess.StartChild(&task.Task{
+		Info: task.Info{
+			Label: "app: " + app.AppSpec.Canonic,
+		},
+		OnClosing: func() {
+			sess.mu.Lock()
+			if sess.apps[app.AppSpec.ID] == actx {
+				delete(sess.apps, app.AppSpec.ID)
+			}
+			sess.mu.Unlock()
+
+			if actx.inst != nil {
+				actx.inst.OnClosing()
+			}
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	actx.inst, err = app.NewAppInstance(actx)
+	if err != nil {
+		actx.Close()
+		return nil, err
+	}
+	return actx, nil
+}
+
+// Implements amp.AppContext
+//
+// The returned Session only reaches apps this app declares in App.Dependencies.
+func (actx *appContext) Session() amp.Session {
+	return actx.view
+}
+
+// Implements amp.AppContext
+func (actx *appContext) LocalDataPath() string {
+	return actx.dataPath
+}
+
+// Implements amp.AppContext
+func (actx *appContext) GetAppAttr(attrSpec tag.ID, dst tag.Value) error {
+	entry, err := actx.sess.host.opts.AppAttrs.Get(actx.attrElement(attrSpec))
+	if err != nil {
+		return err
+	}
+	return entry.Load(dst)
+}
+
+// Implements amp.AppContext
+func (actx *appContext) PutAppAttr(attrSpec tag.ID, src tag.Value) error {
+	attrs := actx.sess.host.opts.AppAttrs
+	elemID := actx.attrElement(attrSpec)
+
+	var predEditID tag.ID
+	if prev, err := attrs.Get(elemID); err == nil {
+		predEditID = prev.EditID
+	} else if err != store.ErrElementNotFound {
+		return err
+	}
+
+	tx := amp.NewTxMsg(true)
+	defer tx.ReleaseRef()
+	if err := tx.Revise(elemID[0], elemID[1], elemID[2], predEditID, src); err != nil {
+		return err
+	}
+	return attrs.Apply(tx)
+}
+
+// attrElement returns the ElementID of an app attr, scoped by both the app and the session's user.
+func (actx *appContext) attrElement(attrSpec tag.ID) amp.ElementID {
+	cellID := actx.app.AppSpec.ID
+	if login := actx.sess.Login(); login.UserID != nil {
+		cellID = cellID.With(login.UserID.AsID())
+	}
+	return amp.ElementID{cellID, attrSpec, {}}
+}
+
+// appSession is the view of a Session given to an app instance, which only reaches the app itself and the apps listed in its App.Dependencies.
+// Undeclared access is logged and fails with ErrCode_InsufficientPermissions, allowing third-party apps to be sandboxed within a shared host.
+type appSession struct {
+	*Session
+	caller *amp.App
+}
+
+// Implements amp.Session
+func (view *appSession) GetAppInstance(appID tag.ID, autoCreate bool) (amp.AppInstance, error) {
+	if err := view.checkAccess(appID); err != nil {
+		return nil, err
+	}
+	return view.Session.GetAppInstance(appID, autoCreate)
+}
+
+// ServeRequest routes the given request to the app its target invokes, allowing an app to pin cells served by the apps it depends on.
+//
+// Implements amp.Pinner
+func (view *appSession) ServeRequest(op amp.Requester) (amp.Pin, error) {
+	route, err := view.route(op.Request())
+	if err != nil {
+		return nil, err
+	}
+	if route.App == nil {
+		return route.Handler.ServeRequest(op)
+	}
+	inst, err := view.GetAppInstance(route.App.AppSpec.ID, true)
+	if err != nil {
+		return nil, err
+	}
+	if err = inst.MakeReady(op); err != nil {
+		return nil, err
+	}
+	return inst.ServeRequest(op)
+}
+
+// checkAccess returns an ErrCode_InsufficientPermissions error if the calling app has not declared the given app as a dependency.
+func (view *appSession) checkAccess(appID tag.ID) error {
+	if appID == view.caller.AppSpec.ID {
+		return nil
+	}
+	for _, dep := range view.caller.Dependencies {
+		if dep == appID {
+			return nil
+		}
+	}
+	view.Log().Warnf("app %s denied access to undeclared dependency %s", view.caller.AppSpec.Canonic, appID.String())
+	return amp.ErrCode_InsufficientPermissions.Errorf("app %s does not declare %s as a dependency", view.caller.AppSpec.Canonic, appID.String())
+}
@@ -0,0 +1,201 @@
+package host
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"sync"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/store"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/media"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/task"
+)
+
+// Host is a stock amp.Host that owns a Registry and serves a Session for each transport given to StartNewSession().
+type Host struct {
+	task.Context
+	opts Opts
+
+	mu        sync.Mutex
+	resumable map[string]*Session // sessions by the AccessToken of their LoginCheckpoint
+}
+
+// Start starts a new Host with no parent Context.  Closing the Host closes its services and sessions.
+func Start(opts Opts) (*Host, error) {
+	if opts.Label == "" {
+		opts.Label = "amp.Host"
+	}
+	if opts.Registry == nil {
+		opts.Registry = amp.NewRegistry()
+		amp.RegisterBuiltinTypes(opts.Registry)
+	}
+	if opts.AppAttrs == nil {
+		opts.AppAttrs = store.NewCellStore()
+	}
+	if opts.Publisher == nil {
+		opts.Publisher = noPublisher{}
+	}
+	if opts.ResumeGrace <= 0 {
+		opts.ResumeGrace = DefaultResumeGrace
+	}
+	if opts.MaxResumeBacklog <= 0 {
+		opts.MaxResumeBacklog = DefaultMaxResumeBacklog
+	}
+
+	host := &Host{
+		opts:      opts,
+		resumable: make(map[string]*Session),
+	}
+	_, err := task.Start(&task.Task{
+		Info: task.Info{
+			Label: opts.Label,
+		},
+		OnStart: func(ctx task.Context) error {
+			host.Context = ctx
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return host, nil
+}
+
+// Implements amp.Host
+func (host *Host) HostRegistry() amp.Registry {
+	return host.opts.Registry
+}
+
+// StartService starts the given HostService as a child of this Host.
+func (host *Host) StartService(svc amp.HostService) error {
+	return svc.StartService(host)
+}
+
+// Implements amp.Host
+//
+// The new Session is a child of the given HostService (or of this Host if parent is nil) and takes ownership of the transport,
+// which is closed if the session fails to start.
+func (host *Host) StartNewSession(parent amp.HostService, via amp.Transport) (amp.Session, error) {
+	sess := &Session{
+		host:     host,
+		via:      via,
+		Registry: amp.NewRegistry(),
+		apps:     make(map[tag.ID]*appContext),
+		reqs:     make(map[tag.ID]*request),
+		resumed:  make(chan *resumeOp, 1),
+		lim:      newLimiter(host.opts.SessionLimits),
+		appLims:  make(map[tag.ID]*limiter),
+	}
+	if err := sess.Registry.Import(host.opts.Registry); err != nil {
+		via.Close()
+		return nil, err
+	}
+
+	var parentCtx task.Context = host
+	if parent != nil {
+		parentCtx = parent
+	}
+
+	// Counted before the session starts since a client that hangs up at once closes it (and so decrements sessionsOpen) before StartChild returns.
+	sessionsOpen.Inc()
+	_, err := parentCtx.StartChild(&task.Task{
+		Info: task.Info{
+			Label: "session: " + via.Label(),
+		},
+		OnStart: func(ctx task.Context) error {
+			sess.Context = ctx
+			return nil
+		},
+		OnRun: func(ctx task.Context) {
+			sess.recvLoop()
+		},
+		OnClosing: func() {
+			sess.onClosing()
+		},
+		OnClosed: func() {
+			sessionsOpen.Dec()
+		},
+	})
+	if err != nil {
+		sessionsOpen.Dec()
+		via.Close()
+		return nil, err
+	}
+	sessionsStarted.Inc()
+	return sess, nil
+}
+
+// SessionLimits returns the Limits applied to each session (see Opts.SessionLimits).
+func (host *Host) SessionLimits() Limits {
+	return host.opts.SessionLimits
+}
+
+// AppLimits returns the Limits applied to the given app within each session (see Opts.AppLimits).
+func (host *Host) AppLimits(appID tag.ID) Limits {
+	if limits, ok := host.opts.AppLimits[appID]; ok {
+		return limits
+	}
+	return host.opts.DefaultAppLimits
+}
+
+// issueCheckpoint issues the given session a new LoginCheckpoint, allowing its client to resume it (see Opts.ResumeGrace).
+func (host *Host) issueCheckpoint(sess *Session) (*amp.LoginCheckpoint, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return nil, amp.ErrCode_InternalErr.Wrap(err)
+	}
+	checkpoint := &amp.LoginCheckpoint{
+		TokenType:   "amp.session",
+		AccessToken: base64.RawURLEncoding.EncodeToString(token),
+	}
+
+	sess.mu.Lock()
+	prev := sess.checkpoint
+	sess.checkpoint = checkpoint
+	sess.mu.Unlock()
+
+	host.mu.Lock()
+	if prev != nil {
+		delete(host.resumable, prev.AccessToken)
+	}
+	host.resumable[checkpoint.AccessToken] = sess
+	host.mu.Unlock()
+	return checkpoint, nil
+}
+
+// forgetCheckpoint removes the given session's checkpoint so it can no longer be resumed.
+func (host *Host) forgetCheckpoint(sess *Session) {
+	sess.mu.Lock()
+	checkpoint := sess.checkpoint
+	sess.mu.Unlock()
+
+	if checkpoint != nil {
+		host.mu.Lock()
+		if host.resumable[checkpoint.AccessToken] == sess {
+			delete(host.resumable, checkpoint.AccessToken)
+		}
+		host.mu.Unlock()
+	}
+}
+
+// resumeSession passes the given transport to the session named by op's checkpoint.
+func (host *Host) resumeSession(op *resumeOp) error {
+	var target *Session
+	if token := op.resume.Checkpoint.GetAccessToken(); token != "" {
+		host.mu.Lock()
+		target = host.resumable[token]
+		host.mu.Unlock()
+	}
+	if target == nil {
+		return amp.ErrCode_SessionExpired.Error("session not found or expired")
+	}
+	return target.acceptResume(op)
+}
+
+// noPublisher is the media.Publisher of a Host given no Opts.Publisher.
+type noPublisher struct{}
+
+func (noPublisher) PublishAsset(asset media.Asset, opts media.PublishOpts) (string, error) {
+	return "", amp.ErrCode_Unimplemented.Error("host has no asset publisher")
+}
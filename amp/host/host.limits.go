@@ -0,0 +1,129 @@
+package host
+
+import (
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// txOpSzEstimate is the estimated marshalled size of a TxOp, used to debit a TxMsg's size without re-marshalling it.
+const txOpSzEstimate = 24
+
+// limiter enforces a Limits on the TxMsgs a session (or an app within a session) receives.
+// Only accessed from the session's receive loop.
+type limiter struct {
+	Limits
+	txs   rateBucket
+	bytes rateBucket
+}
+
+func newLimiter(limits Limits) *limiter {
+	return &limiter{
+		Limits: limits,
+		txs:    rateBucket{rate: limits.MaxTxPerSec},
+		bytes:  rateBucket{rate: float64(limits.MaxBytesPerSec)},
+	}
+}
+
+// admit debits the given TxMsg against this limiter, returning an error naming the first limit it exceeds.
+func (lim *limiter) admit(tx *amp.TxMsg, now time.Time) error {
+	if lim.MaxCommitSz > 0 && int64(len(tx.DataStore)) > lim.MaxCommitSz {
+		return amp.ErrCode_CommitTooLarge.Errorf("commit of %d bytes exceeds limit of %d", len(tx.DataStore), lim.MaxCommitSz)
+	}
+	if !lim.txs.take(1, now) {
+		return amp.ErrCode_TxRateExceeded.Errorf("exceeded %v TxMsgs per second", lim.MaxTxPerSec)
+	}
+	if !lim.bytes.take(float64(txSz(tx)), now) {
+		return amp.ErrCode_ByteRateExceeded.Errorf("exceeded %d bytes per second", lim.MaxBytesPerSec)
+	}
+	return nil
+}
+
+// admitCommit applies the limits of the app serving the given request (if any) to a commit to that request's Pin.
+func (sess *Session) admitCommit(target *request, tx *amp.TxMsg) error {
+	if target.appID.IsNil() {
+		return nil // a handler has no app limits
+	}
+	return sess.appLimiter(target.appID).admit(tx, time.Now())
+}
+
+// txSz returns the estimated marshalled size of the given TxMsg.
+func txSz(tx *amp.TxMsg) int {
+	return int(amp.Const_TxHeader_Size) + len(tx.Ops)*txOpSzEstimate + len(tx.DataStore)
+}
+
+// rateBucket is a token bucket that refills at rate tokens per second and holds at most one second's worth.
+// If rate <= 0, it never runs dry.
+type rateBucket struct {
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// take removes n tokens from this bucket, returning false (and leaving it as is) if it holds fewer than n.
+func (b *rateBucket) take(n float64, now time.Time) bool {
+	if b.rate <= 0 {
+		return true
+	}
+	if b.last.IsZero() {
+		b.tokens = b.rate
+	} else if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = min(b.rate, b.tokens+elapsed*b.rate)
+	}
+	b.last = now
+	if b.tokens < n {
+		return false
+	}
+	b.tokens -= n
+	return true
+}
+
+// appLimiter returns the limiter for the given app within this session, creating it if needed.
+func (sess *Session) appLimiter(appID tag.ID) *limiter {
+	lim := sess.appLims[appID]
+	if lim == nil {
+		lim = newLimiter(sess.host.AppLimits(appID))
+		sess.appLims[appID] = lim
+	}
+	return lim
+}
+
+// admitPin applies the limits of this session and of the app serving the given route (if any) to a PinRequest and, if admitted, registers req as open.
+// The app's rate limits are only debited once the pin limits admit the request.
+func (sess *Session) admitPin(req *request, route amp.Route, tx *amp.TxMsg) error {
+	var appID tag.ID
+	label := route.Scheme.String()
+	lim := &limiter{} // a handler has no app limits
+	if route.App != nil {
+		appID = route.App.AppSpec.ID
+		label = route.App.AppSpec.Canonic
+		lim = sess.appLimiter(appID)
+	}
+
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	if max := sess.lim.MaxPins; max > 0 && len(sess.reqs) >= max {
+		return amp.ErrCode_PinLimitReached.Errorf("session has %d open pins", len(sess.reqs))
+	}
+	if max := lim.MaxPins; max > 0 {
+		open := 0
+		for _, other := range sess.reqs {
+			if other.appID == appID {
+				open++
+			}
+		}
+		if open >= max {
+			return amp.ErrCode_PinLimitReached.Errorf("app has %d open pins", open)
+		}
+	}
+	if err := lim.admit(tx, time.Now()); err != nil {
+		return err
+	}
+	req.appID = appID
+	req.pins = pinsOpen.With(label)
+	req.pins.Inc()
+	sess.reqs[req.params.ID] = req
+	return nil
+}
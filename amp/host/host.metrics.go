@@ -0,0 +1,25 @@
+package host
+
+import (
+	"github.com/art-media-platform/amp-sdk-go/stdlib/metrics"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/task"
+)
+
+// Host metrics, served by metrics.Handler()
+var (
+	sessionsOpen    = metrics.Default.Gauge("amp_host_sessions", "Sessions currently open, including suspended sessions.")
+	sessionsStarted = metrics.Default.Counter("amp_host_sessions_started_total", "Sessions started.")
+	sessionsResumed = metrics.Default.Counter("amp_host_sessions_resumed_total", "Suspended sessions resumed by their client.")
+	pinsOpen        = metrics.Default.GaugeVec("amp_host_pins", "Pins currently open, by app.", "app")
+)
+
+func init() {
+	metrics.Default.GaugeFunc("amp_task_contexts", "task.Contexts currently open.", func() float64 {
+		_, open := task.Counts()
+		return float64(open)
+	})
+	metrics.Default.CounterFunc("amp_task_contexts_started_total", "task.Contexts started.", func() float64 {
+		started, _ := task.Counts()
+		return float64(started)
+	})
+}
@@ -0,0 +1,222 @@
+package host
+
+import (
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// resumeOp is a client's SessionResume received over a new transport.
+type resumeOp struct {
+	via    amp.Transport      // new transport to the client
+	proto  *amp.Protocol      // negotiated over via, if the client sent a Handshake
+	reqID  tag.ID             // ID of the SessionResume request
+	resume *amp.SessionResume // names the session and its requests to resume
+}
+
+// handOff passes this session's transport to the session named by the given SessionResume and then closes this session.
+// If that session can't be resumed, an error is sent to the client and this session carries on.
+func (sess *Session) handOff(reqID tag.ID, resume *amp.SessionResume) {
+	sess.mu.Lock()
+	via := sess.via
+	proto := sess.proto
+	sess.via = nil // detach so that closing this session leaves the transport open
+	sess.mu.Unlock()
+
+	err := sess.host.resumeSession(&resumeOp{
+		via:    via,
+		proto:  proto,
+		reqID:  reqID,
+		resume: resume,
+	})
+	if err != nil {
+		sess.mu.Lock()
+		sess.via = via
+		sess.mu.Unlock()
+		sess.sendErr(reqID, err)
+	}
+}
+
+// acceptResume queues the given resume for this session's receive loop, closing this session's current transport (if any).
+func (sess *Session) acceptResume(op *resumeOp) error {
+	sess.mu.Lock()
+	select {
+	case <-sess.Closing():
+		sess.mu.Unlock()
+		return amp.ErrCode_SessionExpired.Error("session closed")
+	default:
+	}
+	select {
+	case sess.resumed <- op:
+	default:
+		sess.mu.Unlock()
+		return amp.ErrCode_SessionExpired.Error("session is already resuming")
+	}
+	prev := sess.via
+	sess.via = nil
+	sess.mu.Unlock()
+
+	if prev != nil {
+		prev.Close() // the receive loop then picks up op
+	}
+	return nil
+}
+
+// suspend is called once this session's transport fails.  If this session can be resumed, it holds each open request
+// and blocks until the client resumes this session (returning the new transport) or Opts.ResumeGrace elapses (returning nil).
+func (sess *Session) suspend() amp.Transport {
+	sess.mu.Lock()
+	prev := sess.via
+	sess.via = nil
+	resumable := sess.checkpoint != nil
+	reqs := make([]*request, 0, len(sess.reqs))
+	for _, req := range sess.reqs {
+		reqs = append(reqs, req)
+	}
+	sess.mu.Unlock()
+
+	if prev != nil {
+		prev.Close()
+	}
+	if !resumable {
+		return nil
+	}
+	for _, req := range reqs {
+		req.mu.Lock()
+		req.held = true
+		req.mu.Unlock()
+	}
+
+	timer := time.NewTimer(sess.host.opts.ResumeGrace)
+	defer timer.Stop()
+
+	select {
+	case op := <-sess.resumed:
+		sess.resume(op)
+		return op.via
+	case <-timer.C:
+		sess.Log().Infof(1, "not resumed within %v", sess.host.opts.ResumeGrace)
+	case <-sess.Closing():
+	}
+	return nil
+}
+
+// resume adopts the given transport, replies with this session's Login, and then brings each request named by the client up to date.
+// Open requests not named by the client are closed.
+func (sess *Session) resume(op *resumeOp) {
+	sess.mu.Lock()
+	sess.via = op.via
+	sess.proto = op.proto
+	login := sess.login
+	login.Checkpoint = sess.checkpoint
+	reqs := make(map[tag.ID]*request, len(sess.reqs))
+	for reqID, req := range sess.reqs {
+		reqs[reqID] = req
+	}
+	sess.mu.Unlock()
+
+	sessionsResumed.Inc()
+	if err := amp.SendMetaAttr(sess, op.reqID, amp.OpStatus_Closed, amp.LoginAttr, &login); err != nil {
+		sess.Log().Warnf("resume: %v", err)
+	}
+
+	for _, pin := range op.resume.Pins {
+		reqID := asID(pin.RequestID)
+		req := reqs[reqID]
+		if req == nil {
+			sess.sendErr(reqID, amp.ErrCode_RequestNotFound.Error("request not found"))
+			continue
+		}
+		delete(reqs, reqID)
+		if !req.replay(asID(pin.LastSyncedTx)) {
+			sess.reserve(req)
+		}
+	}
+	for _, req := range reqs {
+		req.cancel()
+	}
+}
+
+// replay re-sends the TxMsgs pushed since the given Synced tx and resumes sending, returning false if they are not all retained.
+func (req *request) replay(lastSynced tag.ID) bool {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	if req.done {
+		return true
+	}
+	if req.overflow || req.lastSynced != lastSynced {
+		return false
+	}
+	for _, tx := range req.backlog {
+		tx.AddRef()
+		if err := req.sess.SendTx(tx); err != nil {
+			break
+		}
+	}
+	req.held = false
+	return true
+}
+
+// reserve retires the given request and its Pin and serves the same request anew so that its state is re-sent in full.
+func (sess *Session) reserve(req *request) {
+	req.mu.Lock()
+	if req.done {
+		req.mu.Unlock()
+		return
+	}
+	req.done = true // the retired Pin's completion is then a no-op
+	pin := req.pin
+	req.releaseBacklog()
+	req.mu.Unlock()
+
+	fresh := &request{
+		sess:   sess,
+		params: req.params,
+		appID:  req.appID,
+		pins:   req.pins,
+	}
+	sess.mu.Lock()
+	sess.reqs[req.params.ID] = fresh
+	sess.mu.Unlock()
+
+	if pin != nil {
+		pin.Context().Close()
+	}
+	if req.server == nil {
+		fresh.OnComplete(amp.ErrCode_RequestNotFound.Error("request was never served"))
+		return
+	}
+	sess.serve(fresh, req.server)
+}
+
+// onClosing closes this session's transport (and that of any pending resume) so it can no longer be resumed.
+func (sess *Session) onClosing() {
+	sess.host.forgetCheckpoint(sess)
+
+	sess.mu.Lock()
+	via := sess.via
+	sess.via = nil
+	var pending *resumeOp
+	select {
+	case pending = <-sess.resumed:
+	default:
+	}
+	sess.mu.Unlock()
+
+	if via != nil {
+		via.Close()
+	}
+	if pending != nil {
+		pending.via.Close()
+	}
+}
+
+// asID returns the tag.ID carried by the given Tag, or a nil ID if the Tag is nil.
+func asID(t *amp.Tag) tag.ID {
+	if t == nil {
+		return tag.ID{}
+	}
+	return t.AsID()
+}
@@ -0,0 +1,574 @@
+package host
+
+import (
+	"sync"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/media"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/metrics"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/task"
+)
+
+// Session is a stock amp.Session, serving the client on the other end of its transport.
+//
+// Each app instance runs as a child Context of its Session and each Pin as a child of its app instance,
+// so closing a Session closes its app instances, which closes their Pins.
+type Session struct {
+	task.Context
+	amp.Registry // imported from the Host registry when the session starts
+
+	host    *Host
+	resumed chan *resumeOp // receives the transport of a client resuming this session
+
+	appsMu sync.Mutex // serializes app instance creation
+
+	lim     *limiter            // enforces Opts.SessionLimits; receive loop only
+	appLims map[tag.ID]*limiter // enforces Opts.AppLimits by AppSpec.ID; receive loop only
+
+	mu         sync.Mutex
+	via        amp.Transport          // nil while suspended
+	proto      *amp.Protocol          // negotiated by the client's Handshake over via (see amp.ExchangeHandshake); nil if none
+	login      amp.Login              // verified identity (see Opts.Authenticator)
+	verified   bool                   // set once the Authenticator verifies login
+	pending    *pendingLogin          // login awaiting the client's LoginResponse
+	checkpoint *amp.LoginCheckpoint   // allows the client to resume this session; issued at login
+	apps       map[tag.ID]*appContext // running app instances by AppSpec.ID
+	reqs       map[tag.ID]*request    // open requests by ID
+}
+
+// pendingLogin is a Login whose LoginChallenge has been sent to the client.
+type pendingLogin struct {
+	reqID     tag.ID
+	login     *amp.Login
+	challenge *amp.LoginChallenge
+}
+
+// Implements amp.Session
+func (sess *Session) AssetPublisher() media.Publisher {
+	return sess.host.opts.Publisher
+}
+
+// Implements amp.Session
+func (sess *Session) Login() amp.Login {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.login
+}
+
+// Implements amp.Session
+func (sess *Session) SendTx(tx *amp.TxMsg) error {
+	via := sess.transport()
+	if via == nil {
+		tx.ReleaseRef()
+		return amp.ErrCode_NotConnected.Error("session is suspended")
+	}
+	return via.SendTx(tx)
+}
+
+// transport returns the transport to the client, or nil if this session is suspended.
+func (sess *Session) transport() amp.Transport {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.via
+}
+
+// authorized returns true if this session's client may pin and commit: the host has no Authenticator or it verified the client's Login.
+func (sess *Session) authorized() bool {
+	if sess.host.opts.Authenticator == nil {
+		return true
+	}
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.verified
+}
+
+// resumable returns true if this session has issued a LoginCheckpoint and so could be resumed.
+func (sess *Session) resumable() bool {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.checkpoint != nil
+}
+
+// Implements amp.Session
+func (sess *Session) GetAppInstance(appID tag.ID, autoCreate bool) (amp.AppInstance, error) {
+	if !autoCreate {
+		return sess.runningApp(appID)
+	}
+
+	sess.appsMu.Lock()
+	defer sess.appsMu.Unlock()
+
+	if inst, err := sess.runningApp(appID); err == nil {
+		return inst, nil
+	}
+	app, err := sess.GetAppByTag(appID)
+	if err != nil {
+		return nil, err
+	}
+	if app.NewAppInstance == nil {
+		return nil, amp.ErrCode_AppNotFound.Errorf("app %s has no NewAppInstance", app.AppSpec.Canonic)
+	}
+
+	actx, err := sess.startApp(app)
+	if err != nil {
+		return nil, err
+	}
+	sess.mu.Lock()
+	sess.apps[appID] = actx
+	sess.mu.Unlock()
+	return actx.inst, nil
+}
+
+func (sess *Session) runningApp(appID tag.ID) (amp.AppInstance, error) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+
+	if app := sess.apps[appID]; app != nil {
+		return app.inst, nil
+	}
+	return nil, amp.ErrCode_AppNotFound.Errorf("app %s is not running", appID.String())
+}
+
+// recvLoop handles each TxMsg received from the client until the transport closes and this session is not resumed, then closes this session.
+func (sess *Session) recvLoop() {
+	for via := sess.transport(); via != nil; via = sess.suspend() {
+		for sess.transport() == via {
+			tx, err := via.RecvTx()
+			if err != nil {
+				if err != amp.ErrStreamClosed && sess.transport() == via {
+					sess.Log().Warnf("RecvTx: %v", err)
+				}
+				break
+			}
+			sess.handleTx(tx)
+			tx.ReleaseRef()
+		}
+	}
+	sess.Close()
+}
+
+func (sess *Session) handleTx(tx *amp.TxMsg) {
+	if tx.Status == amp.OpStatus_Closed {
+		sess.closeRequest(tx.ContextID())
+		return
+	}
+
+	reqID := tx.GenesisID()
+	if err := sess.lim.admit(tx, time.Now()); err != nil {
+		sess.sendErr(reqID, err)
+		return
+	}
+	val, err := tx.CheckMetaAttr(sess)
+	if err != nil {
+		sess.sendErr(reqID, err)
+		return
+	}
+
+	switch v := val.(type) {
+	case *amp.PinRequest:
+		sess.servePin(tx, v)
+	case *amp.Login:
+		sess.startLogin(reqID, v)
+	case *amp.LoginResponse:
+		sess.finishLogin(tx.ContextID(), v)
+	case *amp.SessionResume:
+		sess.handOff(reqID, v)
+	case *amp.Handshake:
+		sess.handshake(v)
+	case nil:
+		sess.commit(tx)
+	default:
+		sess.sendErr(reqID, amp.ErrCode_UnsupportedOp.Error("unsupported tx"))
+	}
+}
+
+// startLogin starts the login exchange (see amp.Authenticator), replying with a LoginChallenge.
+// If the host has no Authenticator, the given Login is adopted as-is.
+// Otherwise, the session is unauthenticated until the client answers the challenge.
+func (sess *Session) startLogin(reqID tag.ID, login *amp.Login) {
+	auth := sess.host.opts.Authenticator
+	if auth == nil {
+		sess.setLogin(reqID, *login, false)
+		return
+	}
+
+	sess.mu.Lock()
+	sess.login = amp.Login{}
+	sess.verified = false
+	sess.mu.Unlock()
+
+	challenge, err := auth.NewChallenge(login)
+	if err != nil {
+		sess.sendErr(reqID, err)
+		return
+	}
+	sess.mu.Lock()
+	sess.pending = &pendingLogin{
+		reqID:     reqID,
+		login:     login,
+		challenge: challenge,
+	}
+	sess.mu.Unlock()
+
+	if err = amp.SendMetaAttr(sess, reqID, amp.OpStatus_Syncing, amp.LoginChallengeAttr, challenge); err != nil {
+		sess.Log().Warnf("login: %v", err)
+	}
+}
+
+// finishLogin checks the client's response to the pending LoginChallenge, replying with the verified Login or an ErrCode_LoginFailed error.
+func (sess *Session) finishLogin(reqID tag.ID, response *amp.LoginResponse) {
+	sess.mu.Lock()
+	pending := sess.pending
+	if pending != nil && pending.reqID == reqID {
+		sess.pending = nil
+	} else {
+		pending = nil
+	}
+	sess.mu.Unlock()
+
+	if pending == nil {
+		sess.sendErr(reqID, amp.ErrCode_LoginFailed.Error("no login challenge pending"))
+		return
+	}
+	verified, err := sess.host.opts.Authenticator.CheckResponse(pending.login, pending.challenge, response)
+	if err != nil {
+		sess.Log().Warnf("login failed: %v", err)
+		sess.sendErr(reqID, err)
+		return
+	}
+	sess.setLogin(reqID, verified, true)
+}
+
+// setLogin adopts the given identity and sends it to the client, completing the login request.
+// Only a login the Authenticator verified is sent with a new LoginCheckpoint, allowing whoever holds it to resume this session.
+func (sess *Session) setLogin(reqID tag.ID, login amp.Login, verified bool) {
+	var checkpoint *amp.LoginCheckpoint
+	if verified {
+		var err error
+		if checkpoint, err = sess.host.issueCheckpoint(sess); err != nil {
+			sess.sendErr(reqID, err)
+			return
+		}
+	}
+	login.Checkpoint = nil
+
+	sess.mu.Lock()
+	sess.login = login
+	sess.verified = verified
+	sess.mu.Unlock()
+
+	login.Checkpoint = checkpoint
+
+	if err := amp.SendMetaAttr(sess, reqID, amp.OpStatus_Closed, amp.LoginAttr, &login); err != nil {
+		sess.Log().Warnf("login: %v", err)
+	}
+}
+
+// handshake answers a client Handshake with this session's own and adopts the negotiated protocol (see amp.ExchangeHandshake).
+func (sess *Session) handshake(peer *amp.Handshake) {
+	local := amp.NewHandshake()
+	proto, err := local.Negotiate(peer)
+	if err != nil {
+		sess.Log().Warnf("handshake: %v", err)
+		sess.Close()
+		return
+	}
+	tx, err := amp.MarshalAttr(amp.MetaNodeID, amp.HandshakeAttr, local)
+	if err == nil {
+		err = sess.SendTx(tx)
+	}
+	if err != nil {
+		sess.Log().Warnf("handshake: %v", err)
+		return
+	}
+
+	sess.mu.Lock()
+	via := sess.via
+	sess.proto = &proto
+	sess.mu.Unlock()
+
+	if encoder, ok := via.(amp.TxEncoder); ok {
+		encoder.SetTxEncoding(proto.TxEncoding(amp.NegotiatedTxEncoding))
+	}
+}
+
+// servePin routes a PinRequest to the app it invokes (or to the Opts.Router handler for its URL scheme) subject to Limits, creating the app instance if needed.
+func (sess *Session) servePin(tx *amp.TxMsg, pin *amp.PinRequest) {
+	if !sess.authorized() {
+		sess.sendErr(tx.GenesisID(), amp.ErrCode_LoginFailed.Error("login required"))
+		return
+	}
+	req := &request{
+		sess: sess,
+		params: amp.Request{
+			PinRequest: *pin,
+			ID:         tx.GenesisID(),
+		},
+	}
+
+	route, err := sess.route(&req.params)
+	if err == nil {
+		err = sess.admitPin(req, route, tx)
+	}
+	server := route.Handler
+	if err == nil && route.App != nil {
+		var inst amp.AppInstance
+		inst, err = sess.GetAppInstance(route.App.AppSpec.ID, true)
+		if err == nil {
+			err = inst.MakeReady(req)
+		}
+		server = inst
+	}
+	if err != nil {
+		req.OnComplete(err)
+		return
+	}
+	sess.serve(req, server)
+}
+
+// commit serves a TxMsg the client sent to an open request (see client.Request.SendTx) as a commit to that request's Pin.
+// The commit is served as a new request (whose ID is the tx's GenesisID) with amp.Request.CommitTx set, subject to Opts.CommitPolicy
+// and the Limits of the app serving that request.
+// If the client sent a Handshake, a signed commit must also use a signing kit it negotiated.
+func (sess *Session) commit(tx *amp.TxMsg) {
+	reqID := tx.GenesisID()
+	if !sess.authorized() {
+		sess.sendErr(reqID, amp.ErrCode_LoginFailed.Error("login required"))
+		return
+	}
+
+	sess.mu.Lock()
+	proto := sess.proto
+	sess.mu.Unlock()
+	if proto != nil {
+		if err := proto.CheckSigned(tx); err != nil {
+			sess.sendErr(reqID, err)
+			return
+		}
+	}
+	if policy := sess.host.opts.CommitPolicy; policy != nil {
+		if err := policy.CheckCommit(tx); err != nil {
+			sess.sendErr(reqID, err)
+			return
+		}
+	}
+
+	sess.mu.Lock()
+	target := sess.reqs[tx.ContextID()]
+	sess.mu.Unlock()
+
+	var pin amp.Pin
+	if target != nil {
+		target.mu.Lock()
+		pin = target.pin
+		target.mu.Unlock()
+	}
+	if pin == nil {
+		sess.sendErr(reqID, amp.ErrCode_RequestNotFound.Error("commit names no open request"))
+		return
+	}
+	if err := sess.admitCommit(target, tx); err != nil {
+		sess.sendErr(reqID, err)
+		return
+	}
+
+	tx.AddRef()
+	req := &request{
+		sess: sess,
+		params: amp.Request{
+			PinRequest: target.params.PinRequest,
+			ID:         reqID,
+			CommitTx:   tx,
+			URL:        target.params.URL,
+			Values:     target.params.Values,
+		},
+	}
+	sess.serve(req, pin)
+}
+
+// serve has the given app instance (or URLRouter handler) serve the given request.
+func (sess *Session) serve(req *request, server amp.Pinner) {
+	req.server = server
+	served, err := server.ServeRequest(req)
+	if err != nil {
+		req.OnComplete(err)
+		return
+	}
+	req.setPin(served)
+}
+
+// route returns what serves the given request: the app invoked by its target (e.g. "amp://{app-alias}/...")
+// or the handler registered with Opts.Router for its URL scheme.  If not yet parsed, the URL is parsed into req.
+func (sess *Session) route(req *amp.Request) (amp.Route, error) {
+	return sess.host.opts.Router.Route(sess, req)
+}
+
+// closeRequest closes the Pin serving the given request, if any, as requested by the client.
+func (sess *Session) closeRequest(reqID tag.ID) {
+	sess.mu.Lock()
+	req := sess.reqs[reqID]
+	sess.mu.Unlock()
+
+	if req != nil {
+		req.cancel()
+	}
+}
+
+// sendErr sends the given error to the client as an amp.Err, closing the request with the given ID.
+func (sess *Session) sendErr(reqID tag.ID, err error) {
+	if sendErr := amp.SendMetaAttr(sess, reqID, amp.OpStatus_Closed, tag.ID{}, amp.ErrorToValue(err)); sendErr != nil {
+		sess.Log().Warnf("failed to send error %q: %v", err, sendErr)
+	}
+}
+
+// request implements amp.Requester for a PinRequest received by a Session.
+type request struct {
+	params amp.Request
+	sess   *Session
+	server amp.Pinner     // app instance (or URLRouter handler) serving this request
+	appID  tag.ID         // AppSpec.ID of the app serving this request (nil if served by a handler)
+	pins   *metrics.Gauge // open pins of the app (or URL scheme) serving this request; counts this request until it completes
+
+	mu         sync.Mutex
+	pin        amp.Pin      // set once served
+	canceled   bool         // set if the client closed this request
+	done       bool         // set once OnComplete() is called
+	held       bool         // set while the session is suspended; pushed TxMsgs are only retained
+	lastSynced tag.ID       // GenesisID of the latest OpStatus_Synced tx pushed
+	backlog    []*amp.TxMsg // TxMsgs pushed since lastSynced, replayed on resume
+	overflow   bool         // set if backlog exceeded Opts.MaxResumeBacklog since lastSynced
+}
+
+// Implements amp.Requester
+func (req *request) Request() *amp.Request {
+	return &req.params
+}
+
+// Implements amp.Requester
+//
+// If the session is resumable, the TxMsg is also retained for replay and a transport failure is not reported.
+func (req *request) PushTx(tx *amp.TxMsg) error {
+	req.mu.Lock()
+	defer req.mu.Unlock()
+
+	if req.done {
+		tx.ReleaseRef()
+		return amp.ErrRequestClosed
+	}
+	tx.SetContextID(req.params.ID)
+
+	if !req.sess.resumable() {
+		return req.sess.SendTx(tx)
+	}
+	req.retain(tx)
+	if req.held {
+		tx.ReleaseRef()
+		return nil
+	}
+	req.sess.SendTx(tx) // if the transport fails, the session is suspended and tx is replayed on resume
+	return nil
+}
+
+// Implements amp.Requester
+func (req *request) OnComplete(err error) {
+	req.mu.Lock()
+	if req.done {
+		req.mu.Unlock()
+		return
+	}
+	req.done = true
+	pin := req.pin
+	req.releaseBacklog()
+	if req.params.CommitTx != nil {
+		req.params.CommitTx.ReleaseRef()
+	}
+	req.mu.Unlock()
+
+	sess := req.sess
+	sess.mu.Lock()
+	if sess.reqs[req.params.ID] == req {
+		delete(sess.reqs, req.params.ID)
+		req.pins.Dec()
+	}
+	sess.mu.Unlock()
+
+	if pin != nil {
+		pin.Context().Close()
+	}
+
+	select {
+	case <-sess.Closing():
+		return // the transport is closing
+	default:
+	}
+	if err != nil && err != amp.ErrShuttingDown {
+		sess.sendErr(req.params.ID, err)
+		return
+	}
+	tx := amp.NewTxMsg(true)
+	tx.SetContextID(req.params.ID)
+	tx.Status = amp.OpStatus_Closed
+	sess.SendTx(tx)
+}
+
+// setPin retains the Pin serving this request, completing this request once the Pin closes.
+//
+// If the Pin is closed as this request is re-served (see Session.reserve), this request has already been retired.
+func (req *request) setPin(pin amp.Pin) {
+	req.mu.Lock()
+	req.pin = pin
+	canceled := req.canceled
+	req.mu.Unlock()
+
+	if canceled {
+		pin.Context().Close()
+	}
+	go func() {
+		<-pin.Context().Done()
+		req.OnComplete(nil)
+	}()
+}
+
+// cancel closes this request's Pin (or, if not yet served, marks it to be closed once it is).
+func (req *request) cancel() {
+	req.mu.Lock()
+	req.canceled = true
+	pin := req.pin
+	req.mu.Unlock()
+
+	if pin != nil {
+		pin.Context().Close()
+	}
+}
+
+// retain records the given TxMsg for replay on resume -- caller holds req.mu.
+// A Synced tx marks the point the client is known to be up to date, so it releases the backlog before it.
+func (req *request) retain(tx *amp.TxMsg) {
+	if tx.Status == amp.OpStatus_Synced {
+		req.releaseBacklog()
+		req.lastSynced = tx.GenesisID()
+		req.overflow = false
+		return
+	}
+	if req.overflow {
+		return
+	}
+	if len(req.backlog) >= req.sess.host.opts.MaxResumeBacklog {
+		req.releaseBacklog()
+		req.overflow = true
+		return
+	}
+	tx.AddRef()
+	req.backlog = append(req.backlog, tx)
+}
+
+// releaseBacklog releases the TxMsgs retained for replay -- caller holds req.mu.
+func (req *request) releaseBacklog() {
+	for i, tx := range req.backlog {
+		tx.ReleaseRef()
+		req.backlog[i] = nil
+	}
+	req.backlog = req.backlog[:0]
+}
@@ -0,0 +1,722 @@
+package host_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/client"
+	"github.com/art-media-platform/amp-sdk-go/amp/client/tests"
+	"github.com/art-media-platform/amp-sdk-go/amp/host"
+	"github.com/art-media-platform/amp-sdk-go/amp/std"
+	"github.com/art-media-platform/amp-sdk-go/amp/transport"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+var (
+	testAppSpec = amp.AppSpec.With("test.echo")
+	textPropID  = tag.ID{0, 0, 1}
+)
+
+// echoApp serves a cell whose single property is the path of the pinned URL.
+type echoApp struct {
+	std.App[*echoApp]
+	closed chan struct{}
+	pins   chan *std.Pin[*echoApp] // receives each pin served
+}
+
+type echoCell struct {
+	std.CellNode[*echoApp]
+	text string
+}
+
+func (app *echoApp) ServeRequest(op amp.Requester) (amp.Pin, error) {
+	req := op.Request()
+	if req.URL.Path == "/fail" {
+		return nil, amp.ErrCellNotFound
+	}
+	return app.PinAndServe(&echoCell{text: req.URL.Path}, op)
+}
+
+func (app *echoApp) OnClosing() {
+	close(app.closed)
+}
+
+func (cell *echoCell) PinInto(pin *std.Pin[*echoApp]) error {
+	select {
+	case pin.App.pins <- pin:
+	default:
+	}
+	return nil
+}
+
+func (cell *echoCell) MarshalAttrs(w std.CellWriter) {
+	w.PutText(textPropID, cell.text)
+}
+
+func startTestHost(t *testing.T, opts host.Opts) (*host.Host, chan *echoApp) {
+	h, err := host.Start(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { h.Close() })
+
+	started := make(chan *echoApp, 4)
+	h.HostRegistry().RegisterApp(&amp.App{
+		AppSpec: testAppSpec,
+		NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+			app := &echoApp{
+				closed: make(chan struct{}),
+				pins:   make(chan *std.Pin[*echoApp], 8),
+			}
+			app.AppContext = ctx
+			app.Instance = app
+			started <- app
+			return app, nil
+		},
+	})
+	return h, started
+}
+
+func startTestSession(t *testing.T, opts host.Opts) (*host.Host, amp.Session, *client.Client, chan *echoApp) {
+	h, started := startTestHost(t, opts)
+	a, b := transport.NewPipeTransport(transport.PipeOpts{})
+	sess, err := h.StartNewSession(nil, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := client.NewClient(a, client.Opts{})
+	t.Cleanup(func() { c.Close() })
+	return h, sess, c, started
+}
+
+func TestHost(t *testing.T) {
+	dataPath := t.TempDir()
+	_, sess, c, started := startTestSession(t, host.Opts{
+		DataPath: dataPath,
+	})
+
+	// a snapshot request is served by a new app instance and then closed
+	snapshot := tests.Pin(t, c, "amp://echo/hello", amp.StateSync_CloseOnSync)
+	if text := tests.RecvText(t, snapshot, std.CellProperties.ID); text != "/hello" {
+		t.Fatalf("expected /hello, got %q", text)
+	}
+	tests.WaitFor(t, snapshot.Done(), "snapshot request")
+	if err := snapshot.Err(); err != amp.ErrRequestClosed {
+		t.Fatalf("expected ErrRequestClosed, got %v", err)
+	}
+	app := <-started
+
+	// a maintained request stays open until the client closes it, reusing the running app instance
+	live := tests.Pin(t, c, "amp://echo/live", amp.StateSync_Maintain)
+	if text := tests.RecvText(t, live, std.CellProperties.ID); text != "/live" {
+		t.Fatalf("expected /live, got %q", text)
+	}
+	tests.WaitFor(t, live.Synced(), "Synced")
+	if live.Err() != nil {
+		t.Fatalf("expected open request, got %v", live.Err())
+	}
+	if err := live.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(started) != 0 {
+		t.Fatal("expected app instance to be reused")
+	}
+
+	// errors surface on the client
+	if fail := tests.Pin(t, c, "amp://echo/fail", amp.StateSync_Maintain); true {
+		tests.WaitFor(t, fail.Done(), "failed request")
+		if amp.GetErrCode(fail.Err()) != amp.ErrCode_CellNotFound {
+			t.Fatalf("expected ErrCode_CellNotFound, got %v", fail.Err())
+		}
+	}
+	if missing := tests.Pin(t, c, "amp://nope/x", amp.StateSync_Maintain); true {
+		tests.WaitFor(t, missing.Done(), "missing app request")
+		if amp.GetErrCode(missing.Err()) != amp.ErrCode_AppNotFound {
+			t.Fatalf("expected ErrCode_AppNotFound, got %v", missing.Err())
+		}
+	}
+
+	// app attrs and local data
+	inst, err := sess.GetAppInstance(testAppSpec.ID, false)
+	if err != nil || inst != app {
+		t.Fatalf("expected running app instance, got %v %v", inst, err)
+	}
+	if fi, err := os.Stat(app.LocalDataPath()); err != nil || !fi.IsDir() {
+		t.Fatalf("expected app data directory: %v", err)
+	}
+	settingID := amp.AttrSpec.With("test.setting").ID
+	var setting amp.Tag
+	if err = app.GetAppAttr(settingID, &setting); amp.GetErrCode(err) != amp.ErrCode_AttrNotFound {
+		t.Fatalf("expected ErrCode_AttrNotFound, got %v", err)
+	}
+	for _, text := range []string{"first", "second"} {
+		if err = app.PutAppAttr(settingID, &amp.Tag{Text: text}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = app.GetAppAttr(settingID, &setting); err != nil || setting.Text != "second" {
+		t.Fatalf("expected second, got %q %v", setting.Text, err)
+	}
+
+	// closing the session closes its app instances and their pins
+	open := tests.Pin(t, c, "amp://echo/open", amp.StateSync_Maintain)
+	tests.WaitFor(t, open.Synced(), "Synced")
+	sess.Close()
+	tests.WaitFor(t, app.closed, "app OnClosing")
+	tests.WaitFor(t, sess.Done(), "session close")
+	tests.WaitFor(t, open.Done(), "open request")
+}
+
+func TestStartNewSessionClosed(t *testing.T) {
+	h, _ := startTestHost(t, host.Opts{})
+	h.Close()
+	<-h.Done()
+
+	// a session that fails to start closes the transport it was given
+	a, b := transport.NewPipeTransport(transport.PipeOpts{})
+	if _, err := h.StartNewSession(nil, b); err == nil {
+		t.Fatal("expected StartNewSession to fail on a closed host")
+	}
+	if _, err := a.RecvTx(); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+}
+
+func TestSessionHandshake(t *testing.T) {
+	h, err := host.Start(host.Opts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Close()
+
+	a, b := transport.NewPipeTransport(transport.PipeOpts{})
+	if _, err = h.StartNewSession(nil, b); err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	// a client verifying no signing kits can't send a signed commit
+	hs := amp.NewHandshake()
+	hs.SigningKits = nil
+	proto, err := amp.ExchangeHandshake(a, hs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proto.HeaderVersion != amp.MaxTxHeaderVersion || proto.Codecs != amp.SupportedTxCodecs || len(proto.SigningKits) != 0 {
+		t.Fatalf("unexpected protocol %+v", proto)
+	}
+	c := client.NewClient(a, client.Opts{})
+	defer c.Close()
+
+	signKey, _, err := amp.GenerateSigningKey(amp.CryptoKit_Signing_ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := amp.NewTxMsg(true)
+	tx.Upsert(tag.ID{0, 0, 77}, std.CellProperties.ID, textPropID, &amp.Tag{Text: "committed"})
+	tx.SetContextID(tag.Now())
+	if err = tx.Sign(signKey); err != nil {
+		t.Fatal(err)
+	}
+	req, err := c.Send(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests.WaitFor(t, req.Done(), "commit refusal")
+	if amp.GetErrCode(req.Err()) != amp.ErrCode_UnsupportedOp {
+		t.Fatalf("expected ErrCode_UnsupportedOp, got %v", req.Err())
+	}
+}
+
+func TestSessionLogin(t *testing.T) {
+	deviceKey, devicePub, err := amp.GenerateSigningKey(amp.CryptoKit_Signing_ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _, err := amp.GenerateSigningKey(amp.CryptoKit_Signing_ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, sess, c, _ := startTestSession(t, host.Opts{
+		Authenticator: amp.NewEd25519Authenticator(func(login *amp.Login) (*amp.CryptoKey, error) {
+			return devicePub, nil
+		}),
+	})
+	login := &amp.Login{
+		UserID:   &amp.Tag{Text: "user"},
+		DeviceID: &amp.Tag{Text: "device"},
+	}
+	refused := func(when string) {
+		t.Helper()
+		req := tests.Pin(t, c, "amp://echo/private", amp.StateSync_CloseOnSync)
+		tests.WaitFor(t, req.Done(), when)
+		if amp.GetErrCode(req.Err()) != amp.ErrCode_LoginFailed {
+			t.Fatalf("%s: expected pin to fail with ErrCode_LoginFailed, got %v", when, req.Err())
+		}
+	}
+	refused("before login")
+
+	// a response signed by the wrong key fails and leaves the session unauthenticated
+	if _, err = c.Login(login, otherKey); amp.GetErrCode(err) != amp.ErrCode_LoginFailed {
+		t.Fatalf("expected ErrCode_LoginFailed, got %v", err)
+	}
+	if sess.Login().UserID != nil {
+		t.Fatal("expected unauthenticated session")
+	}
+	refused("after failed login")
+	if _, err = c.Login(login, nil); amp.GetErrCode(err) != amp.ErrCode_LoginFailed {
+		t.Fatalf("expected ErrCode_LoginFailed, got %v", err)
+	}
+
+	verified, err := c.Login(login, deviceKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verified.UserID.Text != "user" || sess.Login().DeviceID.Text != "device" || verified.Checkpoint == nil {
+		t.Fatalf("unexpected login %v / %v", verified, sess.Login())
+	}
+	target := tests.Pin(t, c, "amp://echo/private", amp.StateSync_Maintain)
+	if text := tests.RecvText(t, target, std.CellProperties.ID); text != "/private" {
+		t.Fatalf("unexpected text %q", text)
+	}
+
+	// a commit to an open pin is only served while the login is verified
+	if err = commitText(t, c, target, "committed", nil); amp.GetErrCode(err) != amp.ErrCode_CellNotFound {
+		t.Fatalf("expected commit to be served, got %v", err)
+	}
+	if _, err = c.Login(login, otherKey); amp.GetErrCode(err) != amp.ErrCode_LoginFailed {
+		t.Fatalf("expected ErrCode_LoginFailed, got %v", err)
+	}
+	if err = commitText(t, c, target, "committed", nil); amp.GetErrCode(err) != amp.ErrCode_LoginFailed {
+		t.Fatalf("expected commit after failed re-login to fail with ErrCode_LoginFailed, got %v", err)
+	}
+
+	// without an Authenticator, a claimed login is adopted but can't be used to resume the session
+	_, sess, c, _ = startTestSession(t, host.Opts{})
+	claimed, err := c.Login(login, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimed.Checkpoint != nil || sess.Login().UserID.Text != "user" {
+		t.Fatalf("unexpected login %v / %v", claimed, sess.Login())
+	}
+}
+
+// testAuthenticator returns an Authenticator accepting any login whose challenge is signed by the returned device key.
+func testAuthenticator(t *testing.T) (amp.Authenticator, *amp.CryptoKey) {
+	deviceKey, devicePub, err := amp.GenerateSigningKey(amp.CryptoKit_Signing_ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return amp.NewEd25519Authenticator(func(login *amp.Login) (*amp.CryptoKey, error) {
+		return devicePub, nil
+	}), deviceKey
+}
+
+func TestCommitPolicy(t *testing.T) {
+	signKey, signPub, err := amp.GenerateSigningKey(amp.CryptoKit_Signing_ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _, err := amp.GenerateSigningKey(amp.CryptoKit_Signing_ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, c, _ := startTestSession(t, host.Opts{
+		CommitPolicy: &amp.CommitPolicy{
+			Signers: []*amp.CryptoKey{signPub},
+		},
+	})
+	target := tests.Pin(t, c, "amp://echo/commit", amp.StateSync_Maintain)
+	if text := tests.RecvText(t, target, std.CellProperties.ID); text != "/commit" {
+		t.Fatalf("unexpected text %q", text)
+	}
+
+	// a refused commit is not served
+	for _, key := range []*amp.CryptoKey{nil, otherKey} {
+		if err = commitText(t, c, target, "committed", key); amp.GetErrCode(err) != amp.ErrCode_AuthFailed {
+			t.Fatalf("expected ErrCode_AuthFailed, got %v", err)
+		}
+	}
+
+	// an accepted commit is served by the target's Pin, which has no such cell
+	if err = commitText(t, c, target, "committed", signKey); amp.GetErrCode(err) != amp.ErrCode_CellNotFound {
+		t.Fatalf("expected ErrCode_CellNotFound, got %v", err)
+	}
+}
+
+// commitText commits an echoCell text update to the given open request, signed by key if given, and returns the commit's error.
+func commitText(t *testing.T, c *client.Client, target *client.Request, text string, key *amp.CryptoKey) error {
+	t.Helper()
+	tx := amp.NewTxMsg(true)
+	tx.Upsert(tag.ID{0, 0, 77}, std.CellProperties.ID, textPropID, &amp.Tag{Text: text})
+	tx.SetContextID(target.ID)
+	if key != nil {
+		if err := tx.Sign(key); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req, err := c.Send(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests.WaitFor(t, req.Done(), "commit")
+	return req.Err()
+}
+
+// pushText pushes an update of the given pinned echoCell's text to its client.
+func pushText(t *testing.T, pin *std.Pin[*echoApp], text string, status amp.OpStatus) {
+	t.Helper()
+	cellID := pin.Cell.Root().ID
+	tx := amp.NewTxMsg(true)
+	tx.Upsert(amp.MetaNodeID, std.CellChildren.ID, cellID, nil)
+	tx.Upsert(cellID, std.CellProperties.ID, textPropID, &amp.Tag{Text: text})
+	tx.Status = status
+	if err := pin.Op.PushTx(tx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionResume(t *testing.T) {
+	auth, deviceKey := testAuthenticator(t)
+	h, started := startTestHost(t, host.Opts{
+		Authenticator: auth,
+	})
+
+	conns := make(chan *transport.PipeTransport, 4)
+	dial := func() (amp.Transport, error) {
+		a, b := transport.NewPipeTransport(transport.PipeOpts{Serialize: true})
+		if _, err := h.StartNewSession(nil, b); err != nil {
+			return nil, err
+		}
+		conns <- a
+		return a, nil
+	}
+	via, _ := dial()
+	c, err := client.Connect(via, client.Opts{Reconnect: dial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	login, err := c.Login(&amp.Login{UserID: &amp.Tag{Text: "user"}}, deviceKey)
+	if err != nil || login.Checkpoint.GetAccessToken() == "" {
+		t.Fatalf("expected a resume checkpoint, got %v %v", login.Checkpoint, err)
+	}
+	live := tests.Pin(t, c, "amp://echo/live", amp.StateSync_Maintain)
+	if text := tests.RecvText(t, live, std.CellProperties.ID); text != "/live" {
+		t.Fatalf("expected /live, got %q", text)
+	}
+	app := <-started
+	served := <-app.pins
+
+	// changes pushed while the transport is down are replayed once the client resumes
+	(<-conns).Break(nil)
+	pushText(t, served, "/changed", amp.OpStatus_Syncing)
+	if text := tests.RecvText(t, live, std.CellProperties.ID); text != "/changed" {
+		t.Fatalf("expected /changed, got %q", text)
+	}
+	if len(conns) != 1 {
+		t.Fatalf("expected 1 reconnect, got %d", len(conns))
+	}
+	if _, ok := c.Protocol(); !ok {
+		t.Fatal("expected a Protocol negotiated over the new transport")
+	}
+
+	// a Synced tx the client missed causes the request's state to be re-sent in full
+	(<-conns).Break(nil)
+	pushText(t, served, "/missed", amp.OpStatus_Synced)
+	if text := tests.RecvText(t, live, std.CellProperties.ID); text != "/live" {
+		t.Fatalf("expected /live, got %q", text)
+	}
+	tests.WaitFor(t, served.Context().Done(), "retired pin")
+	if live.Err() != nil || len(started) != 0 {
+		t.Fatalf("expected the request and app instance to carry on, got %v", live.Err())
+	}
+	if err := live.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// a session can't be resumed with an unknown checkpoint
+	a, b := transport.NewPipeTransport(transport.PipeOpts{})
+	if _, err = h.StartNewSession(nil, b); err != nil {
+		t.Fatal(err)
+	}
+	other := client.NewClient(a, client.Opts{})
+	defer other.Close()
+	tx, err := amp.MarshalAttr(amp.MetaNodeID, amp.SessionResumeAttr, &amp.SessionResume{
+		Checkpoint: &amp.LoginCheckpoint{AccessToken: "bogus"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := other.Send(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests.WaitFor(t, req.Done(), "resume refusal")
+	if amp.GetErrCode(req.Err()) != amp.ErrCode_SessionExpired {
+		t.Fatalf("expected ErrCode_SessionExpired, got %v", req.Err())
+	}
+}
+
+func TestSessionResumeGrace(t *testing.T) {
+	auth, deviceKey := testAuthenticator(t)
+	h, _ := startTestHost(t, host.Opts{
+		Authenticator: auth,
+		ResumeGrace:   20 * time.Millisecond,
+	})
+	a, b := transport.NewPipeTransport(transport.PipeOpts{})
+	sess, err := h.StartNewSession(nil, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := client.NewClient(a, client.Opts{})
+	if _, err = c.Login(&amp.Login{UserID: &amp.Tag{Text: "user"}}, deviceKey); err != nil {
+		t.Fatal(err)
+	}
+
+	// a suspended session closes once not resumed within ResumeGrace
+	a.Break(nil)
+	tests.WaitFor(t, c.Done(), "client stop")
+	tests.WaitFor(t, sess.Done(), "session close")
+}
+
+// pinOp is an amp.Requester an app uses to pin a cell served by another app.
+type pinOp struct {
+	req  amp.Request
+	done chan error
+}
+
+func (op *pinOp) Request() *amp.Request { return &op.req }
+
+func (op *pinOp) PushTx(tx *amp.TxMsg) error {
+	tx.ReleaseRef()
+	return nil
+}
+
+func (op *pinOp) OnComplete(err error) {
+	select {
+	case op.done <- err:
+	default:
+	}
+}
+
+func TestAppDependencies(t *testing.T) {
+	h, started := startTestHost(t, host.Opts{})
+	consumerSpec := amp.AppSpec.With("test.consumer")
+	h.HostRegistry().RegisterApp(&amp.App{
+		AppSpec:      consumerSpec,
+		Dependencies: []tag.ID{testAppSpec.ID},
+		NewAppInstance: func(ctx amp.AppContext) (amp.AppInstance, error) {
+			app := &echoApp{
+				closed: make(chan struct{}),
+			}
+			app.AppContext = ctx
+			app.Instance = app
+			return app, nil
+		},
+	})
+
+	a, b := transport.NewPipeTransport(transport.PipeOpts{})
+	defer a.Close()
+	sess, err := h.StartNewSession(nil, b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumer, err := sess.GetAppInstance(consumerSpec.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	echo, err := consumer.Session().GetAppInstance(testAppSpec.ID, true)
+	if err != nil {
+		t.Fatalf("expected access to declared dependency: %v", err)
+	}
+	<-started
+
+	// an app reaches itself and its declared dependencies but nothing else
+	if self, err := consumer.Session().GetAppInstance(consumerSpec.ID, false); err != nil || self != consumer {
+		t.Fatalf("expected access to itself, got %v %v", self, err)
+	}
+	if _, err = echo.Session().GetAppInstance(consumerSpec.ID, true); amp.GetErrCode(err) != amp.ErrCode_InsufficientPermissions {
+		t.Fatalf("expected ErrCode_InsufficientPermissions, got %v", err)
+	}
+
+	// the same applies to cross-app pin routing
+	route := func(from amp.AppInstance, url string) (amp.Pin, error) {
+		op := &pinOp{
+			req: amp.Request{
+				PinRequest: amp.PinRequest{
+					PinTarget: &amp.Tag{URL: url},
+					StateSync: amp.StateSync_CloseOnSync,
+				},
+				ID: tag.Now(),
+			},
+			done: make(chan error, 1),
+		}
+		return from.Session().(amp.Pinner).ServeRequest(op)
+	}
+	pinned, err := route(consumer, "amp://echo/routed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests.WaitFor(t, pinned.Context().Done(), "routed pin")
+	if _, err = route(echo, "amp://consumer/x"); amp.GetErrCode(err) != amp.ErrCode_InsufficientPermissions {
+		t.Fatalf("expected ErrCode_InsufficientPermissions, got %v", err)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	longURL := "amp://echo/" + strings.Repeat("x", 2000)
+	cases := []struct {
+		name    string
+		opts    host.Opts
+		allowed []string
+		denied  string
+		code    amp.ErrCode
+	}{
+		{
+			name:    "session pins",
+			opts:    host.Opts{SessionLimits: host.Limits{MaxPins: 2}},
+			allowed: []string{"amp://echo/a", "amp://echo/b"},
+			denied:  "amp://echo/c",
+			code:    amp.ErrCode_PinLimitReached,
+		}, {
+			name:    "app pins",
+			opts:    host.Opts{AppLimits: map[tag.ID]host.Limits{testAppSpec.ID: {MaxPins: 1}}},
+			allowed: []string{"amp://echo/a"},
+			denied:  "amp://echo/b",
+			code:    amp.ErrCode_PinLimitReached,
+		}, {
+			name:    "tx rate",
+			opts:    host.Opts{SessionLimits: host.Limits{MaxTxPerSec: 2}},
+			allowed: []string{"amp://echo/a", "amp://echo/b"},
+			denied:  "amp://echo/c",
+			code:    amp.ErrCode_TxRateExceeded,
+		}, {
+			name:    "byte rate",
+			opts:    host.Opts{SessionLimits: host.Limits{MaxBytesPerSec: 1000}},
+			allowed: []string{"amp://echo/a"},
+			denied:  longURL,
+			code:    amp.ErrCode_ByteRateExceeded,
+		}, {
+			name:    "commit size",
+			opts:    host.Opts{DefaultAppLimits: host.Limits{MaxCommitSz: 1000}},
+			allowed: []string{"amp://echo/a"},
+			denied:  longURL,
+			code:    amp.ErrCode_CommitTooLarge,
+		},
+	}
+	for _, test := range cases {
+		t.Run(test.name, func(t *testing.T) {
+			_, _, c, _ := startTestSession(t, test.opts)
+
+			var open []*client.Request
+			for _, url := range test.allowed {
+				req := tests.Pin(t, c, url, amp.StateSync_Maintain)
+				tests.WaitFor(t, req.Synced(), url)
+				open = append(open, req)
+			}
+			req := tests.Pin(t, c, test.denied, amp.StateSync_Maintain)
+			tests.WaitFor(t, req.Done(), "denied pin")
+			if code := amp.GetErrCode(req.Err()); code != test.code {
+				t.Fatalf("expected %v, got %v", test.code, req.Err())
+			}
+
+			// closing a pin frees its slot once the host has closed it
+			if test.code == amp.ErrCode_PinLimitReached {
+				open[0].Close()
+				for deadline := time.Now().Add(5 * time.Second); ; {
+					req = tests.Pin(t, c, test.denied, amp.StateSync_Maintain)
+					select {
+					case <-req.Synced():
+					case <-req.Done():
+						if time.Now().Before(deadline) && amp.GetErrCode(req.Err()) == test.code {
+							time.Sleep(10 * time.Millisecond)
+							continue
+						}
+						t.Fatalf("pin after close: %v", req.Err())
+					}
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestAppCommitLimits(t *testing.T) {
+	_, _, c, _ := startTestSession(t, host.Opts{
+		AppLimits: map[tag.ID]host.Limits{
+			testAppSpec.ID: {MaxPins: 1, MaxTxPerSec: 3, MaxCommitSz: 1000},
+		},
+	})
+	target := tests.Pin(t, c, "amp://echo/a", amp.StateSync_Maintain)
+	tests.WaitFor(t, target.Synced(), "pin")
+
+	// a pin refused by MaxPins doesn't debit the app's rate limits
+	req := tests.Pin(t, c, "amp://echo/b", amp.StateSync_Maintain)
+	tests.WaitFor(t, req.Done(), "denied pin")
+	if code := amp.GetErrCode(req.Err()); code != amp.ErrCode_PinLimitReached {
+		t.Fatalf("expected ErrCode_PinLimitReached, got %v", req.Err())
+	}
+
+	// commits to an app's pin are held to its limits; an admitted commit is served by the target's Pin, which has no such cell
+	if err := commitText(t, c, target, strings.Repeat("x", 2000), nil); amp.GetErrCode(err) != amp.ErrCode_CommitTooLarge {
+		t.Fatalf("expected ErrCode_CommitTooLarge, got %v", err)
+	}
+	for range 2 {
+		if err := commitText(t, c, target, "small", nil); amp.GetErrCode(err) != amp.ErrCode_CellNotFound {
+			t.Fatalf("expected ErrCode_CellNotFound, got %v", err)
+		}
+	}
+	if err := commitText(t, c, target, "small", nil); amp.GetErrCode(err) != amp.ErrCode_TxRateExceeded {
+		t.Fatalf("expected ErrCode_TxRateExceeded, got %v", err)
+	}
+}
+
+// webHandler is a URLRouter handler that declines every request, used to check routing.
+type webHandler struct {
+	served chan string
+}
+
+func (h *webHandler) ServeRequest(op amp.Requester) (amp.Pin, error) {
+	h.served <- op.Request().URL.Host
+	return nil, amp.ErrCode_Unimplemented.Error("web handler")
+}
+
+func TestURLRouting(t *testing.T) {
+	web := &webHandler{
+		served: make(chan string, 1),
+	}
+	router := amp.NewURLRouter()
+	if err := router.Handle(amp.UrlScheme_Http, web); err != nil {
+		t.Fatal(err)
+	}
+	_, _, c, _ := startTestSession(t, host.Opts{
+		Router: router,
+	})
+
+	req := tests.Pin(t, c, "amp://echo/routed", amp.StateSync_CloseOnSync)
+	if text := tests.RecvText(t, req, std.CellProperties.ID); text != "/routed" {
+		t.Fatalf("unexpected text %q", text)
+	}
+
+	for url, code := range map[string]amp.ErrCode{
+		"https://example.com/page": amp.ErrCode_Unimplemented, // served by the handler
+		"git://example.com/repo":   amp.ErrCode_InvalidURI,    // no handler
+		"amp://nope/x":             amp.ErrCode_AppNotFound,
+		"amp:":                     amp.ErrCode_InvalidURI,
+	} {
+		req = tests.Pin(t, c, url, amp.StateSync_Maintain)
+		tests.WaitFor(t, req.Done(), url)
+		if got := amp.GetErrCode(req.Err()); got != code {
+			t.Fatalf("%q: expected %v, got %v", url, code, req.Err())
+		}
+	}
+	if host := <-web.served; host != "example.com" {
+		t.Fatalf("unexpected host %q", host)
+	}
+}
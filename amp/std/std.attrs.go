@@ -96,3 +96,15 @@ func (v *FSInfo) SetCreatedAt(t time.Time) {
 	tag := tag.FromTime(t, false)
 	v.CreatedAt = int64(tag[0])
 }
+
+func (v *DataSegment) MarshalToStore(in []byte) (out []byte, err error) {
+	return amp.MarshalPbToStore(v, in)
+}
+
+func (v *DataSegment) TagSpec() tag.Spec {
+	return amp.AttrSpec.With("DataSegment")
+}
+
+func (v *DataSegment) New() tag.Value {
+	return &DataSegment{}
+}
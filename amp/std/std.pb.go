@@ -560,6 +560,8 @@ func (m *TRS) GetRotate3() float32 {
 	return 0
 }
 
+// DataSegment is a span of a (possibly large) value.
+// When a value is sent in chunks (see SegmentSender), each chunk is a DataSegment whose InlineData starts at ByteOfs and ByteSz is the total size of the value.
 type DataSegment struct {
 	ByteOfs    uint64 `protobuf:"varint,5,opt,name=ByteOfs,proto3" json:"ByteOfs,omitempty"`
 	ByteSz     uint64 `protobuf:"varint,6,opt,name=ByteSz,proto3" json:"ByteSz,omitempty"`
@@ -649,59 +651,59 @@ func init() {
 func init() { proto.RegisterFile("amp/std/std.proto", fileDescriptor_b6f70fdd671fe185) }
 
 var fileDescriptor_b6f70fdd671fe185 = []byte{
-	// 831 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x94, 0x4f, 0x6f, 0x1b, 0x45,
-	0x18, 0xc6, 0x3d, 0x76, 0xec, 0xd8, 0x6f, 0x9a, 0xb0, 0x8c, 0x02, 0x0c, 0xa5, 0x5a, 0x59, 0xe6,
-	0xe2, 0x06, 0xc5, 0x89, 0xed, 0x82, 0xb8, 0x00, 0xca, 0x9f, 0xb6, 0x58, 0xc2, 0xc4, 0x9d, 0x4d,
-	0x4a, 0x9a, 0x4b, 0x34, 0xf1, 0x4e, 0xdc, 0x11, 0xbb, 0x3b, 0xcb, 0xee, 0x18, 0xb5, 0x3d, 0xf1,
-	0x11, 0xb8, 0xf0, 0x1d, 0x50, 0xef, 0x7c, 0x07, 0x8e, 0x39, 0xf6, 0xc0, 0x81, 0x38, 0x17, 0x8e,
-	0xfd, 0x00, 0x1c, 0xd0, 0xbc, 0xbb, 0x5e, 0x9b, 0x20, 0x0e, 0x96, 0xdf, 0xe7, 0xf7, 0xec, 0x9f,
-	0x79, 0xdf, 0x79, 0x76, 0xe0, 0x5d, 0x11, 0xc6, 0x3b, 0xa9, 0xf1, 0xed, 0xaf, 0x13, 0x27, 0xda,
-	0x68, 0x5a, 0x49, 0x8d, 0x7f, 0x77, 0xdd, 0x72, 0x11, 0xc6, 0x19, 0x6b, 0xfd, 0x00, 0xf5, 0x91,
-	0x4e, 0x95, 0x51, 0x3a, 0xa2, 0xf7, 0xa1, 0x7e, 0xa0, 0x13, 0xff, 0xf8, 0x65, 0x2c, 0x19, 0x69,
-	0x92, 0xf6, 0x46, 0x6f, 0xbd, 0x63, 0xef, 0x9e, 0x43, 0x5e, 0xd8, 0xf4, 0x0e, 0x90, 0x27, 0xac,
-	0xd2, 0x24, 0x6d, 0xc2, 0xc9, 0x13, 0xab, 0x38, 0x5b, 0xc9, 0x14, 0xb7, 0xca, 0x63, 0xd5, 0x4c,
-	0x79, 0xd4, 0x81, 0x0a, 0x3f, 0x3a, 0x61, 0xb5, 0x26, 0x69, 0x97, 0xb9, 0x2d, 0x5b, 0x7f, 0x10,
-	0xa8, 0x3d, 0xf2, 0x06, 0xd1, 0xa5, 0xa6, 0x14, 0x56, 0x86, 0xda, 0xcf, 0xde, 0xd6, 0xe0, 0x58,
-	0xd3, 0x4d, 0xa8, 0x0e, 0xd2, 0x43, 0x95, 0xb0, 0x72, 0x93, 0xb4, 0xeb, 0x3c, 0x13, 0xf6, 0xca,
-	0x6f, 0x45, 0x28, 0xf1, 0x9d, 0x0d, 0x8e, 0x35, 0x65, 0xb0, 0x6a, 0xff, 0xbf, 0x91, 0x11, 0xbe,
-	0xbc, 0xca, 0xe7, 0x92, 0x36, 0x61, 0xed, 0x40, 0x47, 0x46, 0x46, 0x06, 0x9b, 0xa9, 0xe2, 0x4d,
-	0xcb, 0x88, 0xde, 0x83, 0xc6, 0x41, 0x22, 0x85, 0x91, 0xfe, 0x9e, 0x61, 0xab, 0x4d, 0xd2, 0xae,
-	0xf0, 0x05, 0xa0, 0x2e, 0xc0, 0x50, 0xfb, 0xea, 0x52, 0xa1, 0x5d, 0x47, 0x7b, 0x89, 0xd0, 0xbb,
-	0x50, 0xdf, 0x7f, 0x69, 0xa4, 0xa7, 0x5e, 0x49, 0xd6, 0x40, 0xb7, 0xd0, 0xad, 0xbf, 0x09, 0x34,
-	0x46, 0x81, 0x18, 0xcb, 0x50, 0x46, 0xc6, 0xae, 0x7b, 0xa4, 0xd3, 0x5d, 0xec, 0x90, 0x70, 0xac,
-	0x73, 0xd6, 0xc5, 0x06, 0x33, 0xd6, 0xcd, 0x59, 0x2f, 0x9f, 0x29, 0xd6, 0xf4, 0x7d, 0xa8, 0x79,
-	0x63, 0x11, 0xc8, 0x5d, 0x6c, 0xaf, 0xcc, 0x73, 0x55, 0xf0, 0x2e, 0x36, 0x36, 0xe7, 0xdd, 0x82,
-	0xf7, 0xf2, 0x69, 0xe7, 0xca, 0xf2, 0x87, 0xd3, 0x40, 0x26, 0xa7, 0xd8, 0x68, 0x99, 0xe7, 0xaa,
-	0xe0, 0xcf, 0xb0, 0xc3, 0x39, 0x7f, 0x56, 0xf0, 0x33, 0xec, 0x6d, 0xce, 0xcf, 0xe8, 0xc7, 0x50,
-	0x1b, 0x4a, 0x93, 0xa8, 0x31, 0xbb, 0x83, 0xe9, 0x58, 0xeb, 0xd8, 0x1c, 0x65, 0x88, 0xe7, 0x56,
-	0xeb, 0x29, 0xc0, 0xbe, 0xf0, 0x27, 0xf2, 0x50, 0x4d, 0x94, 0xb1, 0x63, 0xde, 0x0b, 0xe3, 0x40,
-	0x99, 0x69, 0xbe, 0xcb, 0x15, 0xbe, 0x00, 0x74, 0x0b, 0x9c, 0x42, 0x0c, 0xb5, 0x3f, 0x0d, 0xa6,
-	0x29, 0x0e, 0xa5, 0xc2, 0xff, 0xc3, 0x5b, 0xbf, 0x95, 0xa1, 0x72, 0xcc, 0x3d, 0xba, 0x01, 0xe5,
-	0xd3, 0x2e, 0xbb, 0x8f, 0x63, 0x2a, 0x9f, 0x76, 0x51, 0xf7, 0xd8, 0x56, 0xae, 0x7b, 0xa8, 0xfb,
-	0xec, 0x93, 0x5c, 0xf7, 0xe9, 0x67, 0xd0, 0xc0, 0x31, 0x60, 0xce, 0x7a, 0xb8, 0x6e, 0x86, 0xa9,
-	0x3e, 0xe6, 0x5e, 0xe7, 0xa9, 0x4a, 0xa7, 0x22, 0x28, 0x7c, 0xbe, 0xb8, 0x74, 0x69, 0xc8, 0xfd,
-	0xff, 0x19, 0xf2, 0x83, 0xdb, 0x43, 0xc6, 0xaa, 0xcf, 0x3e, 0x5d, 0xe2, 0x7d, 0x1b, 0x52, 0xae,
-	0x8d, 0x30, 0xb2, 0xcb, 0xbe, 0x40, 0x63, 0x2e, 0x17, 0x4e, 0x8f, 0x7d, 0xb9, 0xec, 0xf4, 0x16,
-	0x4e, 0x9f, 0x7d, 0xb5, 0xec, 0xf4, 0x5b, 0xbb, 0xf0, 0xce, 0xad, 0x35, 0xd3, 0x75, 0x68, 0xec,
-	0x4d, 0x8d, 0x46, 0xe0, 0x94, 0xe8, 0x06, 0xc0, 0x23, 0xf5, 0x42, 0xfa, 0x99, 0x26, 0xad, 0x5f,
-	0x08, 0xac, 0x1d, 0x0a, 0x23, 0x3c, 0x39, 0xc1, 0x40, 0x32, 0x58, 0xb5, 0x51, 0x3d, 0xba, 0x4c,
-	0x31, 0x3d, 0x2b, 0x7c, 0x2e, 0x6d, 0x07, 0x18, 0xe2, 0x57, 0x18, 0x9f, 0x15, 0x9e, 0x2b, 0xfb,
-	0x31, 0x0c, 0xa2, 0x40, 0x45, 0xd2, 0x3e, 0x06, 0x23, 0x74, 0x87, 0x2f, 0x11, 0xbb, 0xc7, 0x9e,
-	0x49, 0xa4, 0x08, 0x4f, 0xf8, 0x00, 0x13, 0xd3, 0xe0, 0x0b, 0x80, 0x4f, 0x0d, 0xf4, 0xc5, 0xe0,
-	0x90, 0x01, 0xee, 0x6c, 0xae, 0xb6, 0x5e, 0x93, 0xc5, 0x69, 0x43, 0x19, 0x6c, 0xce, 0xeb, 0xf3,
-	0x93, 0x28, 0x8d, 0xe5, 0x18, 0xbf, 0x34, 0xa7, 0x44, 0x37, 0xc1, 0x29, 0x9c, 0xa3, 0xc4, 0x97,
-	0x89, 0xf4, 0x1d, 0x42, 0xef, 0x01, 0x2b, 0xe8, 0x28, 0x10, 0x91, 0x3c, 0x3f, 0x10, 0x89, 0x91,
-	0xa9, 0x12, 0x91, 0x53, 0xa5, 0x1f, 0xc1, 0x07, 0xb7, 0xdc, 0xaf, 0xe5, 0x8b, 0x87, 0x3f, 0xca,
-	0x88, 0x3b, 0x35, 0xfa, 0x21, 0xbc, 0x57, 0x98, 0x8f, 0xa5, 0x56, 0xfe, 0xb9, 0x17, 0x3f, 0x97,
-	0x89, 0x74, 0xe0, 0x5f, 0xab, 0xc8, 0xac, 0xef, 0x1e, 0x7b, 0x9f, 0x3f, 0x70, 0xd6, 0xf6, 0xe3,
-	0xab, 0x6b, 0xb7, 0xf4, 0xe6, 0xda, 0x2d, 0xbd, 0xbd, 0x76, 0xc9, 0x4f, 0x33, 0x97, 0xfc, 0x3a,
-	0x73, 0xc9, 0xef, 0x33, 0x97, 0x5c, 0xcd, 0x5c, 0xf2, 0xe7, 0xcc, 0x25, 0x7f, 0xcd, 0xdc, 0xd2,
-	0xdb, 0x99, 0x4b, 0x7e, 0xbe, 0x71, 0x4b, 0x57, 0x37, 0x6e, 0xe9, 0xcd, 0x8d, 0x5b, 0x3a, 0xdb,
-	0x9d, 0x28, 0xf3, 0x7c, 0x7a, 0xd1, 0x19, 0xeb, 0x70, 0x47, 0x24, 0x66, 0x3b, 0x94, 0xbe, 0x12,
-	0xdb, 0x71, 0x20, 0xcc, 0xa5, 0x4e, 0x42, 0x7b, 0x08, 0x6f, 0xa7, 0xfe, 0xf7, 0xdb, 0x13, 0xbd,
-	0x93, 0x9f, 0xd5, 0xaf, 0xcb, 0xab, 0x7b, 0xc3, 0x51, 0xc7, 0x33, 0xfe, 0x45, 0x0d, 0x8f, 0xe7,
-	0xfe, 0x3f, 0x01, 0x00, 0x00, 0xff, 0xff, 0xd9, 0x0a, 0xdb, 0xb8, 0xc7, 0x05, 0x00, 0x00,
+	// 827 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x94, 0x4f, 0x73, 0xdb, 0x44,
+	0x18, 0xc6, 0xbd, 0x72, 0xec, 0x58, 0x6f, 0x9a, 0x20, 0x76, 0x02, 0x2c, 0xa5, 0xa3, 0xf1, 0x98,
+	0x8b, 0x1b, 0x26, 0x4e, 0x6c, 0x17, 0x86, 0x0b, 0x30, 0xf9, 0xd3, 0x16, 0xcf, 0x60, 0xe2, 0xae,
+	0x92, 0x92, 0xe6, 0x92, 0xd9, 0x58, 0x1b, 0x57, 0x83, 0xa4, 0x15, 0xd2, 0x9a, 0x69, 0x7b, 0xe2,
+	0x23, 0x70, 0xe1, 0x3b, 0x30, 0xbd, 0xf3, 0x1d, 0x38, 0xe6, 0xd8, 0x03, 0x07, 0xe2, 0x5c, 0x38,
+	0xf6, 0x03, 0x70, 0x60, 0xf6, 0xb5, 0x2c, 0x8b, 0x30, 0x1c, 0x3c, 0x7e, 0x9f, 0xdf, 0xa3, 0x3f,
+	0xfb, 0xbe, 0xfb, 0x68, 0xe1, 0x5d, 0x11, 0x25, 0x3b, 0x99, 0xf6, 0xcd, 0xaf, 0x93, 0xa4, 0x4a,
+	0x2b, 0x5a, 0xcd, 0xb4, 0x7f, 0x77, 0xdd, 0x70, 0x11, 0x25, 0x73, 0xd6, 0xfa, 0x01, 0x1a, 0x23,
+	0x95, 0x05, 0x3a, 0x50, 0x31, 0xbd, 0x0f, 0x8d, 0x03, 0x95, 0xfa, 0xc7, 0x2f, 0x13, 0xc9, 0x48,
+	0x93, 0xb4, 0x37, 0x7a, 0xeb, 0x1d, 0x73, 0xf7, 0x02, 0xf2, 0xc2, 0xa6, 0x77, 0x80, 0x3c, 0x61,
+	0xd5, 0x26, 0x69, 0x13, 0x4e, 0x9e, 0x18, 0xc5, 0xd9, 0xca, 0x5c, 0x71, 0xa3, 0x3c, 0x56, 0x9b,
+	0x2b, 0x8f, 0x3a, 0x50, 0xe5, 0x47, 0x27, 0xac, 0xde, 0x24, 0x6d, 0x8b, 0x9b, 0xb2, 0xf5, 0x07,
+	0x81, 0xfa, 0x23, 0x6f, 0x10, 0x5f, 0x2a, 0x4a, 0x61, 0x65, 0xa8, 0xfc, 0xf9, 0xdb, 0x6c, 0x8e,
+	0x35, 0xdd, 0x84, 0xda, 0x20, 0x3b, 0x0c, 0x52, 0x66, 0x35, 0x49, 0xbb, 0xc1, 0xe7, 0xc2, 0x5c,
+	0xf9, 0xad, 0x88, 0x24, 0xbe, 0xd3, 0xe6, 0x58, 0x53, 0x06, 0xab, 0xe6, 0xff, 0x1b, 0x19, 0xe3,
+	0xcb, 0x6b, 0x7c, 0x21, 0x69, 0x13, 0xd6, 0x0e, 0x54, 0xac, 0x65, 0xac, 0xb1, 0x99, 0x1a, 0xde,
+	0x54, 0x46, 0xf4, 0x1e, 0xd8, 0x07, 0xa9, 0x14, 0x5a, 0xfa, 0x7b, 0x9a, 0xad, 0x36, 0x49, 0xbb,
+	0xca, 0x97, 0x80, 0xba, 0x00, 0x43, 0xe5, 0x07, 0x97, 0x01, 0xda, 0x0d, 0xb4, 0x4b, 0x84, 0xde,
+	0x85, 0xc6, 0xfe, 0x4b, 0x2d, 0xbd, 0xe0, 0x95, 0x64, 0x36, 0xba, 0x85, 0x6e, 0xfd, 0x4d, 0xc0,
+	0x1e, 0x85, 0x62, 0x2c, 0x23, 0x19, 0x6b, 0xb3, 0xee, 0x91, 0xca, 0x76, 0xb1, 0x43, 0xc2, 0xb1,
+	0xce, 0x59, 0x97, 0x59, 0x05, 0xeb, 0xe6, 0xac, 0x97, 0xcf, 0x14, 0x6b, 0xfa, 0x3e, 0xd4, 0xbd,
+	0xb1, 0x08, 0xe5, 0x2e, 0xb6, 0x67, 0xf1, 0x5c, 0x15, 0xbc, 0xcb, 0x6a, 0x25, 0xde, 0x2d, 0x78,
+	0x2f, 0x9f, 0x76, 0xae, 0x0c, 0x7f, 0x38, 0x0d, 0x65, 0x7a, 0x8a, 0x8d, 0x5a, 0x3c, 0x57, 0x05,
+	0x7f, 0xc6, 0x1a, 0x25, 0xfe, 0xac, 0xe0, 0x67, 0xcc, 0x2e, 0xf1, 0x33, 0xfa, 0x31, 0xd4, 0x87,
+	0x52, 0xa7, 0xc1, 0x98, 0xdd, 0xc1, 0x74, 0xac, 0x75, 0x4c, 0x8e, 0xe6, 0x88, 0xe7, 0x56, 0xeb,
+	0x29, 0xc0, 0xbe, 0xf0, 0x27, 0xf2, 0x30, 0x98, 0x04, 0xda, 0x8c, 0x79, 0x2f, 0x4a, 0xc2, 0x40,
+	0x4f, 0xf3, 0x5d, 0xae, 0xf2, 0x25, 0xa0, 0x5b, 0xe0, 0x14, 0x62, 0xa8, 0xfc, 0x69, 0x38, 0xcd,
+	0x70, 0x28, 0x55, 0xfe, 0x1f, 0xde, 0xfa, 0xcd, 0x82, 0xea, 0x31, 0xf7, 0xe8, 0x06, 0x58, 0xa7,
+	0x5d, 0x76, 0x1f, 0xc7, 0x64, 0x9d, 0x76, 0x51, 0xf7, 0xd8, 0x56, 0xae, 0x7b, 0xa8, 0xfb, 0xec,
+	0x93, 0x5c, 0xf7, 0xe9, 0x67, 0x60, 0xe3, 0x18, 0x30, 0x67, 0x3d, 0x5c, 0x37, 0xc3, 0x54, 0x1f,
+	0x73, 0xaf, 0xf3, 0x34, 0xc8, 0xa6, 0x22, 0x2c, 0x7c, 0xbe, 0xbc, 0xb4, 0x34, 0xe4, 0xfe, 0xff,
+	0x0c, 0xf9, 0xc1, 0xed, 0x21, 0x63, 0xd5, 0x67, 0x9f, 0x96, 0x78, 0xdf, 0x84, 0x94, 0x2b, 0x2d,
+	0xb4, 0xec, 0xb2, 0x2f, 0xd0, 0x58, 0xc8, 0xa5, 0xd3, 0x63, 0x5f, 0x96, 0x9d, 0xde, 0xd2, 0xe9,
+	0xb3, 0xaf, 0xca, 0x4e, 0xbf, 0xb5, 0x0b, 0xef, 0xdc, 0x5a, 0x33, 0x5d, 0x07, 0x7b, 0x6f, 0xaa,
+	0x15, 0x02, 0xa7, 0x42, 0x37, 0x00, 0x1e, 0x05, 0x2f, 0xa4, 0x3f, 0xd7, 0xa4, 0xf5, 0x0b, 0x81,
+	0xb5, 0x43, 0xa1, 0x85, 0x27, 0x27, 0x18, 0x48, 0x06, 0xab, 0x26, 0xaa, 0x47, 0x97, 0x19, 0xa6,
+	0x67, 0x85, 0x2f, 0xa4, 0xe9, 0xc0, 0x94, 0xde, 0x2b, 0x8c, 0xcf, 0x0a, 0xcf, 0x95, 0xf9, 0x18,
+	0x06, 0x71, 0x18, 0xc4, 0xd2, 0x3c, 0x06, 0x23, 0x74, 0x87, 0x97, 0x88, 0xd9, 0x63, 0x4f, 0xa7,
+	0x52, 0x44, 0x27, 0x7c, 0x80, 0x89, 0xb1, 0xf9, 0x12, 0xe0, 0x53, 0x43, 0x75, 0x31, 0x38, 0x64,
+	0x80, 0x3b, 0x9b, 0xab, 0xad, 0xd7, 0x64, 0x79, 0xda, 0x50, 0x06, 0x9b, 0x8b, 0xfa, 0xfc, 0x24,
+	0xce, 0x12, 0x39, 0xc6, 0x2f, 0xcd, 0xa9, 0xd0, 0x4d, 0x70, 0x0a, 0xe7, 0x28, 0xf5, 0x65, 0x2a,
+	0x7d, 0x87, 0xd0, 0x7b, 0xc0, 0x0a, 0x3a, 0x0a, 0x45, 0x2c, 0xcf, 0x0f, 0x44, 0xaa, 0x65, 0x16,
+	0x88, 0xd8, 0xa9, 0xd1, 0x8f, 0xe0, 0x83, 0x5b, 0xee, 0xd7, 0xf2, 0xc5, 0xc3, 0x1f, 0x65, 0xcc,
+	0x9d, 0x3a, 0xfd, 0x10, 0xde, 0x2b, 0xcc, 0xc7, 0x52, 0x05, 0xfe, 0xb9, 0x97, 0x3c, 0x97, 0xa9,
+	0x74, 0xe0, 0x5f, 0xab, 0x98, 0x5b, 0xdf, 0x3d, 0xf6, 0x3e, 0x7f, 0xe0, 0xac, 0xed, 0x27, 0x57,
+	0xd7, 0x6e, 0xe5, 0xcd, 0xb5, 0x5b, 0x79, 0x7b, 0xed, 0x92, 0x9f, 0x66, 0x2e, 0xf9, 0x75, 0xe6,
+	0x92, 0xdf, 0x67, 0x2e, 0xb9, 0x9a, 0xb9, 0xe4, 0xcf, 0x99, 0x4b, 0xfe, 0x9a, 0xb9, 0x95, 0xb7,
+	0x33, 0x97, 0xfc, 0x7c, 0xe3, 0x56, 0xae, 0x6e, 0xdc, 0xca, 0x9b, 0x1b, 0xb7, 0x72, 0xb6, 0x3b,
+	0x09, 0xf4, 0xf3, 0xe9, 0x45, 0x67, 0xac, 0xa2, 0x1d, 0x91, 0xea, 0xed, 0x48, 0xfa, 0x81, 0xd8,
+	0x4e, 0x42, 0xa1, 0x2f, 0x55, 0x1a, 0x99, 0x43, 0x78, 0x3b, 0xf3, 0xbf, 0xdf, 0x9e, 0xa8, 0x9d,
+	0xfc, 0xac, 0x7e, 0x6d, 0xad, 0xee, 0x0d, 0x47, 0x1d, 0x4f, 0xfb, 0x17, 0x75, 0x3c, 0x9e, 0xfb,
+	0xff, 0x0c, 0x00, 0xd9, 0x0a, 0xdb, 0xb8, 0xc7, 0x05, 0x00, 0x00,
 }
 
 func (x CordType) String() string {
@@ -0,0 +1,269 @@
+package std
+
+import (
+	"io"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+var (
+	// DataSegmentAttr is the AttrID of TxOps that carry a DataSegment chunk of a larger value.
+	DataSegmentAttr = amp.AttrSpec.With("DataSegment").ID
+)
+
+const (
+	// DefaultSegmentSz is the default max InlineData size of each DataSegment sent by a SegmentSender.
+	DefaultSegmentSz = 1 << 20
+
+	// DefaultMaxSegmentStreamSz is the default max TotalSz of a value accepted by a SegmentAssembler.
+	DefaultMaxSegmentStreamSz = amp.DefaultMaxTxSz
+)
+
+// SegmentKey identifies a value sent in DataSegment chunks.
+type SegmentKey struct {
+	ContextID tag.ID // ContextID shared by each chunk TxMsg
+	CellID    tag.ID // cell the value belongs to
+	ItemID    tag.ID // identifies the value within the cell
+}
+
+// SegmentSender sends a large value as a series of TxMsgs, each holding one DataSegment chunk, so that no single TxMsg
+// approaches the 32-bit TxHeader limits and other TxMsgs can be interleaved on the same transport.
+//
+// Each chunk TxMsg shares the same ContextID and holds a single upsert of (CellID, DataSegmentAttr, ItemID) whose EditID identifies this version of the value.
+type SegmentSender struct {
+	SegmentKey
+	EditID    tag.ID                   // identifies this version of the value; if nil, it is set on the first call to Send()
+	SegmentSz int                      // max bytes per chunk; if <= 0, DefaultSegmentSz is used
+	Progress  func(sent, total uint64) // if set, called after each chunk is sent
+}
+
+// Send reads totalSz bytes from src starting at byte offset fromOfs, passing each chunk TxMsg to send (which takes ownership, as with Transport.SendTx).
+// To resume an interrupted transfer, call Send() again with the same EditID and fromOfs set to the receiver's SegmentStream.Received.
+func (s *SegmentSender) Send(src io.ReaderAt, totalSz, fromOfs uint64, send func(tx *amp.TxMsg) error) error {
+	if fromOfs > totalSz {
+		return amp.ErrCode_BadValue.Errorf("segment offset %d exceeds value size %d", fromOfs, totalSz)
+	}
+	segSz := uint64(s.SegmentSz)
+	if s.SegmentSz <= 0 {
+		segSz = DefaultSegmentSz
+	}
+	if s.EditID.IsNil() {
+		s.EditID = tag.Genesis(tag.Now())
+	}
+
+	if fromOfs == totalSz && totalSz > 0 {
+		return nil
+	}
+
+	seg := DataSegment{
+		ByteSz:     totalSz,
+		InlineData: make([]byte, min(segSz, totalSz-fromOfs)),
+	}
+	for ofs := fromOfs; ; {
+		n := min(segSz, totalSz-ofs)
+		seg.ByteOfs = ofs
+		seg.InlineData = seg.InlineData[:n]
+		if got, err := src.ReadAt(seg.InlineData, int64(ofs)); uint64(got) < n {
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		tx := amp.NewTxMsg(true)
+		tx.SetContextID(s.ContextID)
+		op := amp.TxOp{
+			OpCode: amp.TxOpCode_UpsertElement,
+			TxOpID: amp.TxOpID{
+				CellID: s.CellID,
+				AttrID: DataSegmentAttr,
+				ItemID: s.ItemID,
+				EditID: s.EditID,
+			},
+		}
+		if err := tx.MarshalOp(&op, &seg); err != nil {
+			tx.ReleaseRef()
+			return err
+		}
+		if err := send(tx); err != nil {
+			return err
+		}
+
+		ofs += n
+		if s.Progress != nil {
+			s.Progress(ofs, totalSz)
+		}
+		if ofs >= totalSz {
+			break
+		}
+	}
+	return nil
+}
+
+// SegmentStream is the receiving state of a value sent by a SegmentSender.
+type SegmentStream struct {
+	SegmentKey
+	EditID   tag.ID      // version of the value being received
+	TotalSz  uint64      // total byte size of the value
+	Received uint64      // number of leading bytes received; a sender resumes from here
+	Sink     io.WriterAt // where received bytes are written
+}
+
+// IsComplete returns true once every byte of the value has been received.
+func (stream *SegmentStream) IsComplete() bool {
+	return stream.Received == stream.TotalSz
+}
+
+// Bytes returns the bytes received so far if this stream's Sink is the default in-memory sink, otherwise nil.
+func (stream *SegmentStream) Bytes() []byte {
+	if buf, ok := stream.Sink.(*segmentBuf); ok {
+		return buf.bytes
+	}
+	return nil
+}
+
+// SegmentAssembler reassembles values sent by a SegmentSender.  It is not safe for concurrent use.
+type SegmentAssembler struct {
+
+	// MaxSz is the max TotalSz accepted for a value; if 0, DefaultMaxSegmentStreamSz is used.
+	// Since a peer chooses TotalSz, a value of any size is only accepted if MaxSz < 0.
+	MaxSz int64
+
+	// NewSink returns where the bytes of a new stream are written.  If nil, bytes are held in memory (see SegmentStream.Bytes).
+	NewSink func(stream *SegmentStream) (io.WriterAt, error)
+
+	// Progress, if set, is called after each chunk is written.
+	Progress func(stream *SegmentStream)
+
+	// OnAbandon, if set, is called when an in-progress stream is replaced by a chunk with a new EditID, allowing its Sink to be closed or removed.
+	OnAbandon func(stream *SegmentStream)
+
+	streams map[SegmentKey]*SegmentStream
+}
+
+// Accept writes each DataSegment op in the given tx to its stream, returning the streams that became complete.
+// Ops with an AttrID other than DataSegmentAttr are ignored.  The caller retains ownership of tx.
+//
+// A chunk with a new EditID restarts its stream, abandoning the prior one (see OnAbandon).  Chunks must arrive in order, though a chunk may overlap bytes already received (e.g. after a resume).
+// A completed stream is no longer tracked by this SegmentAssembler.
+func (asm *SegmentAssembler) Accept(tx *amp.TxMsg) ([]*SegmentStream, error) {
+	var completed []*SegmentStream
+	for i, op := range tx.Ops {
+		if op.AttrID != DataSegmentAttr || op.OpCode != amp.TxOpCode_UpsertElement {
+			continue
+		}
+		var seg DataSegment
+		if err := tx.UnmarshalOpValue(i, &seg); err != nil {
+			return completed, err
+		}
+		key := SegmentKey{
+			ContextID: tx.ContextID(),
+			CellID:    op.CellID,
+			ItemID:    op.ItemID,
+		}
+		stream, err := asm.acceptSegment(key, op.EditID, &seg)
+		if err != nil {
+			return completed, err
+		}
+		if stream.IsComplete() {
+			delete(asm.streams, key)
+			completed = append(completed, stream)
+		}
+	}
+	return completed, nil
+}
+
+// Stream returns the in-progress stream with the given key, or nil if there is none.
+func (asm *SegmentAssembler) Stream(key SegmentKey) *SegmentStream {
+	return asm.streams[key]
+}
+
+// Resume tracks a partially received stream (e.g. one restored from a partially written file), so that chunks continuing from stream.Received are accepted.
+func (asm *SegmentAssembler) Resume(stream *SegmentStream) {
+	if asm.streams == nil {
+		asm.streams = make(map[SegmentKey]*SegmentStream)
+	}
+	asm.streams[stream.SegmentKey] = stream
+}
+
+// Cancel stops tracking the given stream, returning it (or nil if it was not in progress).
+func (asm *SegmentAssembler) Cancel(key SegmentKey) *SegmentStream {
+	stream := asm.streams[key]
+	delete(asm.streams, key)
+	return stream
+}
+
+// maxSz returns the max TotalSz accepted for a value, or -1 if there is no limit.
+func (asm *SegmentAssembler) maxSz() int64 {
+	switch {
+	case asm.MaxSz < 0:
+		return -1
+	case asm.MaxSz == 0:
+		return DefaultMaxSegmentStreamSz
+	}
+	return asm.MaxSz
+}
+
+func (asm *SegmentAssembler) acceptSegment(key SegmentKey, editID tag.ID, seg *DataSegment) (*SegmentStream, error) {
+	segEnd := seg.ByteOfs + uint64(len(seg.InlineData))
+	if segEnd < seg.ByteOfs || segEnd > seg.ByteSz {
+		return nil, amp.ErrCode_BadValue.Errorf("segment [%d, %d) exceeds value size %d", seg.ByteOfs, segEnd, seg.ByteSz)
+	}
+
+	stream := asm.streams[key]
+	if stream == nil || stream.EditID != editID {
+		prev := stream
+		if seg.ByteOfs != 0 {
+			return nil, amp.ErrCode_BadValue.Errorf("segment stream starts at offset %d", seg.ByteOfs)
+		}
+		if maxSz := asm.maxSz(); maxSz >= 0 && seg.ByteSz > uint64(maxSz) {
+			return nil, amp.ErrCode_BadValue.Errorf("segment stream size %d exceeds limit %d", seg.ByteSz, maxSz)
+		}
+		stream = &SegmentStream{
+			SegmentKey: key,
+			EditID:     editID,
+			TotalSz:    seg.ByteSz,
+		}
+		var err error
+		if asm.NewSink != nil {
+			stream.Sink, err = asm.NewSink(stream)
+		} else {
+			stream.Sink = &segmentBuf{}
+		}
+		if err != nil {
+			return nil, err
+		}
+		asm.Resume(stream)
+		if prev != nil && asm.OnAbandon != nil {
+			asm.OnAbandon(prev)
+		}
+	} else if seg.ByteSz != stream.TotalSz {
+		return nil, amp.ErrCode_BadValue.Errorf("segment stream size changed from %d to %d", stream.TotalSz, seg.ByteSz)
+	} else if seg.ByteOfs > stream.Received {
+		return nil, amp.ErrCode_BadValue.Errorf("segment at offset %d skips bytes from %d", seg.ByteOfs, stream.Received)
+	}
+
+	if len(seg.InlineData) > 0 {
+		if _, err := stream.Sink.WriteAt(seg.InlineData, int64(seg.ByteOfs)); err != nil {
+			return nil, err
+		}
+	}
+	stream.Received = max(stream.Received, segEnd)
+	if asm.Progress != nil {
+		asm.Progress(stream)
+	}
+	return stream, nil
+}
+
+// segmentBuf is an in-memory io.WriterAt that grows only as bytes are written.
+type segmentBuf struct {
+	bytes []byte
+}
+
+func (buf *segmentBuf) WriteAt(p []byte, ofs int64) (int, error) {
+	if end := int(ofs) + len(p); end > len(buf.bytes) {
+		buf.bytes = append(buf.bytes, make([]byte, end-len(buf.bytes))...)
+	}
+	return copy(buf.bytes[ofs:], p), nil
+}
@@ -27,13 +27,18 @@ func PinAndServe[AppT amp.AppInstance](cell Cell[AppT], app AppT, op amp.Request
 		children: make(map[tag.ID]Cell[AppT]),
 	}
 
+	// AppContext methods are called through an interface value rather than through AppT:
+	// the linker drops a method promoted from an embedded interface that is only called via a type parameter,
+	// which then fails at run time with "unreachable method called" (see TestPinAndServeDebugMode).
+	var appCtx amp.AppContext = app
+
 	label := "pin: " + root.ID.Base32Suffix()
-	if app.Info().DebugMode {
+	if appCtx.Info().DebugMode {
 		label += fmt.Sprintf(", Cell.(*%v)", reflect.TypeOf(cell).Elem().Name())
 	}
 
 	var err error
-	pin.ctx, err = app.StartChild(&task.Task{
+	pin.ctx, err = appCtx.StartChild(&task.Task{
 		Info: task.Info{
 			Label:     label,
 			IdleClose: time.Microsecond,
@@ -0,0 +1,300 @@
+package std
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// DefaultTokenRefreshLead is how long before its Expiry a TokenStore refreshes an access token.
+const DefaultTokenRefreshLead = time.Minute
+
+// TokenRefresher exchanges a LoginCheckpoint's RefreshToken for a new access token -- concurrency safe.
+type TokenRefresher interface {
+
+	// RefreshToken returns a checkpoint holding a new AccessToken and Expiry.
+	// If the returned RefreshToken is empty, the given checkpoint's RefreshToken remains in use.
+	RefreshToken(ctx context.Context, checkpoint *amp.LoginCheckpoint) (*amp.LoginCheckpoint, error)
+}
+
+// TokenStoreOpts configures a TokenStore.
+type TokenStoreOpts struct {
+	Dir         string         // directory holding each user's checkpoint, typically AppContext.LocalDataPath()
+	Refresher   TokenRefresher // refreshes tokens nearing Expiry; if nil, an expired token is reported as ErrCode_SessionExpired
+	RefreshLead time.Duration  // tokens are refreshed this long before Expiry; if <= 0, DefaultTokenRefreshLead is used
+}
+
+// TokenStore persists a LoginCheckpoint for each user and refreshes its access token before it expires -- concurrency safe.
+//
+// A refresh is made without holding the store's lock, so a slow token endpoint only delays Get calls for the user being refreshed,
+// which await that refresh rather than each making their own.
+type TokenStore struct {
+	opts TokenStoreOpts
+
+	mu         sync.Mutex
+	tokens     map[string]*amp.LoginCheckpoint // cached checkpoints by UserID
+	refreshing map[string]*tokenRefresh        // refreshes in progress by UserID
+}
+
+// tokenRefresh is a refresh of a user's access token in progress.
+type tokenRefresh struct {
+	done       chan struct{}        // closed once the refresh completes
+	checkpoint *amp.LoginCheckpoint // set once done if the refresh succeeded
+	err        error                // set once done if the refresh failed
+}
+
+// NewTokenStore returns a TokenStore that persists checkpoints within opts.Dir, creating it if needed.
+func NewTokenStore(opts TokenStoreOpts) (*TokenStore, error) {
+	if opts.Dir == "" {
+		return nil, amp.ErrCode_StorageFailure.Error("TokenStore requires a data directory")
+	}
+	if opts.RefreshLead <= 0 {
+		opts.RefreshLead = DefaultTokenRefreshLead
+	}
+	if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+		return nil, amp.ErrCode_StorageFailure.Wrap(err)
+	}
+	return &TokenStore{
+		opts:       opts,
+		tokens:     make(map[string]*amp.LoginCheckpoint),
+		refreshing: make(map[string]*tokenRefresh),
+	}, nil
+}
+
+// Put stores the given checkpoint (typically from a completed OAuth sign-in) under its UserID, replacing any previous one.
+func (ts *TokenStore) Put(checkpoint *amp.LoginCheckpoint) error {
+	if checkpoint.UserID == "" {
+		return amp.ErrCode_BadValue.Error("LoginCheckpoint is missing UserID")
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.save(checkpoint)
+}
+
+// Get returns the given user's checkpoint, first refreshing its access token if it expires within RefreshLead.
+//
+// amp.ErrNoAuthToken is returned if the user has no checkpoint, and an ErrCode_SessionExpired error if the token has expired and can't be refreshed.
+func (ts *TokenStore) Get(ctx context.Context, userID string) (*amp.LoginCheckpoint, error) {
+	ts.mu.Lock()
+	checkpoint, err := ts.load(userID)
+	if err != nil {
+		ts.mu.Unlock()
+		return nil, err
+	}
+	if checkpoint.Expiry == 0 || time.Until(time.Unix(checkpoint.Expiry, 0)) > ts.opts.RefreshLead {
+		ts.mu.Unlock()
+		return checkpoint, nil
+	}
+	if ts.opts.Refresher == nil || checkpoint.RefreshToken == "" {
+		ts.mu.Unlock()
+		return nil, amp.ErrCode_SessionExpired.Errorf("access token for %q expired and can't be refreshed", userID)
+	}
+
+	// Await a refresh already in progress for this user
+	if op := ts.refreshing[userID]; op != nil {
+		ts.mu.Unlock()
+		select {
+		case <-op.done:
+		case <-ctx.Done():
+			return nil, amp.ErrCode_SessionExpired.Wrap(ctx.Err())
+		}
+		if op.err != nil {
+			return nil, op.err
+		}
+		dupe := *op.checkpoint
+		return &dupe, nil
+	}
+	op := &tokenRefresh{
+		done: make(chan struct{}),
+	}
+	ts.refreshing[userID] = op
+	ts.mu.Unlock()
+
+	refreshed, err := ts.refresh(ctx, checkpoint)
+
+	ts.mu.Lock()
+	delete(ts.refreshing, userID)
+	if err == nil {
+		err = ts.saveRefreshed(checkpoint, refreshed)
+	}
+	if err != nil {
+		refreshed = nil
+	}
+	op.checkpoint, op.err = refreshed, err
+	ts.mu.Unlock()
+	close(op.done)
+
+	if err != nil {
+		return nil, err
+	}
+	dupe := *refreshed
+	return &dupe, nil
+}
+
+// refresh exchanges the given checkpoint's RefreshToken for a new access token, returning the refreshed checkpoint -- called without ts.mu held.
+func (ts *TokenStore) refresh(ctx context.Context, checkpoint *amp.LoginCheckpoint) (*amp.LoginCheckpoint, error) {
+	refreshed, err := ts.opts.Refresher.RefreshToken(ctx, checkpoint)
+	if err != nil {
+		return nil, amp.ErrCode_SessionExpired.Wrap(err)
+	}
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = checkpoint.RefreshToken
+	}
+	refreshed.UserID = checkpoint.UserID
+	if refreshed.URI == "" {
+		refreshed.URI = checkpoint.URI
+	}
+	return refreshed, nil
+}
+
+// saveRefreshed stores the checkpoint refreshed from prev unless the user's checkpoint was replaced or deleted during the refresh -- caller holds ts.mu.
+func (ts *TokenStore) saveRefreshed(prev, refreshed *amp.LoginCheckpoint) error {
+	if cur := ts.tokens[prev.UserID]; cur == nil || cur.AccessToken != prev.AccessToken || cur.RefreshToken != prev.RefreshToken {
+		return nil
+	}
+	return ts.save(refreshed)
+}
+
+// Delete removes the given user's checkpoint (e.g. on sign-out).
+func (ts *TokenStore) Delete(userID string) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	delete(ts.tokens, userID)
+	if err := os.Remove(ts.pathFor(userID)); err != nil && !os.IsNotExist(err) {
+		return amp.ErrCode_StorageFailure.Wrap(err)
+	}
+	return nil
+}
+
+// pathFor returns the file holding the given user's checkpoint.
+func (ts *TokenStore) pathFor(userID string) string {
+	return filepath.Join(ts.opts.Dir, tag.FromLiteral([]byte(userID)).Base32()+".checkpoint")
+}
+
+// load returns a copy of the given user's checkpoint, reading it from disk if not cached.
+func (ts *TokenStore) load(userID string) (*amp.LoginCheckpoint, error) {
+	checkpoint := ts.tokens[userID]
+	if checkpoint == nil {
+		buf, err := os.ReadFile(ts.pathFor(userID))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, amp.ErrNoAuthToken
+			}
+			return nil, amp.ErrCode_StorageFailure.Wrap(err)
+		}
+		checkpoint = &amp.LoginCheckpoint{}
+		if err = checkpoint.Unmarshal(buf); err != nil {
+			return nil, amp.ErrCode_StorageFailure.Wrap(err)
+		}
+		ts.tokens[userID] = checkpoint
+	}
+	dupe := *checkpoint
+	return &dupe, nil
+}
+
+// save writes the given checkpoint to disk (atomically replacing the previous file) and caches a copy.
+func (ts *TokenStore) save(checkpoint *amp.LoginCheckpoint) error {
+	buf, err := checkpoint.Marshal()
+	if err != nil {
+		return err
+	}
+	pathname := ts.pathFor(checkpoint.UserID)
+	tmp := pathname + ".tmp"
+	if err = os.WriteFile(tmp, buf, 0o600); err == nil {
+		err = os.Rename(tmp, pathname)
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return amp.ErrCode_StorageFailure.Wrap(err)
+	}
+
+	dupe := *checkpoint
+	ts.tokens[checkpoint.UserID] = &dupe
+	return nil
+}
+
+// OAuth2Refresher is a TokenRefresher that uses the OAuth 2.0 refresh_token grant (RFC 6749, section 6).
+type OAuth2Refresher struct {
+	TokenURL     string       // token endpoint of the authorization server
+	ClientID     string       // OAuth client ID
+	ClientSecret string       // if set, the client authenticates using HTTP Basic auth
+	Client       *http.Client // if nil, http.DefaultClient is used
+}
+
+// oauth2Token is the token endpoint response -- RFC 6749, section 5.1
+type oauth2Token struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int64  `json:"expires_in"`
+	Error        string `json:"error"`
+	ErrorDesc    string `json:"error_description"`
+}
+
+// Implements TokenRefresher
+func (r *OAuth2Refresher) RefreshToken(ctx context.Context, checkpoint *amp.LoginCheckpoint) (*amp.LoginCheckpoint, error) {
+	form := url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {checkpoint.RefreshToken},
+	}
+	if r.ClientSecret == "" && r.ClientID != "" {
+		form.Set("client_id", r.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if r.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(r.ClientID), url.QueryEscape(r.ClientSecret))
+	}
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, amp.ErrCode_NotConnected.Wrap(err)
+	}
+	defer resp.Body.Close()
+
+	var token oauth2Token
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err == nil {
+		err = json.Unmarshal(body, &token)
+	}
+	switch {
+	case token.Error != "":
+		return nil, amp.ErrCode_AuthFailed.Errorf("token refresh failed: %s %s", token.Error, token.ErrorDesc)
+	case resp.StatusCode != http.StatusOK:
+		return nil, amp.ErrCode_AuthFailed.Errorf("token refresh failed: %s", resp.Status)
+	case err != nil:
+		return nil, amp.ErrCode_AuthFailed.Errorf("token refresh failed: %v", err)
+	case token.AccessToken == "":
+		return nil, amp.ErrCode_AuthFailed.Error("token refresh failed: missing access_token")
+	}
+
+	refreshed := &amp.LoginCheckpoint{
+		TokenType:    token.TokenType,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}
+	if token.ExpiresIn > 0 {
+		refreshed.Expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second).Unix()
+	}
+	return refreshed, nil
+}
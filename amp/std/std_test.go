@@ -0,0 +1,363 @@
+package std_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/std"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/task"
+)
+
+func TestSegments(t *testing.T) {
+	value := make([]byte, 100_000)
+	for i := range value {
+		value[i] = byte(i * 7)
+	}
+
+	sender := std.SegmentSender{
+		SegmentKey: std.SegmentKey{
+			ContextID: tag.Now(),
+			CellID:    tag.ID{0, 0, 42},
+			ItemID:    tag.ID{0, 0, 1},
+		},
+		SegmentSz: 16 << 10,
+	}
+
+	var (
+		asm       std.SegmentAssembler
+		completed []*std.SegmentStream
+		progress  int
+	)
+	asm.Progress = func(stream *std.SegmentStream) {
+		progress++
+	}
+
+	// relay each chunk TxMsg through the wire format and into the assembler, breaking the "connection" after 3 chunks
+	errBroken := errors.New("broken")
+	breakAfter := 3
+	relay := func(tx *amp.TxMsg) error {
+		defer tx.ReleaseRef()
+		if breakAfter == 0 {
+			return errBroken
+		}
+		breakAfter--
+
+		var buf []byte
+		tx.MarshalToBuffer(&buf)
+		tx2, err := amp.ReadTxMsg(bytes.NewReader(buf))
+		if err != nil {
+			return err
+		}
+		defer tx2.ReleaseRef()
+		done, err := asm.Accept(tx2)
+		completed = append(completed, done...)
+		return err
+	}
+
+	if err := sender.Send(bytes.NewReader(value), uint64(len(value)), 0, relay); err != errBroken {
+		t.Fatalf("expected broken send, got %v", err)
+	}
+	stream := asm.Stream(sender.SegmentKey)
+	if stream == nil || stream.Received != 3*16<<10 || stream.IsComplete() {
+		t.Fatalf("expected partial stream")
+	}
+
+	// resume where the receiver left off
+	breakAfter = -1
+	if err := sender.Send(bytes.NewReader(value), uint64(len(value)), stream.Received, relay); err != nil {
+		t.Fatal(err)
+	}
+	if len(completed) != 1 || !bytes.Equal(completed[0].Bytes(), value) {
+		t.Fatalf("reassembled value mismatch")
+	}
+	if progress != 7 || asm.Stream(sender.SegmentKey) != nil {
+		t.Fatalf("unexpected progress or stream state")
+	}
+
+	// a chunk that skips ahead is refused
+	sender.EditID = tag.Genesis(tag.Now())
+	if err := sender.Send(bytes.NewReader(value), uint64(len(value)), 16<<10, relay); err == nil {
+		t.Fatalf("expected stream not starting at 0 to be refused")
+	}
+
+	// a stream claiming a size beyond MaxSz is refused before anything is buffered, unless there is explicitly no limit
+	huge := amp.NewTxMsg(true)
+	defer huge.ReleaseRef()
+	huge.SetContextID(sender.ContextID)
+	err := huge.MarshalOp(&amp.TxOp{
+		OpCode: amp.TxOpCode_UpsertElement,
+		TxOpID: amp.TxOpID{
+			CellID: sender.CellID,
+			AttrID: std.DataSegmentAttr,
+			ItemID: sender.ItemID,
+			EditID: tag.Genesis(tag.Now()),
+		},
+	}, &std.DataSegment{
+		ByteSz:     std.DefaultMaxSegmentStreamSz + 1,
+		InlineData: value[:10],
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = asm.Accept(huge); amp.GetErrCode(err) != amp.ErrCode_BadValue {
+		t.Fatalf("expected ErrCode_BadValue, got %v", err)
+	}
+	asm.MaxSz = -1
+	if _, err = asm.Accept(huge); err != nil {
+		t.Fatal(err)
+	}
+
+	// a new version of the value abandons the stream in progress
+	var abandoned []*std.SegmentStream
+	asm.OnAbandon = func(stream *std.SegmentStream) {
+		abandoned = append(abandoned, stream)
+	}
+	inProgress := asm.Stream(sender.SegmentKey)
+	sender.EditID = tag.Genesis(tag.Now())
+	breakAfter = 1
+	if err = sender.Send(bytes.NewReader(value), uint64(len(value)), 0, relay); err != errBroken {
+		t.Fatalf("expected broken send, got %v", err)
+	}
+	if len(abandoned) != 1 || abandoned[0] != inProgress || asm.Stream(sender.SegmentKey).EditID != sender.EditID {
+		t.Fatalf("expected the prior stream to be abandoned")
+	}
+}
+
+func TestTokenStore(t *testing.T) {
+	var refreshes atomic.Int32
+	oauth := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, secret, _ := r.BasicAuth()
+		if r.FormValue("grant_type") != "refresh_token" || id != "client" || secret != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_request"})
+			return
+		}
+		if r.FormValue("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		refreshes.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "access-2",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+	}))
+	defer oauth.Close()
+
+	dir := t.TempDir()
+	opts := std.TokenStoreOpts{
+		Dir: dir,
+		Refresher: &std.OAuth2Refresher{
+			TokenURL:     oauth.URL,
+			ClientID:     "client",
+			ClientSecret: "secret",
+		},
+	}
+	tokens, err := std.NewTokenStore(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if _, err = tokens.Get(ctx, "alice"); err != amp.ErrNoAuthToken {
+		t.Fatalf("expected ErrNoAuthToken, got %v", err)
+	}
+
+	// a token expiring within RefreshLead is refreshed, keeping its refresh token
+	err = tokens.Put(&amp.LoginCheckpoint{
+		UserID:       "alice",
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		Expiry:       time.Now().Add(10 * time.Second).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := tokens.Get(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.AccessToken != "access-2" || token.RefreshToken != "refresh-1" || token.UserID != "alice" {
+		t.Fatalf("unexpected refreshed token %v", token)
+	}
+	if time.Until(time.Unix(token.Expiry, 0)) < 59*time.Minute {
+		t.Fatalf("unexpected refreshed expiry %v", token.Expiry)
+	}
+
+	// a fresh token is returned as-is, and checkpoints persist across stores
+	reopened, err := std.NewTokenStore(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token, err = reopened.Get(ctx, "alice"); err != nil || token.AccessToken != "access-2" {
+		t.Fatalf("expected persisted token, got %v %v", token, err)
+	}
+	if n := refreshes.Load(); n != 1 {
+		t.Fatalf("expected 1 refresh, got %d", n)
+	}
+
+	// a rejected refresh or a missing refresh token reports ErrCode_SessionExpired
+	expired := time.Now().Add(-time.Minute).Unix()
+	tokens.Put(&amp.LoginCheckpoint{UserID: "bob", AccessToken: "a", RefreshToken: "revoked", Expiry: expired})
+	if _, err = tokens.Get(ctx, "bob"); amp.GetErrCode(err) != amp.ErrCode_SessionExpired {
+		t.Fatalf("expected ErrCode_SessionExpired, got %v", err)
+	}
+	tokens.Put(&amp.LoginCheckpoint{UserID: "carol", AccessToken: "a", Expiry: expired})
+	if _, err = tokens.Get(ctx, "carol"); amp.GetErrCode(err) != amp.ErrCode_SessionExpired {
+		t.Fatalf("expected ErrCode_SessionExpired, got %v", err)
+	}
+
+	if err = tokens.Delete("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tokens.Get(ctx, "alice"); err != amp.ErrNoAuthToken {
+		t.Fatalf("expected ErrNoAuthToken after Delete, got %v", err)
+	}
+}
+
+// stalledRefresher is a TokenRefresher that blocks each refresh until release is closed.
+type stalledRefresher struct {
+	started   chan string
+	release   chan struct{}
+	refreshes atomic.Int32
+}
+
+func (r *stalledRefresher) RefreshToken(ctx context.Context, checkpoint *amp.LoginCheckpoint) (*amp.LoginCheckpoint, error) {
+	r.refreshes.Add(1)
+	r.started <- checkpoint.UserID
+	<-r.release
+	return &amp.LoginCheckpoint{
+		AccessToken: checkpoint.AccessToken + "-refreshed",
+		Expiry:      time.Now().Add(time.Hour).Unix(),
+	}, nil
+}
+
+func TestTokenStoreConcurrentRefresh(t *testing.T) {
+	refresher := &stalledRefresher{
+		started: make(chan string, 4),
+		release: make(chan struct{}),
+	}
+	tokens, err := std.NewTokenStore(std.TokenStoreOpts{
+		Dir:       t.TempDir(),
+		Refresher: refresher,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	expired := time.Now().Add(-time.Minute).Unix()
+	tokens.Put(&amp.LoginCheckpoint{UserID: "alice", AccessToken: "a", RefreshToken: "r", Expiry: expired})
+	tokens.Put(&amp.LoginCheckpoint{UserID: "bob", AccessToken: "b", Expiry: time.Now().Add(time.Hour).Unix()})
+
+	// concurrent Gets for the same user share a single refresh
+	const N = 4
+	results := make(chan *amp.LoginCheckpoint, N)
+	for range N {
+		go func() {
+			token, err := tokens.Get(ctx, "alice")
+			if err != nil {
+				t.Error(err)
+			}
+			results <- token
+		}()
+	}
+	if userID := <-refresher.started; userID != "alice" {
+		t.Fatalf("unexpected refresh for %q", userID)
+	}
+
+	// a stalled refresh doesn't block other users
+	if token, err := tokens.Get(ctx, "bob"); err != nil || token.AccessToken != "b" {
+		t.Fatalf("expected bob's token during alice's refresh, got %v %v", token, err)
+	}
+	if err = tokens.Put(&amp.LoginCheckpoint{UserID: "carol", AccessToken: "c"}); err != nil {
+		t.Fatal(err)
+	}
+
+	close(refresher.release)
+	for range N {
+		if token := <-results; token == nil || token.AccessToken != "a-refreshed" {
+			t.Fatalf("unexpected refreshed token %v", token)
+		}
+	}
+	if n := refresher.refreshes.Load(); n != 1 {
+		t.Fatalf("expected 1 refresh, got %d", n)
+	}
+
+	// a refresh completing after the user's checkpoint was deleted doesn't restore it
+	tokens.Put(&amp.LoginCheckpoint{UserID: "dave", AccessToken: "d", RefreshToken: "r", Expiry: expired})
+	refresher.release = make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := tokens.Get(ctx, "dave")
+		done <- err
+	}()
+	<-refresher.started
+	if err = tokens.Delete("dave"); err != nil {
+		t.Fatal(err)
+	}
+	close(refresher.release)
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tokens.Get(ctx, "dave"); err != amp.ErrNoAuthToken {
+		t.Fatalf("expected ErrNoAuthToken after Delete, got %v", err)
+	}
+}
+
+// debugAppContext is an amp.AppContext in DebugMode whose StartChild records the label of the given task and refuses it.
+type debugAppContext struct {
+	amp.AppContext
+	label string
+}
+
+func (ctx *debugAppContext) Info() task.Info {
+	return task.Info{DebugMode: true}
+}
+
+func (ctx *debugAppContext) StartChild(task *task.Task) (task.Context, error) {
+	ctx.label = task.Info.Label
+	return nil, amp.ErrShuttingDown
+}
+
+type debugApp struct {
+	std.App[*debugApp]
+}
+
+func (app *debugApp) ServeRequest(op amp.Requester) (amp.Pin, error) {
+	return app.PinAndServe(&debugCell{}, op)
+}
+
+type debugCell struct {
+	std.CellNode[*debugApp]
+}
+
+func (cell *debugCell) PinInto(pin *std.Pin[*debugApp]) error { return nil }
+func (cell *debugCell) MarshalAttrs(w std.CellWriter)         {}
+
+func TestPinAndServeDebugMode(t *testing.T) {
+	appCtx := &debugAppContext{}
+	app := &debugApp{}
+	app.AppContext = appCtx
+	app.Instance = app
+
+	// a DebugMode app labels each pin's task with the pinned Cell's type
+	if _, err := app.ServeRequest(nil); err != amp.ErrShuttingDown {
+		t.Fatalf("expected StartChild error, got %v", err)
+	}
+	if !strings.HasSuffix(appCtx.label, ", Cell.(*debugCell)") {
+		t.Fatalf("unexpected pin label %q", appCtx.label)
+	}
+}
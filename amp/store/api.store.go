@@ -0,0 +1,54 @@
+// Package store offers stores that apply TxMsgs to cell state, keyed by TxOpID (CellID / AttrID / ItemID / EditID).
+package store
+
+import (
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// Entry is the current state of a single cell attribute element.
+type Entry struct {
+	amp.TxOpID        // element ID and the EditID of the current value
+	Value      []byte // serialized tag.Value; read-only
+}
+
+// Load unmarshals this entry's value into dst.
+func (e *Entry) Load(dst tag.Value) error {
+	return dst.Unmarshal(e.Value)
+}
+
+// Reader is implemented by stores that offer point lookups and ordered range scans of cell elements.
+type Reader interface {
+
+	// Get returns the current entry for the given element, or ErrElementNotFound if it is not present.
+	Get(elemID amp.ElementID) (Entry, error)
+
+	// ScanCell calls fn for each element of the given cell in ascending AttrID / ItemID order until fn returns false.
+	ScanCell(cellID tag.ID, fn func(e *Entry) bool) error
+
+	// ScanAttr calls fn for each element of the given cell attribute in ascending ItemID order until fn returns false.
+	ScanAttr(cellID, attrID tag.ID, fn func(e *Entry) bool) error
+
+	// Revisions returns the revision graph of the given element, including superseded and deleting edits still held by this store.
+	Revisions(elemID amp.ElementID) (*RevisionGraph, error)
+}
+
+// Store is a Reader that TxMsgs are applied to.
+type Store interface {
+	Reader
+
+	// Apply adds each TxOp of the given TxMsg as a revision of its element: TxOpCode_UpsertElement sets an element and TxOpCode_DeleteElement removes it.
+	// When an element has concurrent revisions, its current revision is resolved deterministically (see RevisionGraph).
+	// The tx is validated before any op is applied, so a tx is either applied in full or not at all.
+	// The caller retains ownership of tx.
+	Apply(tx *amp.TxMsg) error
+
+	// Snapshot returns a TxMsg that reproduces the current state of this store when applied to an empty store.
+	Snapshot() (*amp.TxMsg, error)
+}
+
+var (
+	ErrElementNotFound = amp.ErrCode_AttrNotFound.Error("element not found")
+	ErrSealedTx        = amp.ErrCode_BadRequest.Error("sealed tx must be opened before it is applied")
+	ErrStoreClosed     = amp.ErrCode_ShuttingDown.Error("store is closed")
+)
@@ -0,0 +1,225 @@
+package store
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// CellStore is an ordered in-memory Store -- concurrency safe.
+//
+// Every applied revision of an element is retained and an element's current value is resolved from its revision graph (see RevisionGraph),
+// so replicas that apply the same TxMsgs -- in any order -- hold the same state.
+type CellStore struct {
+	mu   sync.RWMutex
+	revs []Revision // sorted by TxOpID
+}
+
+// NewCellStore returns an empty in-memory Store.
+func NewCellStore() *CellStore {
+	return &CellStore{}
+}
+
+// Len returns the number of elements in this store.
+func (cs *CellStore) Len() int {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	count := 0
+	cs.scan(amp.ElementID{}, 0, func(rev *Revision) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// Implements Store
+func (cs *CellStore) Apply(tx *amp.TxMsg) error {
+	if err := checkTx(tx); err != nil {
+		return err
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	seed := tx.GenesisID()
+	for i := range tx.Ops {
+		op := &tx.Ops[i]
+		rev := Revision{
+			Entry: Entry{
+				TxOpID: op.TxOpID,
+			},
+			Seed:    seed,
+			Deleted: op.OpCode == amp.TxOpCode_DeleteElement,
+		}
+		if !rev.Deleted {
+			rev.Value = append([]byte(nil), tx.DataStore[op.DataOfs:op.DataOfs+op.DataLen]...)
+		}
+
+		idx, found := cs.find(&op.TxOpID)
+		if found {
+			cs.revs[idx] = rev
+		} else {
+			cs.revs = append(cs.revs, Revision{})
+			copy(cs.revs[idx+1:], cs.revs[idx:])
+			cs.revs[idx] = rev
+		}
+	}
+	return nil
+}
+
+// Implements Reader
+func (cs *CellStore) Get(elemID amp.ElementID) (Entry, error) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	var entry *Entry
+	cs.scan(elemID, len(elemID), func(rev *Revision) bool {
+		entry = &rev.Entry
+		return false
+	})
+	if entry == nil {
+		return Entry{}, ErrElementNotFound
+	}
+	return *entry, nil
+}
+
+// Implements Reader
+func (cs *CellStore) ScanCell(cellID tag.ID, fn func(e *Entry) bool) error {
+	cs.scanEntries(amp.ElementID{cellID}, 1, fn)
+	return nil
+}
+
+// Implements Reader
+func (cs *CellStore) ScanAttr(cellID, attrID tag.ID, fn func(e *Entry) bool) error {
+	cs.scanEntries(amp.ElementID{cellID, attrID}, 2, fn)
+	return nil
+}
+
+// Implements Reader
+func (cs *CellStore) Revisions(elemID amp.ElementID) (*RevisionGraph, error) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	start, end := cs.elementRange(elemID)
+	if start == end {
+		return nil, ErrElementNotFound
+	}
+	return Resolve(cs.revs[start:end]), nil
+}
+
+// Implements Store
+func (cs *CellStore) Snapshot() (*amp.TxMsg, error) {
+	cs.mu.RLock()
+	defer cs.mu.RUnlock()
+
+	tx := amp.NewTxMsg(true)
+	tx.Status = amp.OpStatus_Synced
+	cs.scan(amp.ElementID{}, 0, func(rev *Revision) bool {
+		op := amp.TxOp{
+			TxOpID: rev.TxOpID,
+			OpCode: amp.TxOpCode_UpsertElement,
+		}
+		tx.MarshalOpWithBuf(&op, rev.Value)
+		return true
+	})
+	tx.OpsSorted = true
+	return tx, nil
+}
+
+// scanEntries calls fn with a copy of the current entry of each element whose leading prefixLen IDs match those of prefix.
+func (cs *CellStore) scanEntries(prefix amp.ElementID, prefixLen int, fn func(e *Entry) bool) {
+	cs.scan(prefix, prefixLen, func(rev *Revision) bool {
+		entry := rev.Entry
+		return fn(&entry)
+	})
+}
+
+// scan calls fn with the current revision of each element (skipping deleted elements) whose leading prefixLen IDs match those of prefix.
+func (cs *CellStore) scan(prefix amp.ElementID, prefixLen int, fn func(rev *Revision) bool) {
+	start, _ := cs.find(&amp.TxOpID{CellID: prefix[0], AttrID: prefix[1], ItemID: prefix[2]})
+	scanElements(start, len(cs.revs), prefix, prefixLen,
+		func(i int) (*amp.TxOpID, tag.ID) {
+			return &cs.revs[i].TxOpID, cs.revs[i].Seed
+		},
+		func(cur int) bool {
+			rev := &cs.revs[cur]
+			return rev.Deleted || fn(rev)
+		})
+}
+
+// elementRange returns the range of revisions of the given element.
+func (cs *CellStore) elementRange(elemID amp.ElementID) (start, end int) {
+	start, _ = cs.find(&amp.TxOpID{CellID: elemID[0], AttrID: elemID[1], ItemID: elemID[2]})
+	end = start
+	for end < len(cs.revs) && cs.revs[end].ElementID() == elemID {
+		end++
+	}
+	return start, end
+}
+
+// find returns the index of the first revision >= opID and whether it is an exact match.
+func (cs *CellStore) find(opID *amp.TxOpID) (int, bool) {
+	return sort.Find(len(cs.revs), func(i int) int {
+		return opID.CompareTo(&cs.revs[i].TxOpID)
+	})
+}
+
+// scanElements walks revisions sorted by TxOpID from index start, calling fn with the index of the current revision of each element
+// whose leading prefixLen IDs match those of prefix.  rev returns the TxOpID and seed of the revision at the given index.
+func scanElements(start, n int, prefix amp.ElementID, prefixLen int, rev func(i int) (*amp.TxOpID, tag.ID), fn func(cur int) bool) {
+	for idx := start; idx < n; {
+		opID, _ := rev(idx)
+		elemID := opID.ElementID()
+		if !hasPrefix(elemID, prefix, prefixLen) {
+			break
+		}
+
+		end := idx + 1
+		for ; end < n; end++ {
+			if opID, _ = rev(end); opID.ElementID() != elemID {
+				break
+			}
+		}
+
+		cur := idx
+		if end-idx > 1 {
+			base := idx
+			_, _, winner := resolveLineage(end-idx, func(i int) (editID, seed tag.ID) {
+				opID, seed := rev(base + i)
+				return opID.EditID, seed
+			})
+			cur += winner
+		}
+		if !fn(cur) {
+			break
+		}
+		idx = end
+	}
+}
+
+func hasPrefix(elemID, prefix amp.ElementID, prefixLen int) bool {
+	for i := 0; i < prefixLen; i++ {
+		if elemID[i] != prefix[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTx returns an error if the given tx can't be applied in full.
+func checkTx(tx *amp.TxMsg) error {
+	if tx.IsSealed() {
+		return ErrSealedTx
+	}
+	for _, op := range tx.Ops {
+		switch op.OpCode {
+		case amp.TxOpCode_UpsertElement, amp.TxOpCode_DeleteElement:
+		default:
+			return amp.ErrCode_UnsupportedOp.Errorf("unsupported TxOpCode %v", op.OpCode)
+		}
+	}
+	return tx.CheckDataBounds()
+}
@@ -0,0 +1,633 @@
+package store
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+const (
+	// DefaultSegmentSz is the size at which a LogStore starts a new segment file.
+	DefaultSegmentSz = 64 << 20
+
+	logSegmentExt = ".txlog"
+	logTempExt    = ".tmp"
+	logCRCSz      = 4       // each record is trailed by the CRC-32C of the record
+	logCompactSz  = 1 << 20 // DataStore size at which compaction starts a new TxMsg
+)
+
+var (
+	logCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+	// errTornRecord reports a record cut short or left corrupt by an interrupted write.
+	errTornRecord = errors.New("torn record")
+)
+
+// LogOpts configures a LogStore.
+type LogOpts struct {
+	Dir        string // directory holding segment files; created if needed
+	SegmentSz  int64  // a new segment is started once the current one reaches this size; if 0, DefaultSegmentSz is used
+	SyncWrites bool   // if set, each Apply is synced to disk before it returns
+}
+
+// LogStore is a file-backed Store that appends every applied TxMsg to a log of segment files -- concurrency safe.
+//
+// Each record in a segment is an uncompressed TxMsg followed by its CRC-32C.
+// The TxOpIDs of all logged ops are held in a sorted in-memory index, and values are read from the log on demand.
+// As with CellStore, an element's current value is resolved from its revision graph (see RevisionGraph).
+//
+// On open, the log is replayed to rebuild the index; a torn record (short or failing its CRC) at the end of the last segment
+// (e.g. from a crash mid-write) is truncated away, while any other bad record or read error fails the open.  Compact() rewrites the log to hold only current values.
+type LogStore struct {
+	opts  LogOpts
+	mu    sync.RWMutex
+	segs  []*logSegment // ascending segment number; the last segment is appended to
+	index []logRef      // sorted by TxOpID
+	scrap []byte        // holds the record being written or replayed
+}
+
+type logSegment struct {
+	num  uint64
+	file *os.File
+	size int64
+}
+
+// logRef locates one revision of an element in the log.
+type logRef struct {
+	amp.TxOpID
+	seed    tag.ID // GenesisID of the TxMsg that carried this revision
+	seg     *logSegment
+	valOfs  int64 // file offset of the op value
+	valLen  int64
+	deleted bool // set for a delete tombstone
+}
+
+// OpenLogStore opens (or creates) the LogStore in opts.Dir, replaying its log to rebuild the index.
+func OpenLogStore(opts LogOpts) (*LogStore, error) {
+	if opts.SegmentSz <= 0 {
+		opts.SegmentSz = DefaultSegmentSz
+	}
+	if err := os.MkdirAll(opts.Dir, 0o755); err != nil {
+		return nil, amp.ErrCode_StorageFailure.Wrap(err)
+	}
+
+	ls := &LogStore{
+		opts: opts,
+	}
+	err := ls.replay()
+	if err == nil && len(ls.segs) == 0 {
+		_, err = ls.addSegment(1)
+	}
+	if err != nil {
+		ls.closeSegments()
+		return nil, err
+	}
+	return ls, nil
+}
+
+// Close closes all segment files; subsequent calls return ErrStoreClosed.
+func (ls *LogStore) Close() error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.segs == nil {
+		return nil
+	}
+	var err error
+	if ls.opts.SyncWrites {
+		err = ls.activeSeg().file.Sync()
+	}
+	if closeErr := ls.closeSegments(); err == nil {
+		err = closeErr
+	}
+	ls.index = nil
+	return amp.ErrCode_StorageFailure.Wrap(err)
+}
+
+// Len returns the number of elements in this store.
+func (ls *LogStore) Len() int {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	count := 0
+	ls.scan(amp.ElementID{}, 0, func(ref *logRef) bool {
+		count++
+		return true
+	})
+	return count
+}
+
+// Implements Store
+func (ls *LogStore) Apply(tx *amp.TxMsg) error {
+	if err := checkTx(tx); err != nil {
+		return err
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.segs == nil {
+		return ErrStoreClosed
+	}
+	seg, recOfs, err := ls.appendRecord(tx)
+	if err != nil {
+		return err
+	}
+	if ls.opts.SyncWrites {
+		if err = seg.file.Sync(); err != nil {
+			return amp.ErrCode_StorageFailure.Wrap(err)
+		}
+	}
+	ls.indexTx(tx, seg, recOfs+int64(len(ls.scrap)-len(tx.DataStore)))
+	return nil
+}
+
+// Implements Reader
+func (ls *LogStore) Get(elemID amp.ElementID) (Entry, error) {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	if ls.segs == nil {
+		return Entry{}, ErrStoreClosed
+	}
+	var cur *logRef
+	ls.scan(elemID, len(elemID), func(ref *logRef) bool {
+		cur = ref
+		return false
+	})
+	if cur == nil {
+		return Entry{}, ErrElementNotFound
+	}
+	return ls.readEntry(cur)
+}
+
+// Implements Reader
+func (ls *LogStore) Revisions(elemID amp.ElementID) (*RevisionGraph, error) {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	if ls.segs == nil {
+		return nil, ErrStoreClosed
+	}
+	idx, _ := ls.find(&amp.TxOpID{CellID: elemID[0], AttrID: elemID[1], ItemID: elemID[2]})
+	var revs []Revision
+	for ; idx < len(ls.index) && ls.index[idx].ElementID() == elemID; idx++ {
+		ref := &ls.index[idx]
+		entry, err := ls.readEntry(ref)
+		if err != nil {
+			return nil, err
+		}
+		revs = append(revs, Revision{
+			Entry:   entry,
+			Seed:    ref.seed,
+			Deleted: ref.deleted,
+		})
+	}
+	if len(revs) == 0 {
+		return nil, ErrElementNotFound
+	}
+	return Resolve(revs), nil
+}
+
+// Implements Reader
+func (ls *LogStore) ScanCell(cellID tag.ID, fn func(e *Entry) bool) error {
+	return ls.scanEntries(amp.ElementID{cellID}, 1, fn)
+}
+
+// Implements Reader
+func (ls *LogStore) ScanAttr(cellID, attrID tag.ID, fn func(e *Entry) bool) error {
+	return ls.scanEntries(amp.ElementID{cellID, attrID}, 2, fn)
+}
+
+// Implements Store
+func (ls *LogStore) Snapshot() (*amp.TxMsg, error) {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	if ls.segs == nil {
+		return nil, ErrStoreClosed
+	}
+	tx := amp.NewTxMsg(true)
+	tx.Status = amp.OpStatus_Synced
+	var err error
+	ls.scan(amp.ElementID{}, 0, func(ref *logRef) bool {
+		err = ls.appendCurrent(tx, ref)
+		return err == nil
+	})
+	if err != nil {
+		tx.ReleaseRef()
+		return nil, err
+	}
+	tx.OpsSorted = true
+	return tx, nil
+}
+
+// Compact rewrites the log so that it holds only the current value of each element,
+// dropping superseded EditIDs and delete tombstones.
+//
+// Since dropped revisions no longer take part in resolving an element, Compact should only be used once replicas
+// are not expected to send revisions concurrent with those dropped.
+//
+// The compacted log is written to a new segment that is synced and renamed into place before prior segments are removed,
+// so a crash at any point leaves a log that replays to the same state.
+func (ls *LogStore) Compact() error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	if ls.segs == nil {
+		return ErrStoreClosed
+	}
+
+	num := ls.activeSeg().num + 1
+	tmpPath := ls.segmentPath(num) + logTempExt
+	file, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return amp.ErrCode_StorageFailure.Wrap(err)
+	}
+	seg := &logSegment{
+		num:  num,
+		file: file,
+	}
+	index, err := ls.writeCompacted(seg)
+	if err == nil {
+		err = file.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, ls.segmentPath(num))
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(tmpPath)
+		return amp.ErrCode_StorageFailure.Wrap(err)
+	}
+
+	// The rename is only durable once the directory is synced, so prior segments are kept until then.
+	if err = syncDir(ls.opts.Dir); err != nil {
+		file.Close()
+		os.Remove(ls.segmentPath(num))
+		return amp.ErrCode_StorageFailure.Wrap(err)
+	}
+
+	prior := ls.segs
+	ls.segs = []*logSegment{seg}
+	ls.index = index
+	for _, old := range prior {
+		old.file.Close()
+		if rmErr := os.Remove(old.file.Name()); rmErr != nil && err == nil {
+			err = amp.ErrCode_StorageFailure.Wrap(rmErr)
+		}
+	}
+	if syncErr := syncDir(ls.opts.Dir); syncErr != nil && err == nil {
+		err = amp.ErrCode_StorageFailure.Wrap(syncErr)
+	}
+	return err
+}
+
+// writeCompacted writes the current revision of each element to seg and returns the index of the written records.
+//
+// Revisions are grouped into TxMsgs by seed (the GenesisID of the TxMsg that carried them) so that the compacted log replays to the same revision graphs.
+func (ls *LogStore) writeCompacted(seg *logSegment) ([]logRef, error) {
+	var (
+		index []logRef
+		err   error
+	)
+
+	var current []*logRef
+	ls.scan(amp.ElementID{}, 0, func(ref *logRef) bool {
+		current = append(current, ref)
+		return true
+	})
+	sort.Slice(current, func(i, j int) bool {
+		if diff := current[i].seed.CompareTo(current[j].seed); diff != 0 {
+			return diff < 0
+		}
+		return current[i].CompareTo(&current[j].TxOpID) < 0
+	})
+
+	tx := amp.NewTxMsg(false)
+	defer tx.ReleaseRef()
+
+	flush := func() {
+		if len(tx.Ops) == 0 {
+			return
+		}
+		var recOfs int64
+		if recOfs, err = ls.writeRecord(seg, tx); err != nil {
+			return
+		}
+		valBase := recOfs + int64(len(ls.scrap)-len(tx.DataStore))
+		for _, op := range tx.Ops {
+			index = append(index, logRef{
+				TxOpID: op.TxOpID,
+				seed:   tx.GenesisID(),
+				seg:    seg,
+				valOfs: valBase + int64(op.DataOfs),
+				valLen: int64(op.DataLen),
+			})
+		}
+		tx.Ops = tx.Ops[:0]
+		tx.OpCount = 0
+		tx.DataStore = tx.DataStore[:0]
+	}
+
+	for _, ref := range current {
+		if ref.seed != tx.GenesisID() || len(tx.DataStore) >= logCompactSz {
+			if flush(); err != nil {
+				return nil, err
+			}
+			tx.SetGenesisID(ref.seed)
+		}
+		if err = ls.appendCurrent(tx, ref); err != nil {
+			return nil, err
+		}
+	}
+	if flush(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(index, func(i, j int) bool {
+		return index[i].CompareTo(&index[j].TxOpID) < 0
+	})
+	return index, nil
+}
+
+// appendCurrent appends an upsert of the given element revision to tx.
+func (ls *LogStore) appendCurrent(tx *amp.TxMsg, ref *logRef) error {
+	entry, err := ls.readEntry(ref)
+	if err != nil {
+		return err
+	}
+	op := amp.TxOp{
+		TxOpID: entry.TxOpID,
+		OpCode: amp.TxOpCode_UpsertElement,
+	}
+	tx.MarshalOpWithBuf(&op, entry.Value)
+	return nil
+}
+
+// scanEntries calls fn with the current entry of each element whose leading prefixLen IDs match those of prefix.
+func (ls *LogStore) scanEntries(prefix amp.ElementID, prefixLen int, fn func(e *Entry) bool) error {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+
+	if ls.segs == nil {
+		return ErrStoreClosed
+	}
+	var err error
+	ls.scan(prefix, prefixLen, func(ref *logRef) bool {
+		var entry Entry
+		if entry, err = ls.readEntry(ref); err != nil {
+			return false
+		}
+		return fn(&entry)
+	})
+	return err
+}
+
+// scan calls fn with the current revision of each element (skipping deleted elements) whose leading prefixLen IDs match those of prefix.
+func (ls *LogStore) scan(prefix amp.ElementID, prefixLen int, fn func(ref *logRef) bool) {
+	start, _ := ls.find(&amp.TxOpID{CellID: prefix[0], AttrID: prefix[1], ItemID: prefix[2]})
+	scanElements(start, len(ls.index), prefix, prefixLen,
+		func(i int) (*amp.TxOpID, tag.ID) {
+			return &ls.index[i].TxOpID, ls.index[i].seed
+		},
+		func(cur int) bool {
+			ref := &ls.index[cur]
+			return ref.deleted || fn(ref)
+		})
+}
+
+// find returns the index of the first ref >= opID and whether it is an exact match.
+func (ls *LogStore) find(opID *amp.TxOpID) (int, bool) {
+	return sort.Find(len(ls.index), func(i int) int {
+		return opID.CompareTo(&ls.index[i].TxOpID)
+	})
+}
+
+func (ls *LogStore) readEntry(ref *logRef) (Entry, error) {
+	entry := Entry{
+		TxOpID: ref.TxOpID,
+		Value:  make([]byte, ref.valLen),
+	}
+	if _, err := ref.seg.file.ReadAt(entry.Value, ref.valOfs); err != nil {
+		return Entry{}, amp.ErrCode_StorageFailure.Wrap(err)
+	}
+	return entry, nil
+}
+
+// indexTx adds each op of the given tx to the index, where valBase is the file offset of the tx DataStore.
+func (ls *LogStore) indexTx(tx *amp.TxMsg, seg *logSegment, valBase int64) {
+	seed := tx.GenesisID()
+	for _, op := range tx.Ops {
+		ref := logRef{
+			TxOpID:  op.TxOpID,
+			seed:    seed,
+			seg:     seg,
+			valOfs:  valBase + int64(op.DataOfs),
+			valLen:  int64(op.DataLen),
+			deleted: op.OpCode == amp.TxOpCode_DeleteElement,
+		}
+		idx, found := ls.find(&ref.TxOpID)
+		if found {
+			ls.index[idx] = ref
+		} else {
+			ls.index = append(ls.index, logRef{})
+			copy(ls.index[idx+1:], ls.index[idx:])
+			ls.index[idx] = ref
+		}
+	}
+}
+
+// appendRecord writes the given tx to the active segment, starting a new segment if it is full.
+func (ls *LogStore) appendRecord(tx *amp.TxMsg) (*logSegment, int64, error) {
+	seg := ls.activeSeg()
+	if seg.size > 0 && seg.size >= ls.opts.SegmentSz {
+		var err error
+		if seg, err = ls.addSegment(seg.num + 1); err != nil {
+			return nil, 0, err
+		}
+	}
+	recOfs, err := ls.writeRecord(seg, tx)
+	if err != nil {
+		return nil, 0, amp.ErrCode_StorageFailure.Wrap(err)
+	}
+	return seg, recOfs, nil
+}
+
+// writeRecord appends tx and its CRC to the given segment, leaving the record (less its CRC) in ls.scrap.
+// If the write fails, the segment is truncated back to its prior size.
+func (ls *LogStore) writeRecord(seg *logSegment, tx *amp.TxMsg) (int64, error) {
+	tx.MarshalToBuffer(&ls.scrap)
+	rec := binary.LittleEndian.AppendUint32(ls.scrap, crc32.Checksum(ls.scrap, logCRCTable))
+
+	recOfs := seg.size
+	if _, err := seg.file.WriteAt(rec, recOfs); err != nil {
+		seg.file.Truncate(recOfs)
+		return 0, err
+	}
+	seg.size += int64(len(rec))
+	return recOfs, nil
+}
+
+func (ls *LogStore) activeSeg() *logSegment {
+	return ls.segs[len(ls.segs)-1]
+}
+
+func (ls *LogStore) addSegment(num uint64) (*logSegment, error) {
+	file, err := os.OpenFile(ls.segmentPath(num), os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, amp.ErrCode_StorageFailure.Wrap(err)
+	}
+	seg := &logSegment{
+		num:  num,
+		file: file,
+	}
+	ls.segs = append(ls.segs, seg)
+	return seg, nil
+}
+
+// syncDir flushes the given directory's entries (e.g. a file just created, renamed, or removed) to stable storage.
+// Windows offers no way to sync a directory, where a rename is instead made durable by the file system itself.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (ls *LogStore) segmentPath(num uint64) string {
+	return filepath.Join(ls.opts.Dir, fmt.Sprintf("%010d%s", num, logSegmentExt))
+}
+
+func (ls *LogStore) closeSegments() error {
+	var err error
+	for _, seg := range ls.segs {
+		if closeErr := seg.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	ls.segs = nil
+	return err
+}
+
+// replay opens each segment in ascending order and indexes its records.
+func (ls *LogStore) replay() error {
+	dirEntries, err := os.ReadDir(ls.opts.Dir)
+	if err != nil {
+		return amp.ErrCode_StorageFailure.Wrap(err)
+	}
+
+	var nums []uint64
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if strings.HasSuffix(name, logTempExt) {
+			os.Remove(filepath.Join(ls.opts.Dir, name)) // incomplete compaction
+			continue
+		}
+		numStr, isSeg := strings.CutSuffix(name, logSegmentExt)
+		if !isSeg {
+			continue
+		}
+		if num, err := strconv.ParseUint(numStr, 10, 64); err == nil {
+			nums = append(nums, num)
+		}
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+
+	for i, num := range nums {
+		seg, err := ls.addSegment(num)
+		if err != nil {
+			return err
+		}
+		if err = ls.replaySegment(seg, i == len(nums)-1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// replaySegment indexes each record of the given segment.
+// If isLast is set, a torn record is assumed to be from an interrupted write and the segment is truncated to the last good record.
+//
+// Apply accepts a TxMsg of any size, so a record is bounded only by the size of its segment.
+func (ls *LogStore) replaySegment(seg *logSegment, isLast bool) error {
+	info, err := seg.file.Stat()
+	if err != nil {
+		return amp.ErrCode_StorageFailure.Wrap(err)
+	}
+	fileSz := info.Size()
+
+	reader := amp.TxReader{
+		MaxTxSz: int(min(fileSz, math.MaxInt)),
+		Codecs:  1 << amp.TxCodec_None,
+	}
+	for seg.size < fileSz {
+		tx, recLen, err := ls.readRecord(&reader, seg.file, seg.size, fileSz-seg.size)
+		if err != nil {
+			if err != errTornRecord || !isLast {
+				return amp.ErrCode_StorageFailure.Errorf("%s: bad record at offset %d: %v", seg.file.Name(), seg.size, err)
+			}
+			if err = seg.file.Truncate(seg.size); err != nil {
+				return amp.ErrCode_StorageFailure.Wrap(err)
+			}
+			break
+		}
+		ls.indexTx(tx, seg, seg.size+recLen-logCRCSz-int64(len(tx.DataStore)))
+		tx.ReleaseRef()
+		seg.size += recLen
+	}
+	return nil
+}
+
+// readRecord reads and checks the record at the given offset, returning its TxMsg and the record length (including its CRC).
+//
+// errTornRecord is returned if the record is cut short, its header is garbled, or its CRC does not match.
+func (ls *LogStore) readRecord(reader *amp.TxReader, file *os.File, ofs, remain int64) (*amp.TxMsg, int64, error) {
+	var header amp.TxHeader
+	if remain < int64(len(header))+logCRCSz {
+		return nil, 0, errTornRecord
+	}
+	if _, err := file.ReadAt(header[:], ofs); err != nil {
+		return nil, 0, err
+	}
+	bodyLen, dataLen := int64(header.TxBodyLen()), int64(header.TxDataLen())
+	if bodyLen < int64(len(header)) || dataLen < 0 || bodyLen+dataLen+logCRCSz > remain {
+		return nil, 0, errTornRecord
+	}
+	txLen := bodyLen + dataLen
+
+	ls.scrap = append(ls.scrap[:0], make([]byte, txLen+logCRCSz)...)
+	if _, err := file.ReadAt(ls.scrap, ofs); err != nil {
+		return nil, 0, err
+	}
+	rec := ls.scrap[:txLen]
+	if crc32.Checksum(rec, logCRCTable) != binary.LittleEndian.Uint32(ls.scrap[txLen:]) {
+		return nil, 0, errTornRecord
+	}
+	tx, err := reader.ReadTxMsg(bytes.NewReader(rec))
+	if err != nil {
+		return nil, 0, err
+	}
+	return tx, txLen + logCRCSz, nil
+}
@@ -0,0 +1,177 @@
+package store
+
+import (
+	"sort"
+
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// Revision is a single edit of an element, as carried by a TxOp.
+type Revision struct {
+	Entry          // element ID, EditID, and value (nil if Deleted)
+	Seed    tag.ID // seed the EditID was formed from -- the GenesisID of the TxMsg that carried the edit
+	Deleted bool   // set if this edit deletes the element
+}
+
+// RevisionGraph is the edit lineage of a single element, rebuilt from the EditIDs and seeds of its revisions.
+//
+// Since an EditID is formed from its predecessor's EditID and a seed (see tag.ID.FormEditID), each revision's
+// predecessor is found by inverting FormEditID.  A revision whose predecessor is a genesis edit or is not
+// present is a root, so the graph is a forest whose leaves ("heads") are the tips of concurrent branches.
+//
+// Of the heads, the one with the greatest EditID is the current revision (Winner).  This depends only on the set
+// of revisions present, so every replica holding the same revisions resolves the same winner regardless of the
+// order they were applied.
+type RevisionGraph struct {
+	Revs   []Revision // sorted by EditID
+	Parent []int      // Parent[i] is the index of the predecessor of Revs[i], or -1 if Revs[i] is a root
+	Depth  []int      // Depth[i] is the number of predecessors of Revs[i] in this graph
+	Winner int        // index of the current revision
+}
+
+// Resolve rebuilds the revision graph of the given revisions, which must all be of the same element.
+// If two revisions have the same EditID, the latter is used.
+func Resolve(revs []Revision) *RevisionGraph {
+	sorted := make([]Revision, 0, len(revs))
+	for _, rev := range revs {
+		idx := sort.Search(len(sorted), func(i int) bool {
+			return sorted[i].EditID.CompareTo(rev.EditID) >= 0
+		})
+		if idx < len(sorted) && sorted[idx].EditID == rev.EditID {
+			sorted[idx] = rev
+			continue
+		}
+		sorted = append(sorted, Revision{})
+		copy(sorted[idx+1:], sorted[idx:])
+		sorted[idx] = rev
+	}
+
+	g := &RevisionGraph{
+		Revs: sorted,
+	}
+	g.Parent, g.Depth, g.Winner = resolveLineage(len(sorted), func(i int) (editID, seed tag.ID) {
+		return sorted[i].EditID, sorted[i].Seed
+	})
+	return g
+}
+
+// Current returns the current revision of this element.
+func (g *RevisionGraph) Current() *Revision {
+	return &g.Revs[g.Winner]
+}
+
+// Children returns the indexes of the revisions whose predecessor is Revs[idx].
+func (g *RevisionGraph) Children(idx int) []int {
+	var children []int
+	for i, parent := range g.Parent {
+		if parent == idx {
+			children = append(children, i)
+		}
+	}
+	return children
+}
+
+// Heads returns the indexes of the revisions that have no successor -- more than one means there are concurrent branches.
+func (g *RevisionGraph) Heads() []int {
+	hasChild := make([]bool, len(g.Revs))
+	for _, parent := range g.Parent {
+		if parent >= 0 {
+			hasChild[parent] = true
+		}
+	}
+	var heads []int
+	for i := range g.Revs {
+		if !hasChild[i] {
+			heads = append(heads, i)
+		}
+	}
+	return heads
+}
+
+// Lineage returns the index of Revs[idx] followed by the indexes of each of its predecessors, ending with its root.
+func (g *RevisionGraph) Lineage(idx int) []int {
+	lineage := make([]int, 0, g.Depth[idx]+1)
+	for ; idx >= 0; idx = g.Parent[idx] {
+		lineage = append(lineage, idx)
+	}
+	return lineage
+}
+
+// resolveLineage links each of n revisions (sorted by EditID) to its predecessor and returns the index of the winning head.
+func resolveLineage(n int, rev func(i int) (editID, seed tag.ID)) (parent, depth []int, winner int) {
+	parent = make([]int, n)
+	depth = make([]int, n)
+
+	find := func(editID tag.ID) int {
+		idx := sort.Search(n, func(i int) bool {
+			id, _ := rev(i)
+			return id.CompareTo(editID) >= 0
+		})
+		if idx < n {
+			if id, _ := rev(idx); id == editID {
+				return idx
+			}
+		}
+		return -1
+	}
+
+	// Invert FormEditID: the predecessor's [1] and [2] are recovered exactly and its [0] is one of two values.
+	for i := 0; i < n; i++ {
+		parent[i] = -1
+		editID, seed := rev(i)
+		if editID == tag.Genesis(seed) {
+			continue
+		}
+		pred := tag.ID{2*editID[0] - seed[0], editID[1] ^ seed[1], editID[2] ^ seed[2]}
+		for range 2 {
+			if j := find(pred); j >= 0 && j != i && pred.FormEditID(seed) == editID {
+				parent[i] = j
+				break
+			}
+			pred[0]++
+		}
+	}
+
+	// Assign depths, cutting any cycle (only possible from forged EditIDs) where it is first found.
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int8, n)
+	var path []int
+	for i := 0; i < n; i++ {
+		path = path[:0]
+		j := i
+		for ; j >= 0 && state[j] == unvisited; j = parent[j] {
+			state[j] = visiting
+			path = append(path, j)
+		}
+		if j >= 0 && state[j] == visiting {
+			parent[path[len(path)-1]] = -1
+		}
+		for k := len(path) - 1; k >= 0; k-- {
+			p := path[k]
+			if parent[p] >= 0 {
+				depth[p] = depth[parent[p]] + 1
+			}
+			state[p] = visited
+		}
+	}
+
+	// The winner is the head with the greatest EditID.
+	hasChild := make([]bool, n)
+	for _, p := range parent {
+		if p >= 0 {
+			hasChild[p] = true
+		}
+	}
+	winner = -1
+	for i := n - 1; i >= 0; i-- {
+		if !hasChild[i] {
+			winner = i
+			break
+		}
+	}
+	return parent, depth, winner
+}
@@ -0,0 +1,402 @@
+package store_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/store"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+func TestCellStore(t *testing.T) {
+	testStore(t, store.NewCellStore(), store.NewCellStore())
+}
+
+// testStore checks the given empty stores, where replica is used to check Snapshot().
+func testStore(t *testing.T, cs, replica store.Store) {
+
+	cellA := tag.ID{0, 0, 100}
+	cellB := tag.ID{0, 0, 200}
+	attrX := tag.ID{0, 1, 0}
+	attrY := tag.ID{0, 2, 0}
+
+	tx := amp.NewTxMsg(true)
+	for i := uint64(0); i < 5; i++ {
+		upsert(t, tx, cellA, attrY, tag.ID{0, 0, 5 - i}, "y")
+		upsert(t, tx, cellA, attrX, tag.ID{0, 0, 5 - i}, "x")
+		upsert(t, tx, cellB, attrX, tag.ID{0, 0, i}, "b")
+	}
+	if err := cs.Apply(tx); err != nil {
+		t.Fatal(err)
+	}
+	if n := countEntries(t, cs); n != 15 {
+		t.Fatalf("expected 15 entries, got %d", n)
+	}
+
+	// revisions replace what they edit; deletes remove
+	firstEditID := tag.Genesis(tx.GenesisID())
+	tx = amp.NewTxMsg(true)
+	if err := tx.Revise(cellA, attrX, tag.ID{0, 0, 1}, firstEditID, &amp.Tag{Text: "x-edited"}); err != nil {
+		t.Fatal(err)
+	}
+	del := amp.TxOp{
+		OpCode: amp.TxOpCode_DeleteElement,
+		TxOpID: amp.TxOpID{CellID: cellA, AttrID: attrY, ItemID: tag.ID{0, 0, 3}, EditID: firstEditID.FormEditID(tx.GenesisID())},
+	}
+	tx.MarshalOpWithBuf(&del, nil)
+	if err := cs.Apply(tx); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := cs.Get(amp.ElementID{cellA, attrX, tag.ID{0, 0, 1}})
+	if err != nil || loadText(t, &entry) != "x-edited" {
+		t.Fatalf("Get: expected edited value, got %v", err)
+	}
+	if _, err = cs.Get(amp.ElementID{cellA, attrY, tag.ID{0, 0, 3}}); err != store.ErrElementNotFound {
+		t.Fatalf("Get: expected deleted element to be absent, got %v", err)
+	}
+
+	var scanned []amp.ElementID
+	err = cs.ScanCell(cellA, func(e *store.Entry) bool {
+		scanned = append(scanned, e.ElementID())
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scanned) != 9 {
+		t.Fatalf("ScanCell: expected 9 entries, got %d", len(scanned))
+	}
+	for i := 1; i < len(scanned); i++ {
+		if scanned[i-1].CompareTo(scanned[i]) >= 0 {
+			t.Fatalf("ScanCell: entries out of order")
+		}
+	}
+	if scanned[0][1] != attrX || scanned[len(scanned)-1][1] != attrY {
+		t.Fatalf("ScanCell: expected AttrID order")
+	}
+
+	count := 0
+	err = cs.ScanAttr(cellB, attrX, func(e *store.Entry) bool {
+		if e.CellID != cellB || e.AttrID != attrX || loadText(t, e) != "b" {
+			t.Fatalf("ScanAttr: unexpected entry %v", e.ElementID())
+		}
+		count++
+		return count < 3
+	})
+	if err != nil || count != 3 {
+		t.Fatalf("ScanAttr: expected scan to stop after 3 entries, got %d", count)
+	}
+
+	// a tx with an unsupported op is rejected in full
+	tx = amp.NewTxMsg(true)
+	upsert(t, tx, cellB, attrY, tag.ID{0, 0, 1}, "never")
+	bad := amp.TxOp{
+		OpCode: amp.TxOpCode_Nil,
+		TxOpID: amp.TxOpID{CellID: cellB},
+	}
+	tx.MarshalOpWithBuf(&bad, nil)
+	if err = cs.Apply(tx); err == nil {
+		t.Fatalf("expected Apply to reject unsupported op")
+	}
+	if _, err = cs.Get(amp.ElementID{cellB, attrY, tag.ID{0, 0, 1}}); err != store.ErrElementNotFound {
+		t.Fatalf("rejected tx was partially applied")
+	}
+
+	// a sealed tx must be opened first
+	key, err := amp.GenerateSymmetricKey(amp.CryptoKit_AES_GCM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx = amp.NewTxMsg(true)
+	upsert(t, tx, cellB, attrY, tag.ID{0, 0, 1}, "sealed")
+	if err = tx.Seal(key, false); err != nil {
+		t.Fatal(err)
+	}
+	if err = cs.Apply(tx); err != store.ErrSealedTx {
+		t.Fatalf("expected ErrSealedTx, got %v", err)
+	}
+
+	// a snapshot reproduces the same state
+	snap, err := cs.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.ReleaseRef()
+	if err = replica.Apply(snap); err != nil {
+		t.Fatal(err)
+	}
+	checkSameState(t, cs, replica, cellA, cellB)
+}
+
+// checkSameState fails if the given stores differ in the given cells.
+func checkSameState(t *testing.T, st, replica store.Store, cellIDs ...tag.ID) {
+	t.Helper()
+	if n, n2 := countEntries(t, st), countEntries(t, replica); n != n2 {
+		t.Fatalf("expected %d entries, got %d", n, n2)
+	}
+	for _, cellID := range cellIDs {
+		st.ScanCell(cellID, func(e *store.Entry) bool {
+			e2, err := replica.Get(e.ElementID())
+			if err != nil || e2.TxOpID != e.TxOpID || string(e2.Value) != string(e.Value) {
+				t.Fatalf("mismatch at %v: %v", e.ElementID(), err)
+			}
+			return true
+		})
+	}
+}
+
+func countEntries(t *testing.T, st store.Store) int {
+	t.Helper()
+	snap, err := st.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.ReleaseRef()
+	return len(snap.Ops)
+}
+
+func upsert(t *testing.T, tx *amp.TxMsg, cellID, attrID, itemID tag.ID, text string) {
+	t.Helper()
+	if err := tx.Upsert(cellID, attrID, itemID, &amp.Tag{Text: text}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func loadText(t *testing.T, e *store.Entry) string {
+	t.Helper()
+	var val amp.Tag
+	if err := e.Load(&val); err != nil {
+		t.Fatal(err)
+	}
+	return val.Text
+}
+
+func TestLogStore(t *testing.T) {
+	dir := t.TempDir()
+	opts := store.LogOpts{
+		Dir:       dir,
+		SegmentSz: 256, // forces several segments
+	}
+	ls := openLogStore(t, opts)
+	replica := openLogStore(t, store.LogOpts{Dir: t.TempDir()})
+	testStore(t, ls, replica)
+	replica.Close()
+
+	cellA := tag.ID{0, 0, 100}
+	cellB := tag.ID{0, 0, 200}
+	segs, _ := filepath.Glob(filepath.Join(dir, "*.txlog"))
+	if len(segs) < 2 {
+		t.Fatalf("expected multiple segments, got %d", len(segs))
+	}
+
+	// reopening replays the log to the same state
+	ref := store.NewCellStore()
+	snap, err := ls.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref.Apply(snap)
+	snap.ReleaseRef()
+	ls.Close()
+	if _, err = ls.Get(amp.ElementID{cellA}); err != store.ErrStoreClosed {
+		t.Fatalf("expected ErrStoreClosed, got %v", err)
+	}
+	ls = openLogStore(t, opts)
+	checkSameState(t, ref, ls, cellA, cellB)
+
+	// a torn write at the end of the log is truncated away on open
+	tx := amp.NewTxMsg(true)
+	upsert(t, tx, cellB, cellB, cellB, "torn")
+	if err = ls.Apply(tx); err != nil {
+		t.Fatal(err)
+	}
+	ls.Close()
+	segs, _ = filepath.Glob(filepath.Join(dir, "*.txlog"))
+	lastSeg := segs[len(segs)-1]
+	info, err := os.Stat(lastSeg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Truncate(lastSeg, info.Size()-3); err != nil {
+		t.Fatal(err)
+	}
+	ls = openLogStore(t, opts)
+	if _, err = ls.Get(amp.ElementID{cellB, cellB, cellB}); err != store.ErrElementNotFound {
+		t.Fatalf("expected torn upsert to be dropped, got %v", err)
+	}
+	checkSameState(t, ref, ls, cellA, cellB)
+
+	// compaction drops superseded edits and tombstones but preserves state
+	if err = ls.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	segs, _ = filepath.Glob(filepath.Join(dir, "*.txlog"))
+	if len(segs) != 1 {
+		t.Fatalf("expected 1 segment after Compact, got %d", len(segs))
+	}
+	checkSameState(t, ref, ls, cellA, cellB)
+
+	tx = amp.NewTxMsg(true)
+	upsert(t, tx, cellB, cellB, cellB, "after compaction")
+	if err = ls.Apply(tx); err != nil {
+		t.Fatal(err)
+	}
+	if err = ref.Apply(tx); err != nil {
+		t.Fatal(err)
+	}
+	ls.Close()
+	ls = openLogStore(t, opts)
+	defer ls.Close()
+	checkSameState(t, ref, ls, cellA, cellB)
+}
+
+func TestLogStoreLargeRecord(t *testing.T) {
+	if testing.Short() {
+		t.Skip("writes a record larger than amp.DefaultMaxTxSz")
+	}
+	opts := store.LogOpts{Dir: t.TempDir()}
+	ls := openLogStore(t, opts)
+
+	// a record over amp.DefaultMaxTxSz, followed by another record, both survive a reopen
+	cellID := tag.ID{0, 0, 300}
+	tx := amp.NewTxMsg(true)
+	op := amp.TxOp{
+		OpCode: amp.TxOpCode_UpsertElement,
+		TxOpID: amp.TxOpID{CellID: cellID, AttrID: cellID, ItemID: cellID, EditID: tx.GenesisID()},
+	}
+	tx.MarshalOpWithBuf(&op, make([]byte, amp.DefaultMaxTxSz+1))
+	if err := ls.Apply(tx); err != nil {
+		t.Fatal(err)
+	}
+	tx.ReleaseRef()
+	tx = amp.NewTxMsg(true)
+	upsert(t, tx, cellID, cellID, tag.ID{0, 0, 1}, "after")
+	if err := ls.Apply(tx); err != nil {
+		t.Fatal(err)
+	}
+	tx.ReleaseRef()
+	ls.Close()
+
+	ls = openLogStore(t, opts)
+	defer ls.Close()
+	if n := ls.Len(); n != 2 {
+		t.Fatalf("expected 2 elements after reopen, got %d", n)
+	}
+	entry, err := ls.Get(amp.ElementID{cellID, cellID, cellID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entry.Value) != amp.DefaultMaxTxSz+1 {
+		t.Fatalf("expected large value to replay, got %d bytes", len(entry.Value))
+	}
+}
+
+func openLogStore(t *testing.T, opts store.LogOpts) *store.LogStore {
+	t.Helper()
+	ls, err := store.OpenLogStore(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ls
+}
+
+func TestRevisions(t *testing.T) {
+	elemID := amp.ElementID{{0, 0, 7}, {0, 1, 0}, {0, 0, 1}}
+
+	// root -> a -> a2 and, concurrently, root -> b -> (deleted)
+	seeds := []tag.ID{{100 << 16, 11, 12}, {200 << 16, 21, 22}, {201 << 16, 31, 32}, {300 << 16, 41, 42}, {301 << 16, 51, 52}}
+	rootID := tag.Genesis(seeds[0])
+	aID := rootID.FormEditID(seeds[1])
+	bID := rootID.FormEditID(seeds[2])
+	a2ID := aID.FormEditID(seeds[3])
+	delID := bID.FormEditID(seeds[4])
+
+	var txs []*amp.TxMsg
+	for i, edit := range []struct {
+		editID tag.ID
+		text   string
+	}{
+		{rootID, "root"}, {aID, "a"}, {bID, "b"}, {a2ID, "a2"}, {delID, ""},
+	} {
+		tx := amp.NewTxMsg(false)
+		tx.SetGenesisID(seeds[i])
+		op := amp.TxOp{
+			OpCode: amp.TxOpCode_UpsertElement,
+			TxOpID: amp.TxOpID{CellID: elemID[0], AttrID: elemID[1], ItemID: elemID[2], EditID: edit.editID},
+		}
+		if edit.text == "" {
+			op.OpCode = amp.TxOpCode_DeleteElement
+			tx.MarshalOpWithBuf(&op, nil)
+		} else if err := tx.MarshalOp(&op, &amp.Tag{Text: edit.text}); err != nil {
+			t.Fatal(err)
+		}
+		txs = append(txs, tx)
+	}
+
+	ls := openLogStore(t, store.LogOpts{Dir: t.TempDir()})
+	defer ls.Close()
+	stores := []store.Store{store.NewCellStore(), store.NewCellStore(), ls}
+	orders := [][]int{{0, 1, 2, 3, 4}, {4, 3, 2, 1, 0}, {2, 4, 0, 3, 1}}
+	for i, st := range stores {
+		for _, j := range orders[i] {
+			if err := st.Apply(txs[j]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// the winner is the head with the greatest EditID
+	winnerID, winnerText := a2ID, "a2"
+	if delID.CompareTo(a2ID) > 0 {
+		winnerID, winnerText = delID, ""
+	}
+
+	for i, st := range stores {
+		g, err := st.Revisions(elemID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(g.Revs) != 5 {
+			t.Fatalf("store %d: expected 5 revisions, got %d", i, len(g.Revs))
+		}
+		idx := map[tag.ID]int{}
+		for j, rev := range g.Revs {
+			idx[rev.EditID] = j
+		}
+		for child, parent := range map[tag.ID]tag.ID{aID: rootID, bID: rootID, a2ID: aID, delID: bID} {
+			if g.Parent[idx[child]] != idx[parent] {
+				t.Fatalf("store %d: lineage not rebuilt", i)
+			}
+		}
+		if g.Parent[idx[rootID]] != -1 || g.Depth[idx[a2ID]] != 2 || len(g.Lineage(idx[a2ID])) != 3 {
+			t.Fatalf("store %d: bad depth or lineage", i)
+		}
+		if heads := g.Heads(); len(heads) != 2 || len(g.Children(idx[rootID])) != 2 {
+			t.Fatalf("store %d: expected 2 heads and 2 branches, got %v", i, heads)
+		}
+		if g.Current().EditID != winnerID {
+			t.Fatalf("store %d: unexpected winner", i)
+		}
+
+		entry, err := st.Get(elemID)
+		if winnerText == "" {
+			if err != store.ErrElementNotFound {
+				t.Fatalf("store %d: expected deleted element, got %v", i, err)
+			}
+		} else if err != nil || loadText(t, &entry) != winnerText {
+			t.Fatalf("store %d: expected %q, got %v", i, winnerText, err)
+		}
+	}
+
+	// forged EditIDs that form a cycle still resolve
+	cycle := []store.Revision{
+		{Entry: store.Entry{TxOpID: amp.TxOpID{EditID: tag.ID{4, 1, 1}}}, Seed: tag.ID{4, 0, 0}},
+	}
+	g := store.Resolve(cycle)
+	if g.Parent[0] != -1 || g.Winner != 0 {
+		t.Fatalf("expected self-referencing revision to be a root")
+	}
+}
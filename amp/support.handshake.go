@@ -0,0 +1,144 @@
+package amp
+
+const (
+	// MinTxHeaderVersion is the lowest TxHeader version this package reads.
+	MinTxHeaderVersion = byte(Const_TxHeader_Version)
+
+	// MaxTxHeaderVersion is the highest TxHeader version this package reads and writes.
+	MaxTxHeaderVersion = byte(Const_TxHeader_Version)
+)
+
+var (
+	// HandshakeAttr is the AttrID of the MetaNodeID attr that carries a peer's Handshake.
+	HandshakeAttr = AttrSpec.With("Handshake").ID
+)
+
+// Protocol is the set of wire features two peers have agreed to use, as negotiated from their Handshakes.
+type Protocol struct {
+	HeaderVersion byte          // TxHeader version written to the peer
+	Codecs        TxCodecs      // TxCodecs both peers decode
+	SigningKits   []CryptoKitID // signing kits both peers verify, in order of local preference
+}
+
+// NewHandshake returns a Handshake advertising the wire features this package supports.
+func NewHandshake() *Handshake {
+	hs := &Handshake{
+		MinHeaderVersion: uint32(MinTxHeaderVersion),
+		MaxHeaderVersion: uint32(MaxTxHeaderVersion),
+		SigningKits:      []CryptoKitID{CryptoKit_Signing_ED25519, CryptoKit_Signing_NaCl},
+	}
+	for codec := TxCodec(0); codec < 32; codec++ {
+		if SupportedTxCodecs.Has(codec) {
+			hs.Codecs = append(hs.Codecs, codec)
+		}
+	}
+	return hs
+}
+
+// TxCodecs returns the set of codecs this Handshake advertises.  TxCodec_None is always included.
+func (v *Handshake) TxCodecs() TxCodecs {
+	codecs := TxCodecs(1 << TxCodec_None)
+	for _, codec := range v.Codecs {
+		if codec >= 0 && codec < 32 {
+			codecs |= 1 << codec
+		}
+	}
+	return codecs
+}
+
+// Negotiate returns the Protocol to use with a peer that sent the given Handshake, where v is the local Handshake.
+// If the peers have no TxHeader version in common, an ErrCode_UnsupportedOp error is returned.
+func (v *Handshake) Negotiate(peer *Handshake) (Protocol, error) {
+	lo := max(v.MinHeaderVersion, peer.MinHeaderVersion)
+	hi := min(v.MaxHeaderVersion, peer.MaxHeaderVersion)
+	if lo > hi || hi > 0xFF {
+		return Protocol{}, ErrCode_UnsupportedOp.Errorf("no common TxHeader version: local [%#x, %#x], peer [%#x, %#x]",
+			v.MinHeaderVersion, v.MaxHeaderVersion, peer.MinHeaderVersion, peer.MaxHeaderVersion)
+	}
+
+	proto := Protocol{
+		HeaderVersion: byte(hi),
+		Codecs:        v.TxCodecs() & peer.TxCodecs(),
+	}
+	for _, kit := range v.SigningKits {
+		for _, peerKit := range peer.SigningKits {
+			if kit == peerKit {
+				proto.SigningKits = append(proto.SigningKits, kit)
+				break
+			}
+		}
+	}
+	return proto, nil
+}
+
+// TxEncoding returns the given encoding adjusted to this Protocol.
+func (proto *Protocol) TxEncoding(enc TxEncoding) TxEncoding {
+	enc = enc.Negotiate(proto.Codecs)
+	enc.HeaderVersion = proto.HeaderVersion
+	return enc
+}
+
+// CheckSigned returns an ErrCode_UnsupportedOp error if the given tx is signed (see TxMsg.Sign) with a signing kit the peers did not agree on.
+func (proto *Protocol) CheckSigned(tx *TxMsg) error {
+	if _, kit := tx.signatureTags(); kit != CryptoKit_Nil && !proto.HasSigningKit(kit) {
+		return ErrCode_UnsupportedOp.Errorf("tx signature kit %v was not negotiated", kit)
+	}
+	return nil
+}
+
+// HasSigningKit returns true if both peers support the given signing kit.
+func (proto *Protocol) HasSigningKit(kit CryptoKitID) bool {
+	for _, k := range proto.SigningKits {
+		if k == kit {
+			return true
+		}
+	}
+	return false
+}
+
+// ExchangeHandshake sends the local Handshake over the given transport as a MetaNodeID attr and then receives the peer's,
+// which is expected to be the first TxMsg received.  Both peers call this at session start, before any other TxMsg is sent.
+// The transport's encoding is expected to still write MinTxHeaderVersion (see TxEncoding.HeaderVersion) so that the peer can read the Handshake.
+//
+// If the transport implements TxEncoder, its encoding is set to NegotiatedTxEncoding adjusted to the negotiated Protocol.
+func ExchangeHandshake(via Transport, local *Handshake) (Protocol, error) {
+	tx, err := MarshalAttr(MetaNodeID, HandshakeAttr, local)
+	if err != nil {
+		return Protocol{}, err
+	}
+	if err = via.SendTx(tx); err != nil {
+		return Protocol{}, err
+	}
+
+	tx, err = via.RecvTx()
+	if err != nil {
+		return Protocol{}, err
+	}
+	defer tx.ReleaseRef()
+
+	peer, err := ReadHandshake(tx)
+	if err != nil {
+		return Protocol{}, err
+	}
+	proto, err := local.Negotiate(peer)
+	if err != nil {
+		return Protocol{}, err
+	}
+	if encoder, ok := via.(TxEncoder); ok {
+		encoder.SetTxEncoding(proto.TxEncoding(NegotiatedTxEncoding))
+	}
+	return proto, nil
+}
+
+// ReadHandshake returns the Handshake carried by the given TxMsg, or an ErrCode_BadRequest error if it does not carry one.
+func ReadHandshake(tx *TxMsg) (*Handshake, error) {
+	ops := tx.AttrItems(MetaNodeID, HandshakeAttr)
+	if !ops.Next() {
+		return nil, ErrCode_BadRequest.Error("expected Handshake")
+	}
+	hs := &Handshake{}
+	if err := ops.Load(hs); err != nil {
+		return nil, err
+	}
+	return hs, nil
+}
@@ -0,0 +1,93 @@
+package amp
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+)
+
+// LoginChallengeSz is the byte size of the random LoginChallenge.Hash issued by NewLoginChallenge.
+const LoginChallengeSz = 32
+
+// loginSigningContext prefixes each signed challenge so that a device key's login signature can't be mistaken for any other signature.
+const loginSigningContext = "amp.login.challenge/ed25519:"
+
+var (
+	// LoginAttr is the AttrID of the MetaNodeID attr that carries a client's Login and, once verified, the host's reply.
+	LoginAttr = AttrSpec.With("Login").ID
+
+	// LoginChallengeAttr is the AttrID of the MetaNodeID attr that carries a host's LoginChallenge.
+	LoginChallengeAttr = AttrSpec.With("LoginChallenge").ID
+
+	// LoginResponseAttr is the AttrID of the MetaNodeID attr that carries a client's LoginResponse.
+	LoginResponseAttr = AttrSpec.With("LoginResponse").ID
+
+	// SessionResumeAttr is the AttrID of the MetaNodeID attr that carries a client's SessionResume.
+	SessionResumeAttr = AttrSpec.With("SessionResume").ID
+)
+
+// DeviceKeyLookup returns the public signing key registered for the device named by the given Login.
+type DeviceKeyLookup func(login *Login) (*CryptoKey, error)
+
+// NewLoginChallenge returns a LoginChallenge holding a new random Hash.
+func NewLoginChallenge() (*LoginChallenge, error) {
+	challenge := &LoginChallenge{
+		Hash: make([]byte, LoginChallengeSz),
+	}
+	if _, err := rand.Read(challenge.Hash); err != nil {
+		return nil, ErrCode_InternalErr.Wrap(err)
+	}
+	return challenge, nil
+}
+
+// Sign returns the LoginResponse that answers this challenge using the given private device key.
+func (v *LoginChallenge) Sign(deviceKey *CryptoKey) (*LoginResponse, error) {
+	priv, err := deviceKey.signingPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResponse{
+		HashResponse: ed25519.Sign(priv, loginSigningMessage(v.Hash)),
+	}, nil
+}
+
+// NewEd25519Authenticator returns an Authenticator that challenges each client to sign a random Hash with its ed25519 device key,
+// verifying the signature against the public key returned by the given lookup.
+func NewEd25519Authenticator(lookup DeviceKeyLookup) Authenticator {
+	return &ed25519Authenticator{
+		lookup: lookup,
+	}
+}
+
+type ed25519Authenticator struct {
+	lookup DeviceKeyLookup
+}
+
+func (auth *ed25519Authenticator) NewChallenge(login *Login) (*LoginChallenge, error) {
+	return NewLoginChallenge()
+}
+
+func (auth *ed25519Authenticator) CheckResponse(login *Login, challenge *LoginChallenge, response *LoginResponse) (Login, error) {
+	pubKey, err := auth.lookup(login)
+	if err != nil {
+		if GetErrCode(err) == ErrCode_LoginFailed {
+			return Login{}, err
+		}
+		return Login{}, ErrCode_LoginFailed.Wrap(err)
+	}
+	if pubKey == nil || !isSigningKit(pubKey.CryptoKitID) || len(pubKey.KeyBytes) != ed25519.PublicKeySize {
+		return Login{}, ErrCode_LoginFailed.Error("device has no ed25519 public key")
+	}
+	if len(challenge.Hash) == 0 || !ed25519.Verify(pubKey.KeyBytes, loginSigningMessage(challenge.Hash), response.HashResponse) {
+		return Login{}, ErrCode_LoginFailed.Error("login challenge response verification failed")
+	}
+
+	verified := *login
+	verified.Checkpoint = nil
+	return verified, nil
+}
+
+func loginSigningMessage(hash []byte) []byte {
+	msg := make([]byte, 0, len(loginSigningContext)+len(hash))
+	msg = append(msg, loginSigningContext...)
+	return append(msg, hash...)
+}
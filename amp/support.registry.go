@@ -125,6 +125,8 @@ func (reg *registry) GetAppForInvocation(invocation string) (*App, error) {
 }
 
 func (reg *registry) MakeValue(attrSpec tag.ID) (tag.Value, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
 
 	// Often, an attrID will be a unnamed scalar attr (which means we can get the elemDef directly.
 	// This is also essential during bootstrapping when the client sends a RegisterDefs is not registered yet.
@@ -138,6 +140,21 @@ func (reg *registry) MakeValue(attrSpec tag.ID) (tag.Value, error) {
 	return def.Prototype.New(), nil
 }
 
+// Implements Registry
+func (reg *registry) GetAttrSpec(attrSpec tag.ID) (tag.Spec, error) {
+	reg.mu.RLock()
+	defer reg.mu.RUnlock()
+
+	def, exists := reg.elemDefs[attrSpec]
+	if !exists {
+		def, exists = reg.attrDefs[attrSpec]
+		if !exists {
+			return tag.Spec{}, ErrCode_AttrNotFound.Errorf("GetAttrSpec: attr %s not found", attrSpec.String())
+		}
+	}
+	return def.Spec, nil
+}
+
 /*
 func (reg *registry) RegisterDefs(defs *RegisterDefs) error {
 
@@ -0,0 +1,143 @@
+package amp
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ClassifyURL returns the UrlScheme of the given URL.
+// A URL without a scheme (e.g. "app-alias/cmd") is taken to be an amp URL.
+func ClassifyURL(u *url.URL) UrlScheme {
+	switch strings.ToLower(u.Scheme) {
+	case "", "amp":
+		return UrlScheme_Amp
+	case "http", "https":
+		return UrlScheme_Http
+	case "data":
+		return UrlScheme_Data
+	case "file":
+		return UrlScheme_File
+	case "git":
+		return UrlScheme_Git
+	}
+	return UrlScheme_Unrecognized
+}
+
+// AppInvocation returns the app alias named by the given amp URL: the host of "amp://app-alias/cmd/uri",
+// or if it has none, the first path element (e.g. "amp:app-alias/cmd" or "app-alias/cmd").
+func AppInvocation(u *url.URL) string {
+	if u.Host != "" {
+		return u.Host
+	}
+	invocation, _, _ := strings.Cut(strings.TrimPrefix(u.Opaque+u.Path, "/"), "/")
+	return invocation
+}
+
+// ParseURL parses PinTarget.URL into URL and Values (if not already parsed), returning an ErrCode_InvalidURI error if it is malformed.
+func (req *Request) ParseURL() error {
+	if req.URL != nil {
+		return nil
+	}
+	if req.PinTarget == nil || req.PinTarget.URL == "" {
+		return ErrCode_InvalidURI.Error("missing URL")
+	}
+	u, err := url.Parse(req.PinTarget.URL)
+	if err != nil {
+		return ErrCode_InvalidURI.Wrap(err)
+	}
+	req.URL = u
+	req.Values = u.Query()
+	return nil
+}
+
+// Route names what serves a request, as returned by URLRouter.Route.
+type Route struct {
+	Scheme  UrlScheme // scheme of the request's URL, or UrlScheme_Nil if the target has no URL
+	App     *App      // set if an app serves the request (an amp URL or a target given by ID)
+	Handler Pinner    // otherwise, the handler registered for Scheme
+}
+
+// URLRouter routes each request by the UrlScheme of its PinTarget.URL -- concurrency safe.
+//
+// An amp URL (or a target with no URL) is served by the app it invokes (see AppInvocation and Registry.GetAppForInvocation),
+// while other schemes are served by the Pinner registered for that scheme.  A nil *URLRouter routes only to apps.
+type URLRouter struct {
+	mu       sync.RWMutex
+	handlers map[UrlScheme]Pinner
+}
+
+// NewURLRouter returns a URLRouter with no scheme handlers.
+func NewURLRouter() *URLRouter {
+	return &URLRouter{
+		handlers: make(map[UrlScheme]Pinner),
+	}
+}
+
+// Handle registers the given handler to serve requests whose URL has the given scheme, replacing any previous handler.
+// A handler registered for UrlScheme_Unrecognized serves URLs of any scheme having no handler of its own.  If handler is nil, the scheme's handler is removed.
+func (r *URLRouter) Handle(scheme UrlScheme, handler Pinner) error {
+	if scheme == UrlScheme_Nil || scheme == UrlScheme_Amp {
+		return ErrCode_BadValue.Errorf("%v is not routed to a handler", scheme)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if handler == nil {
+		delete(r.handlers, scheme)
+	} else {
+		r.handlers[scheme] = handler
+	}
+	return nil
+}
+
+// Route returns what serves the given request, parsing its URL if needed (see Request.ParseURL).
+//
+// A malformed URL, an amp URL naming no app, or a scheme having no handler yields an ErrCode_InvalidURI error,
+// while an app alias or target ID not in the given Registry yields an ErrCode_AppNotFound error.
+func (r *URLRouter) Route(reg Registry, req *Request) (Route, error) {
+	target := req.PinTarget
+	if target == nil {
+		return Route{}, ErrBadTarget
+	}
+	if target.URL == "" {
+		app, err := reg.GetAppByTag(target.AsID())
+		return Route{App: app}, err
+	}
+	if err := req.ParseURL(); err != nil {
+		return Route{}, err
+	}
+
+	route := Route{
+		Scheme: ClassifyURL(req.URL),
+	}
+	if route.Scheme == UrlScheme_Amp {
+		invocation := AppInvocation(req.URL)
+		if invocation == "" {
+			return route, ErrCode_InvalidURI.Errorf("URL %q names no app", target.URL)
+		}
+		var err error
+		route.App, err = reg.GetAppForInvocation(invocation)
+		return route, err
+	}
+
+	route.Handler = r.handler(route.Scheme)
+	if route.Handler == nil {
+		return route, ErrCode_InvalidURI.Errorf("no handler for URL scheme %q", req.URL.Scheme)
+	}
+	return route, nil
+}
+
+// handler returns the handler registered for the given scheme (falling back to UrlScheme_Unrecognized), or nil if none.
+func (r *URLRouter) handler(scheme UrlScheme) Pinner {
+	if r == nil {
+		return nil
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if handler := r.handlers[scheme]; handler != nil {
+		return handler
+	}
+	return r.handlers[UrlScheme_Unrecognized]
+}
@@ -0,0 +1,197 @@
+package amp
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+const (
+	// DefaultCompressAbove is the section byte size at or above which NegotiatedTxEncoding compresses.
+	DefaultCompressAbove = 16 * 1024
+)
+
+// TxEncoding specifies how a TxMsg is encoded onto the wire.
+type TxEncoding struct {
+	Codec         TxCodec // codec used to compress a section; TxCodec_None disables compression
+	CompressAbove int     // sections smaller than this many bytes are sent uncompressed
+	CompressBody  bool    // if set, the TxMsg body (TxEnvelope and TxOps) is also compressed
+	Level         int     // codec compression level (e.g. flate.BestSpeed); 0 denotes the codec default
+	HeaderVersion byte    // TxHeader version written (see Protocol); 0 denotes MinTxHeaderVersion, written until a Handshake negotiates one
+}
+
+// DefaultTxEncoding is the encoding used by TxMsg.MarshalToWriter and by a transport until a Handshake is exchanged.
+// It sends no compressed sections since a peer predating TxCodec support reads TxHeader bytes 12 and 13 as reserved.
+var DefaultTxEncoding = TxEncoding{
+	Codec:         TxCodec_None,
+	CompressAbove: DefaultCompressAbove,
+}
+
+// NegotiatedTxEncoding is the encoding a transport adopts once a Handshake is exchanged (see ExchangeHandshake),
+// adjusted by Protocol.TxEncoding to what both peers support.
+var NegotiatedTxEncoding = TxEncoding{
+	Codec:         TxCodec_Deflate,
+	CompressAbove: DefaultCompressAbove,
+}
+
+// TxCodecs is a set of TxCodec values, used to express which codecs a peer is able to decode.
+type TxCodecs uint32
+
+// SupportedTxCodecs is the set of TxCodecs that this package encodes and decodes.
+const SupportedTxCodecs = TxCodecs(1<<TxCodec_None | 1<<TxCodec_Deflate | 1<<TxCodec_Zlib | 1<<TxCodec_Gzip)
+
+// Has returns true if the given codec is in this set.
+func (codecs TxCodecs) Has(codec TxCodec) bool {
+	return codec >= 0 && codec < 32 && codecs&(1<<codec) != 0
+}
+
+// Negotiate returns this encoding adjusted so that a peer accepting the given codecs can decode it.
+// If the peer does not accept enc.Codec, compression is turned off.
+func (enc TxEncoding) Negotiate(peer TxCodecs) TxEncoding {
+	if !peer.Has(enc.Codec) || !SupportedTxCodecs.Has(enc.Codec) {
+		enc.Codec = TxCodec_None
+	}
+	return enc
+}
+
+// TxEncoder is implemented by a Transport whose outbound TxEncoding can be changed, e.g. once a peer's accepted TxCodecs are known.
+type TxEncoder interface {
+	SetTxEncoding(enc TxEncoding)
+}
+
+// shouldCompress returns true if a section of the given size is to be compressed.
+func (enc *TxEncoding) shouldCompress(sectionSz int) bool {
+	return enc.Codec != TxCodec_None && sectionSz > 0 && sectionSz >= enc.CompressAbove
+}
+
+// appendCompressed appends the uncompressed size of src followed by src compressed with the given codec.
+func appendCompressed(dst []byte, codec TxCodec, level int, src []byte) ([]byte, error) {
+	dst = binary.AppendUvarint(dst, uint64(len(src)))
+	buf := bytes.NewBuffer(dst)
+
+	zw, err := getCodecWriter(codec, level, buf)
+	if err != nil {
+		return dst, err
+	}
+	defer putCodecWriter(codec, level, zw)
+
+	if _, err = zw.Write(src); err != nil {
+		return dst, err
+	}
+	if err = zw.Close(); err != nil {
+		return dst, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompress replaces dst with the decompression of src, which was encoded by appendCompressed().
+// Decompression stops with ErrTxTooLarge if the declared uncompressed size exceeds maxSz.
+func decompress(dst []byte, codec TxCodec, src []byte, maxSz int) ([]byte, error) {
+	rawLen, n := binary.Uvarint(src)
+	if n <= 0 {
+		return dst[:0], ErrMalformedTx
+	}
+	if rawLen > uint64(maxSz) {
+		return dst[:0], ErrTxTooLarge
+	}
+
+	var (
+		zr  io.ReadCloser
+		err error
+	)
+	rd := bytes.NewReader(src[n:])
+	switch codec {
+	case TxCodec_Deflate:
+		zr = flate.NewReader(rd)
+	case TxCodec_Zlib:
+		zr, err = zlib.NewReader(rd)
+	case TxCodec_Gzip:
+		zr, err = gzip.NewReader(rd)
+	default:
+		err = ErrCode_UnsupportedOp.Errorf("unsupported TxCodec %v", codec)
+	}
+	if err != nil {
+		return dst[:0], wrapMalformed(err)
+	}
+	defer zr.Close()
+
+	dst, err = readFull(zr, dst[:0], int(rawLen))
+	if err != nil {
+		return dst, wrapMalformed(err)
+	}
+
+	// reading to the end verifies the codec checksum (if any) and that no bytes remain beyond the declared size
+	extra, err := io.Copy(io.Discard, io.LimitReader(zr, 1))
+	if err != nil {
+		return dst, wrapMalformed(err)
+	}
+	if extra != 0 {
+		return dst, ErrCode_MalformedTx.Error("compressed section exceeds its declared size")
+	}
+	return dst, nil
+}
+
+func wrapMalformed(err error) error {
+	switch GetErrCode(err) {
+	case ErrCode_MalformedTx, ErrCode_UnsupportedOp:
+		return err
+	}
+	return ErrCode_MalformedTx.Wrap(err)
+}
+
+// codecWriter is implemented by flate, zlib, and gzip writers
+type codecWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+type codecKey struct {
+	codec TxCodec
+	level int
+}
+
+// gCodecWriters maps a codecKey to a *sync.Pool of codecWriters, since compression writers are expensive to allocate.
+var gCodecWriters sync.Map
+
+func getCodecWriter(codec TxCodec, level int, w io.Writer) (codecWriter, error) {
+	key := codecKey{codec, level}
+	if pool, ok := gCodecWriters.Load(key); ok {
+		if zw, _ := pool.(*sync.Pool).Get().(codecWriter); zw != nil {
+			zw.Reset(w)
+			return zw, nil
+		}
+	}
+
+	if level == 0 {
+		level = flate.DefaultCompression
+	}
+
+	var (
+		zw  codecWriter
+		err error
+	)
+	switch codec {
+	case TxCodec_Deflate:
+		zw, err = flate.NewWriter(w, level)
+	case TxCodec_Zlib:
+		zw, err = zlib.NewWriterLevel(w, level)
+	case TxCodec_Gzip:
+		zw, err = gzip.NewWriterLevel(w, level)
+	default:
+		err = ErrCode_UnsupportedOp.Errorf("unsupported TxCodec %v", codec)
+	}
+	if err != nil {
+		return nil, ErrCode_UnsupportedOp.Wrap(err)
+	}
+	return zw, nil
+}
+
+func putCodecWriter(codec TxCodec, level int, zw codecWriter) {
+	zw.Reset(nil)
+	pool, _ := gCodecWriters.LoadOrStore(codecKey{codec, level}, &sync.Pool{})
+	pool.(*sync.Pool).Put(zw)
+}
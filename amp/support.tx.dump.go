@@ -0,0 +1,282 @@
+package amp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/gogo/protobuf/proto"
+)
+
+// txDump is the JSON form of a TxMsg written by TxMsg.Dump() and read by ReadTxDump().
+type txDump struct {
+	Status    string          `json:"Status,omitempty"`
+	GenesisID string          `json:"GenesisID,omitempty"`
+	ContextID string          `json:"ContextID,omitempty"`
+	From      json.RawMessage `json:"From,omitempty"`
+	To        json.RawMessage `json:"To,omitempty"`
+	Epoch     json.RawMessage `json:"Epoch,omitempty"`
+	Tags      json.RawMessage `json:"Tags,omitempty"`
+	Ops       []txOpDump      `json:"Ops"`
+}
+
+type txOpDump struct {
+	OpCode string          `json:"OpCode"`
+	CellID string          `json:"CellID,omitempty"`
+	AttrID string          `json:"AttrID,omitempty"`
+	Attr   string          `json:"Attr,omitempty"` // canonic tag.Spec of AttrID, if known
+	ItemID string          `json:"ItemID,omitempty"`
+	EditID string          `json:"EditID,omitempty"`
+	Value  json.RawMessage `json:"Value,omitempty"` // op value as JSON, if its type is registered
+	Data   []byte          `json:"Data,omitempty"`  // raw op value, if it could not be decoded
+}
+
+// Dump writes a human-readable JSON rendering of this TxMsg's envelope and TxOps to w.
+//
+// IDs are written in Base32 and each op value is decoded via reg.MakeValue() and written as JSON.
+// Values that cannot be decoded (e.g. unregistered or sealed) are written as base64 "Data".
+// If reg is nil, only raw values are written.  ReadTxDump() parses this output back into a TxMsg.
+func (tx *TxMsg) Dump(reg Registry, w io.Writer) error {
+	dump := txDump{
+		Status:    tx.Status.String(),
+		GenesisID: base32OrEmpty(tx.GenesisID()),
+		ContextID: base32OrEmpty(tx.ContextID()),
+		Ops:       make([]txOpDump, 0, len(tx.Ops)),
+	}
+
+	var err error
+	if dump.From, err = marshalJSON(tx.From); err != nil {
+		return err
+	}
+	if dump.To, err = marshalJSON(tx.To); err != nil {
+		return err
+	}
+	if dump.Epoch, err = marshalJSON(tx.Epoch); err != nil {
+		return err
+	}
+	if dump.Tags, err = marshalJSON(tx.TxEnvelope.Tags); err != nil {
+		return err
+	}
+
+	for i, op := range tx.Ops {
+		opDump := txOpDump{
+			OpCode: op.OpCode.String(),
+			CellID: base32OrEmpty(op.CellID),
+			AttrID: base32OrEmpty(op.AttrID),
+			ItemID: base32OrEmpty(op.ItemID),
+			EditID: base32OrEmpty(op.EditID),
+		}
+		if reg != nil && op.AttrID.IsSet() {
+			if spec, err := reg.GetAttrSpec(op.AttrID); err == nil {
+				opDump.Attr = spec.Canonic
+			}
+		}
+		if op.DataLen > 0 {
+			opDump.Value, opDump.Data = tx.dumpOpValue(reg, i)
+		}
+		dump.Ops = append(dump.Ops, opDump)
+	}
+
+	out, err := json.MarshalIndent(&dump, "", "  ")
+	if err != nil {
+		return ErrCode_ExportErr.Wrap(err)
+	}
+	out = append(out, '\n')
+	_, err = w.Write(out)
+	return err
+}
+
+// dumpOpValue returns the given op's value as JSON or, if it can't be decoded, as raw bytes.
+func (tx *TxMsg) dumpOpValue(reg Registry, idx int) (json.RawMessage, []byte) {
+	op := tx.Ops[idx]
+	if !tx.IsSealed() && reg != nil {
+		if val, err := reg.MakeValue(op.AttrID); err == nil {
+			if err = tx.UnmarshalOpValue(idx, val); err == nil {
+				if valJSON, err := marshalJSON(val); err == nil && valJSON != nil {
+					return valJSON, nil
+				}
+			}
+		}
+	}
+	dataLen := uint64(len(tx.DataStore))
+	if op.DataLen > dataLen || op.DataOfs > dataLen-op.DataLen {
+		return nil, nil
+	}
+	return nil, tx.DataStore[op.DataOfs : op.DataOfs+op.DataLen]
+}
+
+// ReadTxDump parses the JSON form written by TxMsg.Dump() into a new TxMsg, allowing test transactions to be authored by hand.
+//
+// Each op's AttrID may be given in Base32 ("AttrID") or as a tag.Spec ("Attr"); a "Value" is parsed using the type registered for the AttrID.
+// If GenesisID is omitted, a new one is generated; if an op's EditID is omitted, it defaults to the tx genesis edit (like TxMsg.Upsert).
+func ReadTxDump(reg Registry, r io.Reader) (*TxMsg, error) {
+	var dump txDump
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&dump); err != nil {
+		return nil, ErrCode_BadValue.Wrap(err)
+	}
+
+	tx := NewTxMsg(dump.GenesisID == "")
+	err := tx.loadDump(reg, &dump)
+	if err != nil {
+		tx.ReleaseRef()
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (tx *TxMsg) loadDump(reg Registry, dump *txDump) error {
+	var err error
+	if dump.Status != "" {
+		status, exists := OpStatus_value[dump.Status]
+		if !exists {
+			return ErrCode_BadValue.Errorf("unknown Status %q", dump.Status)
+		}
+		tx.Status = OpStatus(status)
+	}
+	if dump.GenesisID != "" {
+		genesisID, err := parseDumpID("GenesisID", dump.GenesisID)
+		if err != nil {
+			return err
+		}
+		tx.SetGenesisID(genesisID)
+	}
+	if dump.ContextID != "" {
+		contextID, err := parseDumpID("ContextID", dump.ContextID)
+		if err != nil {
+			return err
+		}
+		tx.SetContextID(contextID)
+	}
+	if tx.From, err = unmarshalJSON(dump.From, tx.From); err != nil {
+		return err
+	}
+	if tx.To, err = unmarshalJSON(dump.To, tx.To); err != nil {
+		return err
+	}
+	if tx.Epoch, err = unmarshalJSON(dump.Epoch, tx.Epoch); err != nil {
+		return err
+	}
+	if tx.TxEnvelope.Tags, err = unmarshalJSON(dump.Tags, tx.TxEnvelope.Tags); err != nil {
+		return err
+	}
+
+	for _, opDump := range dump.Ops {
+		if err = tx.loadOpDump(reg, &opDump); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *TxMsg) loadOpDump(reg Registry, opDump *txOpDump) error {
+	var op TxOp
+
+	opCode, exists := TxOpCode_value[opDump.OpCode]
+	if !exists {
+		return ErrCode_BadValue.Errorf("unknown OpCode %q", opDump.OpCode)
+	}
+	op.OpCode = TxOpCode(opCode)
+
+	var err error
+	if op.CellID, err = parseDumpID("CellID", opDump.CellID); err != nil {
+		return err
+	}
+	if op.AttrID, err = parseDumpID("AttrID", opDump.AttrID); err != nil {
+		return err
+	}
+	if opDump.Attr != "" {
+		specID := tag.Spec{}.With(opDump.Attr).ID
+		if op.AttrID.IsNil() {
+			op.AttrID = specID
+		} else if op.AttrID != specID {
+			return ErrCode_BadValue.Errorf("AttrID %s does not match Attr %q", opDump.AttrID, opDump.Attr)
+		}
+	}
+	if op.ItemID, err = parseDumpID("ItemID", opDump.ItemID); err != nil {
+		return err
+	}
+	if op.EditID, err = parseDumpID("EditID", opDump.EditID); err != nil {
+		return err
+	}
+	if op.EditID.IsNil() {
+		op.EditID = tag.Genesis(tx.GenesisID())
+	}
+
+	switch {
+	case len(opDump.Value) > 0:
+		if reg == nil {
+			return ErrCode_BadValue.Error("op Value requires a Registry")
+		}
+		val, err := reg.MakeValue(op.AttrID)
+		if err != nil {
+			return err
+		}
+		pb, ok := val.(proto.Message)
+		if !ok {
+			return ErrCode_BadValue.Errorf("attr %q does not support JSON", opDump.Attr)
+		}
+		if err = jsonpb.Unmarshal(bytes.NewReader(opDump.Value), pb); err != nil {
+			return ErrCode_BadValue.Wrap(err)
+		}
+		return tx.MarshalOp(&op, val)
+	case len(opDump.Data) > 0:
+		tx.MarshalOpWithBuf(&op, opDump.Data)
+		return nil
+	default:
+		return tx.MarshalOp(&op, nil)
+	}
+}
+
+func base32OrEmpty(id tag.ID) string {
+	if id.IsNil() {
+		return ""
+	}
+	return id.Base32()
+}
+
+func parseDumpID(field, str string) (tag.ID, error) {
+	if str == "" {
+		return tag.ID{}, nil
+	}
+	id, err := tag.ParseBase32(str)
+	if err != nil {
+		return tag.ID{}, ErrCode_BadValue.Errorf("%s: invalid Base32 tag.ID %q", field, str)
+	}
+	return id, nil
+}
+
+// marshalJSON returns the JSON form of the given value, or nil if it is nil or not a proto.Message.
+func marshalJSON(val any) (json.RawMessage, error) {
+	pb, ok := val.(proto.Message)
+	if !ok || reflect.ValueOf(pb).IsNil() {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	m := jsonpb.Marshaler{
+		OrigName: true,
+	}
+	if err := m.Marshal(&buf, pb); err != nil {
+		return nil, ErrCode_ExportErr.Wrap(err)
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalJSON parses src into a new instance of T, returning dst unchanged if src is empty.
+func unmarshalJSON[T any, P interface {
+	*T
+	proto.Message
+}](src json.RawMessage, dst P) (P, error) {
+	if len(src) == 0 {
+		return dst, nil
+	}
+	out := P(new(T))
+	if err := jsonpb.Unmarshal(bytes.NewReader(src), out); err != nil {
+		return dst, ErrCode_BadValue.Wrap(err)
+	}
+	return out, nil
+}
@@ -26,6 +26,16 @@ func (header TxHeader) TxDataLen() int {
 	return int(binary.LittleEndian.Uint32(header[8:12]))
 }
 
+// DataCodec returns the TxCodec used to compress the DataStore that follows the body.
+func (header TxHeader) DataCodec() TxCodec {
+	return TxCodec(header[12])
+}
+
+// BodyCodec returns the TxCodec used to compress the body (TxEnvelope and TxOps) that follows this header.
+func (header TxHeader) BodyCodec() TxCodec {
+	return TxCodec(header[13])
+}
+
 func NewTxMsg(genesis bool) *TxMsg {
 	tx := gTxMsgPool.Get().(*TxMsg)
 	tx.refCount = 1
@@ -107,13 +117,21 @@ func MarshalAttr(cellID, attrID tag.ID, attrVal tag.Value) (*TxMsg, error) {
 	return tx, nil
 }
 
+// UnmarshalOpValue unmarshals the value of the TxOp at the given index into out.
+// If this tx is sealed, ErrTxSealed is returned since its DataStore is ciphertext until Open() is called.
 func (tx *TxMsg) UnmarshalOpValue(idx int, out tag.Value) error {
 	if idx < 0 || idx >= len(tx.Ops) {
 		return ErrCode_MalformedTx.Error("UnmarshalOpValue: index out of range")
 	}
+	if tx.IsSealed() {
+		return ErrTxSealed
+	}
 	op := tx.Ops[idx]
-	ofs := op.DataOfs
-	span := tx.DataStore[ofs : ofs+op.DataLen]
+	dataLen := uint64(len(tx.DataStore))
+	if op.DataLen > dataLen || op.DataOfs > dataLen-op.DataLen {
+		return ErrTxBounds
+	}
+	span := tx.DataStore[op.DataOfs : op.DataOfs+op.DataLen]
 	return out.Unmarshal(span)
 }
 
@@ -149,15 +167,20 @@ var (
 	ErrPropertyNotFound = ErrCode_BadRequest.Error("property not found")
 )
 
+// sortOps sorts Ops by TxOpID, keeping ops having the same TxOpID in their current order.
 func (tx *TxMsg) sortOps() {
 	if !tx.OpsSorted {
 		tx.OpsSorted = true
-		sort.Slice(tx.Ops, func(i, j int) bool {
-			return tx.Ops[i].TxOpID.CompareTo(&tx.Ops[j].TxOpID) < 0
-		})
+		sortTxOps(tx.Ops)
 	}
 }
 
+func sortTxOps(ops []TxOp) {
+	sort.SliceStable(ops, func(i, j int) bool {
+		return ops[i].TxOpID.CompareTo(&ops[j].TxOpID) < 0
+	})
+}
+
 // If reqID == 0, then this sends an attr to the client's session controller (vs a specific request)
 func SendMetaAttr(sess Session, context tag.ID, status OpStatus, attrID tag.ID, val tag.Value) error {
 	tx, err := MarshalAttr(MetaNodeID, attrID, val)
@@ -199,12 +222,26 @@ func (tx *TxMsg) Upsert(cellID, attrID, itemID tag.ID, val tag.Value) error {
 	return tx.MarshalOp(&op, val)
 }
 
+// Revise is like Upsert() but forms the EditID from predEditID (the EditID of the revision being replaced) and this tx's GenesisID,
+// so that the edit extends the element's revision lineage rather than starting a new one.
+func (tx *TxMsg) Revise(cellID, attrID, itemID, predEditID tag.ID, val tag.Value) error {
+	op := TxOp{}
+	op.OpCode = TxOpCode_UpsertElement
+	op.CellID = cellID
+	op.AttrID = attrID
+	op.ItemID = itemID
+	op.EditID = predEditID.FormEditID(tx.GenesisID())
+
+	return tx.MarshalOp(&op, val)
+}
+
 // Marshals a TxOp and optional value to the given Tx's to and data store.
 //
 // On success:
 //   - TxOp.DataOfs and TxOp.DataLen are overwritten,
-//   - TxMsg.DataStore is appended with the serialization of val, and
-//   - the TxOp is appended to TxMsg.Ops.
+//   - TxMsg.DataStore is appended with the serialization of val,
+//   - the TxOp is appended to TxMsg.Ops, and
+//   - TxMsg.OpsSorted is cleared.
 func (tx *TxMsg) MarshalOp(op *TxOp, val tag.Value) error {
 	if val == nil {
 		op.DataOfs = 0
@@ -221,73 +258,34 @@ func (tx *TxMsg) MarshalOp(op *TxOp, val tag.Value) error {
 
 	tx.OpCount += 1
 	tx.Ops = append(tx.Ops, *op)
+	tx.OpsSorted = false
 	return nil
 }
 
+// MarshalOpWithBuf is MarshalOp for an already serialized value.
 func (tx *TxMsg) MarshalOpWithBuf(op *TxOp, valBuf []byte) {
 	op.DataOfs = uint64(len(tx.DataStore))
 	op.DataLen = uint64(len(valBuf))
 	tx.DataStore = append(tx.DataStore, valBuf...)
 	tx.OpCount += 1
 	tx.Ops = append(tx.Ops, *op)
+	tx.OpsSorted = false
 }
 
+// ReadTxMsg reads a TxMsg from the given stream using the limits of a default TxReader.
 func ReadTxMsg(stream io.Reader) (*TxMsg, error) {
-	readBytes := func(dst []byte) error {
-		for L := 0; L < len(dst); {
-			n, err := stream.Read(dst[L:])
-			if err != nil {
-				return err
-			}
-			L += n
-		}
-		return nil
-	}
-
-	var header TxHeader
-	if err := readBytes(header[:]); err != nil {
-		return nil, err
-	}
-
-	marker := uint32(header[0])<<16 | uint32(header[1])<<8 | uint32(header[2])
-	if marker != uint32(Const_TxHeader_Marker) {
-		return nil, ErrMalformedTx
-	}
-	if header[3] < byte(Const_TxHeader_Version) {
-		return nil, ErrMalformedTx
-	}
-
-	tx := NewTxMsg(false)
-	bodyLen := header.TxBodyLen()
-	dataLen := header.TxDataLen()
-
-	// Use tx.DataStore to hold the body for unmarshalling.
-	// The tx body contains TxMsg fields and TxOps
-	{
-		needSz := max(bodyLen, dataLen)
-		if cap(tx.DataStore) < needSz {
-			tx.DataStore = make([]byte, max(needSz, 2048))
-		}
-
-		buf := tx.DataStore[:bodyLen-int(Const_TxHeader_Size)]
-		if err := readBytes(buf); err != nil {
-			return nil, err
-		}
-		if err := tx.UnmarshalBody(buf); err != nil {
-			return nil, err
-		}
-	}
-
-	// Read tx data store -- used for on-demand tag.Value unmarshalling
-	tx.DataStore = tx.DataStore[:dataLen]
-	if err := readBytes(tx.DataStore); err != nil {
-		return nil, err
-	}
+	r := TxReader{}
+	return r.ReadTxMsg(stream)
+}
 
-	return tx, nil
+// MarshalToWriter writes this TxMsg to the given stream using DefaultTxEncoding.
+func (tx *TxMsg) MarshalToWriter(scrap *[]byte, w io.Writer) error {
+	return tx.MarshalToWriterWith(DefaultTxEncoding, scrap, w)
 }
 
-func (tx *TxMsg) MarshalToWriter(scrap *[]byte, w io.Writer) (err error) {
+// MarshalToWriterWith writes this TxMsg to the given stream, compressing sections as specified by enc.
+// scrap is used as a work buffer and is retained by the caller for reuse.
+func (tx *TxMsg) MarshalToWriterWith(enc TxEncoding, scrap *[]byte, w io.Writer) (err error) {
 	writeBytes := func(src []byte) error {
 		for L := 0; L < len(src); {
 			n, err := w.Write(src[L:])
@@ -299,21 +297,82 @@ func (tx *TxMsg) MarshalToWriter(scrap *[]byte, w io.Writer) (err error) {
 		return nil
 	}
 
-	tx.MarshalHeaderAndOps(scrap)
+	var rawData []byte
+	if rawData, err = tx.marshalEncoded(enc, scrap); err != nil {
+		return
+	}
 	if err = writeBytes(*scrap); err != nil {
 		return
 	}
-	if err = writeBytes(tx.DataStore); err != nil {
+	if err = writeBytes(rawData); err != nil {
 		return
 	}
 	return
 }
 
+// MarshalToBuffer serializes this TxMsg into dst with no compression.
 func (tx *TxMsg) MarshalToBuffer(dst *[]byte) {
 	tx.MarshalHeaderAndOps(dst)
 	*dst = append(*dst, tx.DataStore...)
 }
 
+// MarshalToBufferWith serializes this TxMsg into dst, compressing sections as specified by enc.
+func (tx *TxMsg) MarshalToBufferWith(enc TxEncoding, dst *[]byte) error {
+	rawData, err := tx.marshalEncoded(enc, dst)
+	if err != nil {
+		return err
+	}
+	*dst = append(*dst, rawData...)
+	return nil
+}
+
+// marshalEncoded places the header and body into dst, followed by the DataStore if it was compressed.
+// Returns the DataStore bytes that are to follow dst (or nil if the DataStore was compressed).
+func (tx *TxMsg) marshalEncoded(enc TxEncoding, dst *[]byte) ([]byte, error) {
+	if vers := enc.HeaderVersion; vers != 0 && (vers < MinTxHeaderVersion || vers > MaxTxHeaderVersion) {
+		return nil, ErrCode_UnsupportedOp.Errorf("TxHeader version %#x not supported", vers)
+	}
+	tx.MarshalHeaderAndOps(dst)
+	buf := *dst
+	if enc.HeaderVersion != 0 {
+		buf[3] = enc.HeaderVersion
+	}
+	var err error
+
+	// Compress the body in place, keeping it only if it shrinks.
+	if bodyLen := len(buf) - int(Const_TxHeader_Size); enc.CompressBody && enc.shouldCompress(bodyLen) {
+		var packed []byte
+		packed, err = appendCompressed(buf[len(buf):], enc.Codec, enc.Level, buf[Const_TxHeader_Size:])
+		if err != nil {
+			return nil, err
+		}
+		if len(packed) < bodyLen {
+			buf = append(buf[:Const_TxHeader_Size], packed...)
+			buf[13] = byte(enc.Codec)
+			binary.LittleEndian.PutUint32(buf[4:8], uint32(len(buf)))
+		}
+	}
+
+	rawData := tx.DataStore
+	if dataLen := len(tx.DataStore); enc.shouldCompress(dataLen) {
+		bodyEnd := len(buf)
+		buf, err = appendCompressed(buf, enc.Codec, enc.Level, tx.DataStore)
+		if err != nil {
+			return nil, err
+		}
+		if packedLen := len(buf) - bodyEnd; packedLen < dataLen {
+			buf[12] = byte(enc.Codec)
+			binary.LittleEndian.PutUint32(buf[8:12], uint32(packedLen))
+			rawData = nil
+		} else {
+			buf = buf[:bodyEnd]
+		}
+	}
+
+	*dst = buf
+	return rawData, nil
+}
+
 func (tx *TxMsg) MarshalHeaderAndOps(dst *[]byte) {
 	buf := (*dst)[:0]
 	if cap(buf) < 300 {
@@ -321,38 +380,53 @@ func (tx *TxMsg) MarshalHeaderAndOps(dst *[]byte) {
 	}
 
 	headerAndOps := tx.MarshalOps(buf[:Const_TxHeader_Size])
+	putTxHeader(headerAndOps, len(tx.DataStore))
 
+	*dst = headerAndOps
+}
+
+// putTxHeader writes an uncompressed TxHeader into the leading bytes of the given header and body.
+func putTxHeader(headerAndOps []byte, dataLen int) {
 	header := headerAndOps[:Const_TxHeader_Size]
 	header[0] = byte((Const_TxHeader_Marker >> 16) & 0xFF)
 	header[1] = byte((Const_TxHeader_Marker >> 8) & 0xFF)
 	header[2] = byte((Const_TxHeader_Marker >> 0) & 0xFF)
-	header[3] = byte(Const_TxHeader_Version)
+	header[3] = MinTxHeaderVersion
+	clear(header[12:16])
 
 	binary.LittleEndian.PutUint32(header[4:8], uint32(len(headerAndOps)))
-	binary.LittleEndian.PutUint32(header[8:12], uint32(len(tx.DataStore)))
-
-	*dst = headerAndOps
+	binary.LittleEndian.PutUint32(header[8:12], uint32(dataLen))
 }
 
 func (tx *TxMsg) MarshalOps(dst []byte) []byte {
+	tx.OpCount = uint64(len(tx.Ops))
+	return appendTxBody(dst, &tx.TxEnvelope, tx.Ops)
+}
+
+// appendTxBody appends the given TxEnvelope followed by the given delta-encoded TxOps.
+func appendTxBody(dst []byte, env *TxEnvelope, ops []TxOp) []byte {
 
 	// TxEnvelope
 	{
-		tx.OpCount = uint64(len(tx.Ops))
-		infoLen := tx.TxEnvelope.Size()
+		infoLen := env.Size()
 		dst = binary.AppendUvarint(dst, uint64(infoLen))
 
 		p := len(dst)
 		dst = append(dst, make([]byte, infoLen)...)
-		tx.TxEnvelope.MarshalToSizedBuffer(dst[p : p+infoLen])
+		env.MarshalToSizedBuffer(dst[p : p+infoLen])
 	}
 
+	return appendTxOps(dst, ops)
+}
+
+// appendTxOps appends the given TxOps, each delta-encoded against the previous.
+func appendTxOps(dst []byte, ops []TxOp) []byte {
 	var (
 		op_prv [TxField_MaxFields]uint64
 		op_cur [TxField_MaxFields]uint64
 	)
 
-	for _, op := range tx.Ops {
+	for _, op := range ops {
 		dst = binary.AppendUvarint(dst, 0) // skip bytes (future use)
 		dst = binary.AppendUvarint(dst, uint64(op.OpCode))
 		dst = binary.AppendUvarint(dst, op.DataLen)
@@ -397,71 +471,106 @@ func (tx *TxMsg) MarshalOps(dst []byte) []byte {
 	return dst
 }
 
+// UnmarshalBody decodes a TxMsg body (TxEnvelope followed by TxOps), checking the bounds of every field read.
 func (tx *TxMsg) UnmarshalBody(src []byte) error {
 	p := 0
 
-	// TxEnvelope
-	{
-		infoLen, n := binary.Uvarint(src[0:])
+	readUvarint := func() (uint64, error) {
+		val, n := binary.Uvarint(src[p:])
 		if n <= 0 {
-			return ErrMalformedTx
+			return 0, ErrMalformedTx
 		}
 		p += n
+		return val, nil
+	}
+
+	// TxEnvelope
+	{
+		infoLen, err := readUvarint()
+		if err != nil {
+			return err
+		}
+		if infoLen > uint64(len(src)-p) {
+			return ErrTxBounds
+		}
 
 		tx.TxEnvelope = TxEnvelope{}
-		err := tx.TxEnvelope.Unmarshal(src[p : p+int(infoLen)])
+		err = tx.TxEnvelope.Unmarshal(src[p : p+int(infoLen)])
 		if err != nil {
 			return ErrMalformedTx
 		}
 		p += int(infoLen)
 	}
 
+	_, err := tx.unmarshalOps(src[p:], tx.OpCount)
+	return err
+}
+
+// unmarshalOps appends opCount TxOps read from src, returning the number of bytes read.
+func (tx *TxMsg) unmarshalOps(src []byte, opCount uint64) (int, error) {
+	p := 0
+
+	readUvarint := func() (uint64, error) {
+		val, n := binary.Uvarint(src[p:])
+		if n <= 0 {
+			return 0, ErrMalformedTx
+		}
+		p += n
+		return val, nil
+	}
+
+	// Each op occupies at least 5 bytes, so reject an OpCount that could not possibly fit
+	if opCount > uint64(len(src)-p)/5 {
+		return 0, ErrTxBounds
+	}
+
 	var (
 		op_cur [TxField_MaxFields]uint64
 	)
 
-	for i := uint64(0); i < tx.OpCount; i++ {
+	for i := uint64(0); i < opCount; i++ {
 		var op TxOp
-		var n int
 
 		// skip (future use)
-		var skip uint64
-		if skip, n = binary.Uvarint(src[p:]); n <= 0 {
-			return ErrMalformedTx
+		skip, err := readUvarint()
+		if err != nil {
+			return 0, err
 		}
-		p += n + int(skip)
+		if skip > uint64(len(src)-p) {
+			return 0, ErrTxBounds
+		}
+		p += int(skip)
 
 		// OpCode
-		var opCode uint64
-		if opCode, n = binary.Uvarint(src[p:]); n <= 0 {
-			return ErrMalformedTx
+		opCode, err := readUvarint()
+		if err != nil {
+			return 0, err
 		}
-		p += n
 		op.OpCode = TxOpCode(opCode)
 
 		// DataLen
-		if op.DataLen, n = binary.Uvarint(src[p:]); n <= 0 {
-			return ErrMalformedTx
+		if op.DataLen, err = readUvarint(); err != nil {
+			return 0, err
 		}
-		p += n
 
 		// DataOfs
-		if op.DataOfs, n = binary.Uvarint(src[p:]); n <= 0 {
-			return ErrMalformedTx
+		if op.DataOfs, err = readUvarint(); err != nil {
+			return 0, err
 		}
-		p += n
 
 		// hasFields
-		var hasFields uint64
-		if hasFields, n = binary.Uvarint(src[p:]); n <= 0 {
-			return ErrMalformedTx
+		hasFields, err := readUvarint()
+		if err != nil {
+			return 0, err
+		}
+		if hasFields>>TxField_MaxFields != 0 {
+			return 0, ErrTxBounds
 		}
-		p += n
 
 		for i := 0; i < int(TxField_MaxFields); i++ {
 			if hasFields&(1<<i) != 0 {
 				if p+8 > len(src) {
-					return ErrMalformedTx
+					return 0, ErrTxBounds
 				}
 				op_cur[i] = binary.LittleEndian.Uint64(src[p:])
 				p += 8
@@ -487,9 +596,35 @@ func (tx *TxMsg) UnmarshalBody(src []byte) error {
 		tx.Ops = append(tx.Ops, op)
 	}
 
+	return p, nil
+}
+
+// CheckDataBounds returns an error if any TxOp references bytes outside of DataStore.
+func (tx *TxMsg) CheckDataBounds() error {
+	dataLen := uint64(len(tx.DataStore))
+	for _, op := range tx.Ops {
+		if op.DataLen > dataLen || op.DataOfs > dataLen-op.DataLen {
+			return ErrTxBounds
+		}
+	}
 	return nil
 }
 
+// ElementID returns the CellID / AttrID / ItemID portion of this TxOpID.
+func (op *TxOpID) ElementID() ElementID {
+	return ElementID{op.CellID, op.AttrID, op.ItemID}
+}
+
+// CompareTo orders ElementIDs by CellID, then AttrID, then ItemID.
+func (elem ElementID) CompareTo(oth ElementID) int {
+	for i := range elem {
+		if diff := elem[i].CompareTo(oth[i]); diff != 0 {
+			return diff
+		}
+	}
+	return 0
+}
+
 func (op *TxOpID) CompareTo(oth *TxOpID) int {
 	if diff := op.CellID.CompareTo(oth.CellID); diff != 0 {
 		return int(diff)
@@ -0,0 +1,115 @@
+package amp
+
+import (
+	"sort"
+
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+)
+
+// TxOpRange iterates over a contiguous range of a TxMsg's ops in TxOpID order, decoding values only on request:
+//
+//	for ops := tx.AttrItems(cellID, attrID); ops.Next(); {
+//		op := ops.Op()
+//		...
+//	}
+//
+// Since a TxOpRange refers to ops by index, the TxMsg's ops should not be added to or reordered while it is in use.
+type TxOpRange struct {
+	tx  *TxMsg
+	idx int // index of the current op
+	end int // index past the last op in range
+}
+
+// CellOps returns the ops of the given cell, ordered by AttrID, ItemID, and then EditID.
+func (tx *TxMsg) CellOps(cellID tag.ID) TxOpRange {
+	return tx.selectOps(func(op *TxOpID) int {
+		return op.CellID.CompareTo(cellID)
+	})
+}
+
+// AttrItems returns the ops of the given cell attribute, ordered by ItemID and then EditID.
+func (tx *TxMsg) AttrItems(cellID, attrID tag.ID) TxOpRange {
+	return tx.selectOps(func(op *TxOpID) int {
+		if diff := op.CellID.CompareTo(cellID); diff != 0 {
+			return diff
+		}
+		return op.AttrID.CompareTo(attrID)
+	})
+}
+
+// ItemRange returns the ops of the given cell attribute whose ItemID is >= fromItemID and < toItemID, ordered by ItemID and then EditID.
+func (tx *TxMsg) ItemRange(cellID, attrID, fromItemID, toItemID tag.ID) TxOpRange {
+	return tx.selectOps(func(op *TxOpID) int {
+		if diff := op.CellID.CompareTo(cellID); diff != 0 {
+			return diff
+		}
+		if diff := op.AttrID.CompareTo(attrID); diff != 0 {
+			return diff
+		}
+		if op.ItemID.CompareTo(fromItemID) < 0 {
+			return -1
+		}
+		if op.ItemID.CompareTo(toItemID) >= 0 {
+			return 1
+		}
+		return 0
+	})
+}
+
+// selectOps sorts this tx's ops (if needed) and returns the range of ops for which cmp returns 0.
+// cmp returns < 0 for ops preceding the range and > 0 for ops following it.
+func (tx *TxMsg) selectOps(cmp func(op *TxOpID) int) TxOpRange {
+	tx.sortOps()
+
+	start := sort.Search(len(tx.Ops), func(i int) bool {
+		return cmp(&tx.Ops[i].TxOpID) >= 0
+	})
+	end := start + sort.Search(len(tx.Ops)-start, func(i int) bool {
+		return cmp(&tx.Ops[start+i].TxOpID) > 0
+	})
+	return TxOpRange{
+		tx:  tx,
+		idx: start - 1,
+		end: end,
+	}
+}
+
+// Next advances to the next op in range, returning false once the range is exhausted.
+func (ops *TxOpRange) Next() bool {
+	if ops.idx < ops.end {
+		ops.idx++
+	}
+	return ops.idx < ops.end
+}
+
+// Remaining returns the number of ops in range not yet visited by Next().
+func (ops *TxOpRange) Remaining() int {
+	return max(0, ops.end-ops.idx-1)
+}
+
+// Index returns the index of the current op within TxMsg.Ops.
+func (ops *TxOpRange) Index() int {
+	return ops.idx
+}
+
+// Op returns the current op.
+func (ops *TxOpRange) Op() *TxOp {
+	return &ops.tx.Ops[ops.idx]
+}
+
+// Load unmarshals the current op's value into dst.
+func (ops *TxOpRange) Load(dst tag.Value) error {
+	return ops.tx.UnmarshalOpValue(ops.idx, dst)
+}
+
+// Value instantiates the current op's value type via the given Registry and unmarshals the op's value into it.
+func (ops *TxOpRange) Value(reg Registry) (tag.Value, error) {
+	val, err := reg.MakeValue(ops.Op().AttrID)
+	if err != nil {
+		return nil, err
+	}
+	if err = ops.Load(val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
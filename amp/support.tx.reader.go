@@ -0,0 +1,131 @@
+package amp
+
+import (
+	"io"
+)
+
+const (
+	// DefaultMaxTxSz is the default max byte size of a TxMsg frame (header, body, and DataStore) accepted by a TxReader.
+	DefaultMaxTxSz = 64 << 20
+
+	// TxReader grows its buffers at most this many bytes ahead of what has actually been read.
+	// This prevents a hostile header from causing a large allocation that is never filled.
+	txReadChunkSz = 1 << 20
+)
+
+// TxReader reads TxMsgs from a stream, trusting nothing it reads.
+//
+// Header lengths are checked against MaxTxSz before any allocation and every uvarint and field read is bounds checked,
+// so a truncated or hostile frame yields an ErrCode_MalformedTx error rather than a panic or runaway allocation.
+// Compressed sections are decompressed transparently and their uncompressed size is also held to MaxTxSz.
+//
+// A TxReader is not safe for concurrent use.
+type TxReader struct {
+	MaxTxSz int      // max byte size of a TxMsg frame; if <= 0, DefaultMaxTxSz is used
+	Codecs  TxCodecs // TxCodecs accepted from the peer; if 0, SupportedTxCodecs is used
+	scrap   []byte   // holds compressed sections
+}
+
+// ReadTxMsg reads the next TxMsg from the given stream.
+// On error, the returned error is either an ErrCode_MalformedTx error or the error returned by the stream.
+func (r *TxReader) ReadTxMsg(stream io.Reader) (*TxMsg, error) {
+	maxTxSz := r.MaxTxSz
+	if maxTxSz <= 0 {
+		maxTxSz = DefaultMaxTxSz
+	}
+
+	var header TxHeader
+	if _, err := io.ReadFull(stream, header[:]); err != nil {
+		return nil, err
+	}
+
+	marker := uint32(header[0])<<16 | uint32(header[1])<<8 | uint32(header[2])
+	if marker != uint32(Const_TxHeader_Marker) {
+		return nil, ErrTxHeader
+	}
+	if vers := header[3]; vers < MinTxHeaderVersion {
+		return nil, ErrTxHeader
+	} else if vers > MaxTxHeaderVersion {
+		return nil, ErrCode_UnsupportedOp.Errorf("TxHeader version %#x not supported", vers)
+	}
+
+	bodyLen := header.TxBodyLen()
+	dataLen := header.TxDataLen()
+	if bodyLen < int(Const_TxHeader_Size) || dataLen < 0 {
+		return nil, ErrTxHeader
+	}
+	if uint64(bodyLen)+uint64(dataLen) > uint64(maxTxSz) {
+		return nil, ErrTxTooLarge
+	}
+
+	codecs := r.Codecs
+	if codecs == 0 {
+		codecs = SupportedTxCodecs
+	}
+	codecs &= SupportedTxCodecs
+	for _, codec := range [2]TxCodec{header.BodyCodec(), header.DataCodec()} {
+		if !codecs.Has(codec) {
+			return nil, ErrCode_UnsupportedOp.Errorf("TxCodec %v not accepted", codec)
+		}
+	}
+
+	tx := NewTxMsg(false)
+	err := r.readBody(tx, header, stream, bodyLen-int(Const_TxHeader_Size), dataLen, maxTxSz)
+	if err != nil {
+		tx.ReleaseRef()
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (r *TxReader) readBody(tx *TxMsg, header TxHeader, stream io.Reader, bodyLen, dataLen, maxTxSz int) error {
+	var err error
+
+	// Use tx.DataStore to hold the body for unmarshalling.
+	// The tx body contains TxMsg fields and TxOps
+	if codec := header.BodyCodec(); codec == TxCodec_None {
+		tx.DataStore, err = readFull(stream, tx.DataStore[:0], bodyLen)
+	} else if r.scrap, err = readFull(stream, r.scrap[:0], bodyLen); err == nil {
+		tx.DataStore, err = decompress(tx.DataStore, codec, r.scrap, maxTxSz)
+		maxTxSz -= len(tx.DataStore)
+	}
+	if err != nil {
+		return err
+	}
+	if err = tx.UnmarshalBody(tx.DataStore); err != nil {
+		return err
+	}
+
+	// Read tx data store -- used for on-demand tag.Value unmarshalling
+	if codec := header.DataCodec(); codec == TxCodec_None {
+		tx.DataStore, err = readFull(stream, tx.DataStore[:0], dataLen)
+	} else if r.scrap, err = readFull(stream, r.scrap[:0], dataLen); err == nil {
+		tx.DataStore, err = decompress(tx.DataStore, codec, r.scrap, maxTxSz)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.CheckDataBounds()
+}
+
+// readFull appends exactly n bytes read from the stream to dst, growing dst only as data arrives.
+func readFull(stream io.Reader, dst []byte, n int) ([]byte, error) {
+	for remain := n; remain > 0; {
+		step := min(remain, txReadChunkSz)
+		L := len(dst)
+		if cap(dst)-L < step {
+			grown := make([]byte, L, max(L+step, min(2*cap(dst), L+remain), 2048))
+			copy(grown, dst)
+			dst = grown
+		}
+		if _, err := io.ReadFull(stream, dst[L:L+step]); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return dst, err
+		}
+		dst = dst[:L+step]
+		remain -= step
+	}
+	return dst, nil
+}
@@ -0,0 +1,193 @@
+package amp
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+)
+
+// TxSealedContentType is the Tag.ContentType of the TxEnvelope.Tags entry that marks a TxMsg as sealed (encrypted).
+// The entry's Tag.UID is the nonce salt (base64 raw URL encoded) and Tag.Text is one of the TxSealed_* modes.
+const TxSealedContentType = "amp.tx.sealed/aes-gcm"
+
+const (
+	TxSealed_Data       = "data"     // only the DataStore is sealed; TxOps are readable
+	TxSealed_OpsAndData = "ops,data" // TxOps and DataStore are sealed; the tx carries no readable TxOps
+)
+
+const txSealSaltSz = 16
+
+// GenerateSymmetricKey returns a new random key for the given symmetric CryptoKitID.
+func GenerateSymmetricKey(kit CryptoKitID) (*CryptoKey, error) {
+	if kit != CryptoKit_AES_GCM {
+		return nil, ErrCode_UnsupportedOp.Errorf("unsupported symmetric kit %v", kit)
+	}
+	key := &CryptoKey{
+		CryptoKitID: kit,
+		KeyBytes:    make([]byte, 32),
+	}
+	if _, err := rand.Read(key.KeyBytes); err != nil {
+		return nil, ErrCode_InternalErr.Wrap(err)
+	}
+	return key, nil
+}
+
+// Seal encrypts this TxMsg's DataStore -- and if sealOps is set, its TxOps -- using the given symmetric key.
+// The TxEnvelope (e.g. GenesisID and ContextID) stays readable so that relays and intermediate hosts can route the tx.
+//
+// The nonce is derived from the tx GenesisID and a random salt, so a tx can be safely re-sealed.
+// If a tx is to be signed, Seal() must precede Sign().
+func (tx *TxMsg) Seal(key *CryptoKey, sealOps bool) error {
+	if tx.IsSealed() {
+		return ErrCode_BadRequest.Error("tx is already sealed")
+	}
+	aead, err := key.aead()
+	if err != nil {
+		return err
+	}
+
+	var salt [txSealSaltSz]byte
+	if _, err = rand.Read(salt[:]); err != nil {
+		return ErrCode_InternalErr.Wrap(err)
+	}
+
+	mode := TxSealed_Data
+	plaintext := tx.DataStore
+	if sealOps {
+		mode = TxSealed_OpsAndData
+		plaintext = binary.AppendUvarint(nil, uint64(len(tx.Ops)))
+		plaintext = appendTxOps(plaintext, tx.Ops)
+		plaintext = append(plaintext, tx.DataStore...)
+	}
+	nonce, aad, err := tx.sealNonce(aead, salt[:], mode)
+	if err != nil {
+		return err
+	}
+	if sealOps {
+		tx.Ops = tx.Ops[:0]
+		tx.OpCount = 0
+	}
+	tx.DataStore = aead.Seal(nil, nonce, plaintext, aad)
+
+	if tx.TxEnvelope.Tags == nil {
+		tx.TxEnvelope.Tags = &Tags{}
+	}
+	tx.TxEnvelope.Tags.SubTags = append(tx.TxEnvelope.Tags.SubTags, &Tags{
+		ID: &Tag{
+			ContentType: TxSealedContentType,
+			UID:         base64.RawURLEncoding.EncodeToString(salt[:]),
+			Text:        mode,
+		},
+	})
+	return nil
+}
+
+// IsSealed returns true if this TxMsg's payload is encrypted and must be opened before its values are read.
+func (tx *TxMsg) IsSealed() bool {
+	return tx.TxEnvelope.Tags.FindSubTags(TxSealedContentType) != nil
+}
+
+// Open decrypts a sealed TxMsg in place using the given symmetric key.
+// If the key is wrong or the payload was altered, ErrTxDecrypt (ErrCode_DecryptFailed) is returned.
+func (tx *TxMsg) Open(key *CryptoKey) error {
+	sealed := tx.TxEnvelope.Tags.FindSubTags(TxSealedContentType)
+	if sealed == nil {
+		return ErrCode_BadRequest.Error("tx is not sealed")
+	}
+	aead, err := key.aead()
+	if err != nil {
+		return err
+	}
+
+	salt, err := base64.RawURLEncoding.DecodeString(sealed.ID.UID)
+	if err != nil || len(salt) != txSealSaltSz {
+		return ErrCode_DecryptFailed.Error("malformed tx seal")
+	}
+	nonce, aad, err := tx.sealNonce(aead, salt, sealed.ID.Text)
+	if err != nil {
+		return err
+	}
+	plaintext, err := aead.Open(nil, nonce, tx.DataStore, aad)
+	if err != nil {
+		return ErrTxDecrypt
+	}
+
+	switch sealed.ID.Text {
+	case TxSealed_Data:
+		tx.DataStore = plaintext
+	case TxSealed_OpsAndData:
+		opCount, n := binary.Uvarint(plaintext)
+		if n <= 0 {
+			return ErrCode_DecryptFailed.Error("malformed sealed tx ops")
+		}
+		tx.Ops = tx.Ops[:0]
+		opsLen, err := tx.unmarshalOps(plaintext[n:], opCount)
+		if err != nil {
+			tx.Ops = tx.Ops[:0]
+			return ErrCode_DecryptFailed.Wrap(err)
+		}
+		tx.OpCount = opCount
+		tx.DataStore = plaintext[n+opsLen:]
+	default:
+		return ErrCode_DecryptFailed.Errorf("unsupported tx seal mode %q", sealed.ID.Text)
+	}
+
+	tx.TxEnvelope.Tags = tx.TxEnvelope.Tags.WithoutSubTags(TxSealedContentType)
+	return tx.CheckDataBounds()
+}
+
+// sealNonce derives the nonce from the GenesisID and the given salt and returns the data authenticated alongside the payload:
+// the GenesisID, the seal mode, and the readable TxOps (none if they are sealed), so none can be altered or swapped without Open failing.
+// The TxOps are authenticated sorted by TxOpID since reading a sealed tx's ops (e.g. AttrItems) may reorder them.
+func (tx *TxMsg) sealNonce(aead cipher.AEAD, salt []byte, mode string) (nonce, aad []byte, err error) {
+	genesisID := tx.GenesisID()
+	if genesisID.IsNil() {
+		return nil, nil, ErrCode_MalformedTx.Error("missing tx.GenesisID")
+	}
+	aad = make([]byte, 0, 64)
+	for _, v := range genesisID {
+		aad = binary.LittleEndian.AppendUint64(aad, v)
+	}
+
+	h := sha256.New()
+	h.Write(aad)
+	h.Write(salt)
+	nonce = h.Sum(nil)[:aead.NonceSize()]
+
+	ops := tx.Ops
+	if mode == TxSealed_OpsAndData {
+		ops = nil
+	} else if !tx.OpsSorted {
+		ops = append([]TxOp(nil), ops...)
+		sortTxOps(ops)
+	}
+	aad = binary.AppendUvarint(aad, uint64(len(mode)))
+	aad = append(aad, mode...)
+	aad = binary.AppendUvarint(aad, uint64(len(ops)))
+	aad = appendTxOps(aad, ops)
+	return nonce, aad, nil
+}
+
+func (key *CryptoKey) aead() (cipher.AEAD, error) {
+	if key == nil {
+		return nil, ErrCode_BadValue.Error("missing symmetric key")
+	}
+	switch key.CryptoKitID {
+	case CryptoKit_AES_GCM:
+		block, err := aes.NewCipher(key.KeyBytes)
+		if err != nil {
+			return nil, ErrCode_BadValue.Wrap(err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, ErrCode_BadValue.Wrap(err)
+		}
+		return aead, nil
+	case CryptoKit_SecretBox_NaCl:
+		return nil, ErrCode_UnsupportedOp.Error("CryptoKit_SecretBox_NaCl is not available in this build; use CryptoKit_AES_GCM")
+	}
+	return nil, ErrCode_UnsupportedOp.Errorf("unsupported symmetric kit %v", key.CryptoKitID)
+}
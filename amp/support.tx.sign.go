@@ -0,0 +1,230 @@
+package amp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+)
+
+// The Tag.ContentType of the TxEnvelope.Tags entry that carries a TxMsg signature names the signing kit used.
+// The entry's Tag.UID is the signer's public key and Tag.Text is the signature, both base64 (raw URL) encoded.
+const (
+	TxSignatureContentType     = "amp.tx.signature/ed25519" // signed with a CryptoKit_Signing_ED25519 key
+	TxSignatureNaClContentType = "amp.tx.signature/nacl"    // signed with a CryptoKit_Signing_NaCl key
+)
+
+// GenerateSigningKey returns a new private and public key pair for the given signing CryptoKitID.
+func GenerateSigningKey(kit CryptoKitID) (privKey, pubKey *CryptoKey, err error) {
+	if !isSigningKit(kit) {
+		return nil, nil, ErrCode_UnsupportedOp.Errorf("unsupported signing kit %v", kit)
+	}
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, ErrCode_InternalErr.Wrap(err)
+	}
+	privKey = &CryptoKey{
+		CryptoKitID: kit,
+		KeyBytes:    priv,
+	}
+	pubKey = &CryptoKey{
+		CryptoKitID: kit,
+		KeyBytes:    pub,
+	}
+	return privKey, pubKey, nil
+}
+
+// PublicKey returns the public key of this private signing key.
+func (key *CryptoKey) PublicKey() (*CryptoKey, error) {
+	priv, err := key.signingPrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoKey{
+		CryptoKitID: key.CryptoKitID,
+		KeyBytes:    priv.Public().(ed25519.PublicKey),
+	}, nil
+}
+
+// Sign signs this TxMsg's header, TxOps, and DataStore using the given private key, replacing any existing signature.
+// The signature and signer's public key are carried in TxEnvelope.Tags, so any change to this TxMsg afterwards requires it to be signed again.
+//
+// Both CryptoKit_Signing_ED25519 and CryptoKit_Signing_NaCl keys are supported (NaCl signing is ed25519).
+func (tx *TxMsg) Sign(privKey *CryptoKey) error {
+	priv, err := privKey.signingPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	tx.TxEnvelope.Tags = withoutSignature(tx.TxEnvelope.Tags)
+	tx.OpCount = uint64(len(tx.Ops))
+
+	digest := tx.signingDigest(&tx.TxEnvelope)
+	sig, err := priv.Sign(nil, digest, &ed25519.Options{Hash: crypto.SHA512})
+	if err != nil {
+		return ErrCode_InternalErr.Wrap(err)
+	}
+
+	if tx.TxEnvelope.Tags == nil {
+		tx.TxEnvelope.Tags = &Tags{}
+	}
+	tx.TxEnvelope.Tags.SubTags = append(tx.TxEnvelope.Tags.SubTags, &Tags{
+		ID: &Tag{
+			ContentType: signatureContentType(privKey.CryptoKitID),
+			UID:         base64.RawURLEncoding.EncodeToString(priv.Public().(ed25519.PublicKey)),
+			Text:        base64.RawURLEncoding.EncodeToString(sig),
+		},
+	})
+	return nil
+}
+
+// IsSigned returns true if this TxMsg carries a signature (which may or may not be valid).
+func (tx *TxMsg) IsSigned() bool {
+	sub, _ := tx.signatureTags()
+	return sub != nil
+}
+
+// Signer returns the public key (and so the signing kit) that this TxMsg claims to be signed by.
+// The claim is only meaningful once Verify() succeeds.
+func (tx *TxMsg) Signer() (*CryptoKey, error) {
+	kit, pub, _, err := tx.signature()
+	if err != nil {
+		return nil, err
+	}
+	return &CryptoKey{
+		CryptoKitID: kit,
+		KeyBytes:    pub,
+	}, nil
+}
+
+// Verify checks this TxMsg's signature and returns an ErrCode_AuthFailed error if it is unsigned or fails verification.
+//
+// If pubKey is nil, the signature is checked against the signer embedded in the TxMsg, which proves integrity but not identity --
+// the caller is then expected to check Signer() against who it trusts.
+func (tx *TxMsg) Verify(pubKey *CryptoKey) error {
+	_, signer, sig, err := tx.signature()
+	if err != nil {
+		return err
+	}
+	if pubKey != nil {
+		if !isSigningKit(pubKey.CryptoKitID) {
+			return ErrCode_AuthFailed.Errorf("unsupported signing kit %v", pubKey.CryptoKitID)
+		}
+		if !bytes.Equal(pubKey.KeyBytes, signer) {
+			return ErrTxWrongSigner
+		}
+	}
+
+	env := tx.TxEnvelope
+	env.Tags = withoutSignature(env.Tags)
+	env.OpCount = uint64(len(tx.Ops))
+
+	digest := tx.signingDigest(&env)
+	err = ed25519.VerifyWithOptions(signer, digest, sig, &ed25519.Options{Hash: crypto.SHA512})
+	if err != nil {
+		return ErrTxSignature
+	}
+	return nil
+}
+
+// CommitPolicy is used by a Session to decide whether a client commit (see Request.CommitTx and host.Opts.CommitPolicy) is accepted.
+type CommitPolicy struct {
+	RequireSigned bool         // if set, unsigned commits are refused
+	Signers       []*CryptoKey // if non-empty, a commit must be signed by one of these public keys
+}
+
+// CheckCommit returns nil if the given tx is accepted under this policy, otherwise an ErrCode_AuthFailed error.
+// A signed tx is always verified, even if signing is not required.
+func (policy *CommitPolicy) CheckCommit(tx *TxMsg) error {
+	if !tx.IsSigned() {
+		if policy.RequireSigned || len(policy.Signers) > 0 {
+			return ErrTxUnsigned
+		}
+		return nil
+	}
+	if len(policy.Signers) == 0 {
+		return tx.Verify(nil)
+	}
+	for _, signer := range policy.Signers {
+		if err := tx.Verify(signer); err != ErrTxWrongSigner {
+			return err
+		}
+	}
+	return ErrTxWrongSigner
+}
+
+// signature returns the signing kit, signer public key, and signature carried by this TxMsg.
+func (tx *TxMsg) signature() (CryptoKitID, ed25519.PublicKey, []byte, error) {
+	sub, kit := tx.signatureTags()
+	if sub == nil {
+		return CryptoKit_Nil, nil, nil, ErrTxUnsigned
+	}
+	pub, err := base64.RawURLEncoding.DecodeString(sub.ID.UID)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return CryptoKit_Nil, nil, nil, ErrCode_AuthFailed.Error("malformed tx signer")
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(sub.ID.Text)
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return CryptoKit_Nil, nil, nil, ErrCode_AuthFailed.Error("malformed tx signature")
+	}
+	return kit, pub, sig, nil
+}
+
+// signatureTags returns the TxEnvelope.Tags entry carrying this TxMsg's signature and the signing kit it names, or nil if unsigned.
+func (tx *TxMsg) signatureTags() (*Tags, CryptoKitID) {
+	for _, kit := range [...]CryptoKitID{CryptoKit_Signing_ED25519, CryptoKit_Signing_NaCl} {
+		if sub := tx.TxEnvelope.Tags.FindSubTags(signatureContentType(kit)); sub != nil {
+			return sub, kit
+		}
+	}
+	return nil, CryptoKit_Nil
+}
+
+// signatureContentType returns the Tag.ContentType of a signature made with the given signing kit.
+func signatureContentType(kit CryptoKitID) string {
+	if kit == CryptoKit_Signing_NaCl {
+		return TxSignatureNaClContentType
+	}
+	return TxSignatureContentType
+}
+
+// withoutSignature returns the given Tags excluding any signature entry (see WithoutSubTags).
+func withoutSignature(tags *Tags) *Tags {
+	return tags.WithoutSubTags(TxSignatureContentType).WithoutSubTags(TxSignatureNaClContentType)
+}
+
+// signingDigest returns the SHA-512 of the uncompressed header and body (using the given TxEnvelope) followed by the DataStore.
+// The body is digested with its ops sorted by TxOpID so that a signature holds however Ops is later reordered (e.g. by Load).
+func (tx *TxMsg) signingDigest(env *TxEnvelope) []byte {
+	ops := tx.Ops
+	if !tx.OpsSorted {
+		ops = append([]TxOp(nil), ops...)
+		sortTxOps(ops)
+	}
+	headerAndOps := appendTxBody(make([]byte, Const_TxHeader_Size, 512), env, ops)
+	putTxHeader(headerAndOps, len(tx.DataStore))
+
+	h := sha512.New()
+	h.Write(headerAndOps)
+	h.Write(tx.DataStore)
+	return h.Sum(nil)
+}
+
+func (key *CryptoKey) signingPrivateKey() (ed25519.PrivateKey, error) {
+	if key == nil || !isSigningKit(key.CryptoKitID) {
+		return nil, ErrCode_UnsupportedOp.Error("not a signing key")
+	}
+	switch len(key.KeyBytes) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(key.KeyBytes), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(key.KeyBytes), nil
+	}
+	return nil, ErrCode_BadValue.Error("malformed signing key")
+}
+
+func isSigningKit(kit CryptoKitID) bool {
+	return kit == CryptoKit_Signing_ED25519 || kit == CryptoKit_Signing_NaCl
+}
@@ -2,9 +2,11 @@ package amp
 
 import (
 	"bytes"
+	"encoding/binary"
 	fmt "fmt"
 	io "io"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
@@ -12,7 +14,512 @@ import (
 
 func TestTxSerialize(t *testing.T) {
 	// Test serialization of a simple TxMsg
+	tx := makeTestTx(5500)
+
+	var txBuf []byte
+	tx.MarshalToBuffer(&txBuf)
+
+	r := bufReader{
+		buf: txBuf,
+	}
+	tx2, err := ReadTxMsg(&r)
+	if err != nil {
+		t.Fatalf("ReadTxMsg failed: %v", err)
+	}
+	if tx2.TxEnvelope != tx.TxEnvelope {
+		t.Errorf("ReadTxMsg failed: TxEnvelope mismatch")
+	}
+	if len(tx2.Ops) != len(tx.Ops) {
+		t.Errorf("ReadTxMsg failed: TxEnvelope mismatch")
+	}
+	if !bytes.Equal(tx.DataStore, tx2.DataStore) {
+		t.Errorf("ReadTxMsg failed: DataStore mismatch")
+	}
+	for i, op1 := range tx.Ops {
+		op2 := tx2.Ops[i]
+
+		if op1.OpCode != op2.OpCode || op1 != op2 || op1.DataOfs != op2.DataOfs || op1.DataLen != op2.DataLen {
+			t.Errorf("ReadTxMsg failed: Op mismatch")
+		}
+	}
+}
+
+func TestTxCompression(t *testing.T) {
+	tx := makeTestTx(2000)
+
+	var plain []byte
+	tx.MarshalToBuffer(&plain)
+
+	for _, codec := range []TxCodec{TxCodec_Deflate, TxCodec_Zlib, TxCodec_Gzip} {
+		enc := TxEncoding{
+			Codec:        codec,
+			CompressBody: true,
+		}
+		var scrap []byte
+		var txBuf bytes.Buffer
+		if err := tx.MarshalToWriterWith(enc, &scrap, &txBuf); err != nil {
+			t.Fatal(err)
+		}
+
+		var header TxHeader
+		copy(header[:], txBuf.Bytes())
+		if header.BodyCodec() != codec || header.DataCodec() != codec {
+			t.Fatalf("%v: header codecs not set", codec)
+		}
+		if txBuf.Len() >= len(plain) {
+			t.Fatalf("%v: compressed size %d >= %d", codec, txBuf.Len(), len(plain))
+		}
+
+		tx2, err := ReadTxMsg(bytes.NewReader(txBuf.Bytes()))
+		if err != nil {
+			t.Fatalf("%v: ReadTxMsg failed: %v", codec, err)
+		}
+		if tx2.TxEnvelope != tx.TxEnvelope || !reflect.DeepEqual(tx2.Ops, tx.Ops) || !bytes.Equal(tx2.DataStore, tx.DataStore) {
+			t.Fatalf("%v: round trip mismatch", codec)
+		}
+		tx2.ReleaseRef()
+
+		// a peer not accepting the codec must reject the tx
+		r := TxReader{
+			Codecs: 1 << TxCodec_None,
+		}
+		if _, err = r.ReadTxMsg(bytes.NewReader(txBuf.Bytes())); GetErrCode(err) != ErrCode_UnsupportedOp {
+			t.Fatalf("%v: expected ErrCode_UnsupportedOp, got %v", codec, err)
+		}
+		if enc.Negotiate(r.Codecs).Codec != TxCodec_None {
+			t.Fatalf("%v: Negotiate did not turn off compression", codec)
+		}
+	}
+
+	// nothing is compressed until a Handshake negotiates a codec, so a peer predating TxCodecs can read it
+	var scrap []byte
+	var txBuf bytes.Buffer
+	if err := tx.MarshalToWriter(&scrap, &txBuf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(txBuf.Bytes(), plain) {
+		t.Fatalf("tx was compressed by default")
+	}
+
+	// sections under the threshold are sent as is
+	small := makeTestTx(0)
+	txBuf.Reset()
+	if err := small.MarshalToWriterWith(NegotiatedTxEncoding, &scrap, &txBuf); err != nil {
+		t.Fatal(err)
+	}
+	small.MarshalToBuffer(&plain)
+	if !bytes.Equal(txBuf.Bytes(), plain) {
+		t.Fatalf("small tx was not sent uncompressed")
+	}
+}
+
+func TestTxSign(t *testing.T) {
+	privKey, pubKey, err := GenerateSigningKey(CryptoKit_Signing_ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, otherKey, _ := GenerateSigningKey(CryptoKit_Signing_NaCl)
+
+	tx := makeTestTx(50)
+	if err = tx.Verify(pubKey); err != ErrTxUnsigned {
+		t.Fatalf("expected ErrTxUnsigned, got %v", err)
+	}
+	if err = tx.Sign(privKey); err != nil {
+		t.Fatal(err)
+	}
+
+	// the signature must survive the wire, compressed or not
+	for _, enc := range []TxEncoding{{}, {Codec: TxCodec_Deflate, CompressBody: true}} {
+		var txBuf []byte
+		if err = tx.MarshalToBufferWith(enc, &txBuf); err != nil {
+			t.Fatal(err)
+		}
+		tx2, err := ReadTxMsg(bytes.NewReader(txBuf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = tx2.Verify(pubKey); err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+		if err = tx2.Verify(nil); err != nil {
+			t.Fatalf("Verify failed: %v", err)
+		}
+		if err = tx2.Verify(otherKey); err != ErrTxWrongSigner {
+			t.Fatalf("expected ErrTxWrongSigner, got %v", err)
+		}
+
+		tx2.DataStore[len(tx2.DataStore)-1] ^= 1
+		if err = tx2.Verify(pubKey); GetErrCode(err) != ErrCode_AuthFailed {
+			t.Fatalf("expected ErrCode_AuthFailed for altered DataStore, got %v", err)
+		}
+		tx2.DataStore[len(tx2.DataStore)-1] ^= 1
+		tx2.Ops[3].ItemID[0]++
+		if err = tx2.Verify(pubKey); GetErrCode(err) != ErrCode_AuthFailed {
+			t.Fatalf("expected ErrCode_AuthFailed for altered op, got %v", err)
+		}
+	}
+
+	// reading a signed tx sorts its ops in place, which must not void the signature
+	unsorted := NewTxMsg(true)
+	attrID := tag.ID{0, 0, 5}
+	for _, cellID := range []tag.ID{{0, 0, 9}, {0, 0, 3}, {0, 0, 7}} {
+		if err = unsorted.Upsert(cellID, attrID, tag.ID{}, &Tag{Text: cellID.String()}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = unsorted.Sign(privKey); err != nil {
+		t.Fatal(err)
+	}
+	if ops := unsorted.AttrItems(tag.ID{0, 0, 3}, attrID); !ops.Next() {
+		t.Fatal("AttrItems: expected an item")
+	}
+	if err = unsorted.Verify(pubKey); err != nil {
+		t.Fatalf("Verify after Load failed: %v", err)
+	}
+
+	policy := CommitPolicy{
+		RequireSigned: true,
+	}
+	if err = policy.CheckCommit(makeTestTx(1)); GetErrCode(err) != ErrCode_AuthFailed {
+		t.Fatalf("expected unsigned commit to be refused, got %v", err)
+	}
+	if err = policy.CheckCommit(tx); err != nil {
+		t.Fatal(err)
+	}
+	policy.Signers = []*CryptoKey{otherKey}
+	if err = policy.CheckCommit(tx); err != ErrTxWrongSigner {
+		t.Fatalf("expected ErrTxWrongSigner, got %v", err)
+	}
+	policy.Signers = append(policy.Signers, pubKey)
+	if err = policy.CheckCommit(tx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTxSeal(t *testing.T) {
+	key, err := GenerateSymmetricKey(CryptoKit_AES_GCM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongKey, _ := GenerateSymmetricKey(CryptoKit_AES_GCM)
+
+	for _, sealOps := range []bool{false, true} {
+		tx := makeTestTx(20)
+		orig := *tx
+		orig.Ops = append([]TxOp{}, tx.Ops...)
+		orig.DataStore = append([]byte{}, tx.DataStore...)
+
+		if err = tx.Seal(key, sealOps); err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(tx.DataStore, []byte("batwing ave")) {
+			t.Fatal("DataStore not encrypted")
+		}
+		if sealOps != (len(tx.Ops) == 0) {
+			t.Fatalf("sealOps=%v: unexpected op count %d", sealOps, len(tx.Ops))
+		}
+
+		var txBuf []byte
+		tx.MarshalToBuffer(&txBuf)
+		tx2, err := ReadTxMsg(bytes.NewReader(txBuf))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx2.ContextID() != orig.ContextID() || !tx2.IsSealed() {
+			t.Fatal("sealed tx envelope not routable")
+		}
+		if !sealOps {
+			var login Login
+			if err = tx2.UnmarshalOpValue(0, &login); err != ErrTxSealed {
+				t.Fatalf("expected ErrTxSealed, got %v", err)
+			}
+		}
+		if err = tx2.Open(wrongKey); GetErrCode(err) != ErrCode_DecryptFailed {
+			t.Fatalf("expected ErrCode_DecryptFailed, got %v", err)
+		}
+		if err = tx2.Open(key); err != nil {
+			t.Fatal(err)
+		}
+		if tx2.IsSealed() || !reflect.DeepEqual(tx2.Ops, orig.Ops) || !bytes.Equal(tx2.DataStore, orig.DataStore) {
+			t.Fatalf("sealOps=%v: opened tx does not match original", sealOps)
+		}
+	}
+
+	// tampering with the envelope's nonce material, the seal mode, or the readable ops must fail
+	tamper := map[string]func(tx *TxMsg){
+		"GenesisID": func(tx *TxMsg) { tx.GenesisID_2++ },
+		"mode":      func(tx *TxMsg) { tx.TxEnvelope.Tags.FindSubTags(TxSealedContentType).ID.Text = TxSealed_OpsAndData },
+		"op":        func(tx *TxMsg) { tx.Ops[0].DataOfs++ },
+		"op ID":     func(tx *TxMsg) { tx.Ops[1].ItemID[0]++ },
+	}
+	for what, alter := range tamper {
+		tx := makeTestTx(1)
+		if err = tx.Seal(key, false); err != nil {
+			t.Fatal(err)
+		}
+		alter(tx)
+		if err = tx.Open(key); err != ErrTxDecrypt {
+			t.Fatalf("%s: expected ErrTxDecrypt, got %v", what, err)
+		}
+	}
+}
+
+func TestTxDump(t *testing.T) {
+	reg := NewRegistry()
+	RegisterBuiltinTypes(reg)
 
+	tx := NewTxMsg(true)
+	tx.Status = OpStatus_Synced
+	tx.SetContextID(tag.Now())
+	tx.From = &Tag{
+		UID: "cmdr5",
+	}
+	cellID := tag.Now()
+	tx.Upsert(cellID, AttrSpec.With("LaunchURL").ID, tag.ID{0, 0, 1}, &LaunchURL{
+		URL: "amp://planet/batcave",
+	})
+	tx.Upsert(cellID, AttrSpec.With("Login").ID, tag.ID{}, &Login{
+		HostAddress: "batwing ave",
+	})
+	tx.MarshalOpWithBuf(&TxOp{
+		OpCode: TxOpCode_UpsertElement,
+		TxOpID: TxOpID{
+			CellID: cellID,
+			AttrID: tag.ID{7, 3, 7},
+			EditID: tag.Genesis(tx.GenesisID()),
+		},
+	}, []byte("unregistered"))
+
+	var dump bytes.Buffer
+	if err := tx.Dump(reg, &dump); err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{`"Attr": "amp.attr.LaunchURL"`, `"amp://planet/batcave"`, `"OpStatus_Synced"`, cellID.Base32()} {
+		if !strings.Contains(dump.String(), expect) {
+			t.Fatalf("dump missing %s:\n%s", expect, dump.String())
+		}
+	}
+
+	tx2, err := ReadTxDump(reg, bytes.NewReader(dump.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tx2.TxEnvelope.Equal(&tx.TxEnvelope) || !reflect.DeepEqual(tx2.Ops, tx.Ops) || !bytes.Equal(tx2.DataStore, tx.DataStore) {
+		t.Fatalf("ReadTxDump round trip mismatch")
+	}
+
+	// hand-authored tx using a tag.Spec in place of an AttrID
+	tx3, err := ReadTxDump(reg, strings.NewReader(`{
+		"Ops": [{
+			"OpCode": "TxOpCode_UpsertElement",
+			"CellID": "`+cellID.Base32()+`",
+			"Attr":   "amp.attr.LaunchURL",
+			"Value":  { "URL": "amp://hand/authored" }
+		}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var launch LaunchURL
+	if err = tx3.LoadItem(AttrSpec.With("LaunchURL").ID, tag.ID{}, &launch); err != nil || launch.URL != "amp://hand/authored" {
+		t.Fatalf("hand-authored tx mismatch: %v %v", err, launch.URL)
+	}
+	if _, err = ReadTxDump(reg, strings.NewReader(`{"Ops": [{"OpCode": "TxOpCode_Nope"}]}`)); GetErrCode(err) != ErrCode_BadValue {
+		t.Fatalf("expected ErrCode_BadValue, got %v", err)
+	}
+}
+
+func TestTxQuery(t *testing.T) {
+	reg := NewRegistry()
+	RegisterBuiltinTypes(reg)
+
+	urlAttr := AttrSpec.With("LaunchURL").ID
+	loginAttr := AttrSpec.With("Login").ID
+	cellA := tag.ID{0, 0, 100}
+	cellB := tag.ID{0, 0, 200}
+
+	tx := NewTxMsg(true)
+	for _, cellID := range []tag.ID{cellB, cellA, tag.ID{0, 0, 300}} {
+		for item := uint64(5); item > 0; item-- {
+			tx.Upsert(cellID, urlAttr, tag.ID{0, 0, item}, &LaunchURL{
+				URL: fmt.Sprintf("amp://%v/%d", cellID[2], item),
+			})
+		}
+		tx.Upsert(cellID, loginAttr, tag.ID{}, &Login{
+			HostAddress: fmt.Sprint(cellID[2]),
+		})
+	}
+
+	count := 0
+	for ops := tx.CellOps(cellA); ops.Next(); count++ {
+		if ops.Op().CellID != cellA {
+			t.Fatalf("CellOps returned op of cell %v", ops.Op().CellID)
+		}
+	}
+	if count != 6 {
+		t.Fatalf("CellOps: expected 6 ops, got %d", count)
+	}
+
+	ops := tx.AttrItems(cellB, urlAttr)
+	if ops.Remaining() != 5 {
+		t.Fatalf("AttrItems: expected 5 ops, got %d", ops.Remaining())
+	}
+	for item := uint64(1); ops.Next(); item++ {
+		var url LaunchURL
+		if err := ops.Load(&url); err != nil {
+			t.Fatal(err)
+		}
+		if ops.Op().ItemID[2] != item || url.URL != fmt.Sprintf("amp://200/%d", item) {
+			t.Fatalf("AttrItems: unexpected item %v: %q", ops.Op().ItemID, url.URL)
+		}
+	}
+	if ops.Next() || ops.Remaining() != 0 {
+		t.Fatal("AttrItems: expected range to be exhausted")
+	}
+
+	ops = tx.ItemRange(cellA, urlAttr, tag.ID{0, 0, 2}, tag.ID{0, 0, 4})
+	var urls []string
+	for ops.Next() {
+		val, err := ops.Value(reg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		urls = append(urls, val.(*LaunchURL).URL)
+	}
+	if !reflect.DeepEqual(urls, []string{"amp://100/2", "amp://100/3"}) {
+		t.Fatalf("ItemRange: unexpected values %v", urls)
+	}
+
+	ops = tx.AttrItems(cellA, loginAttr)
+	if !ops.Next() {
+		t.Fatal("AttrItems: missing Login")
+	}
+	if val, err := ops.Value(reg); err != nil || val.(*Login).HostAddress != "100" {
+		t.Fatalf("AttrItems: unexpected Login %v %v", val, err)
+	}
+
+	if ops = tx.AttrItems(tag.ID{0, 0, 150}, urlAttr); ops.Next() {
+		t.Fatal("AttrItems: expected empty range")
+	}
+	if ops = tx.ItemRange(cellA, urlAttr, tag.ID{0, 0, 4}, tag.ID{0, 0, 2}); ops.Next() {
+		t.Fatal("ItemRange: expected empty range")
+	}
+
+	// ops marshalled after a query are found by the next query
+	tx.Upsert(cellA, urlAttr, tag.ID{0, 0, 9}, &LaunchURL{URL: "amp://100/9"})
+	tx.MarshalOpWithBuf(&TxOp{TxOpID: TxOpID{CellID: cellA, AttrID: urlAttr, ItemID: tag.ID{0, 0, 0}}}, nil)
+	if ops = tx.AttrItems(cellA, urlAttr); ops.Remaining() != 7 {
+		t.Fatalf("AttrItems: expected 7 ops after appending, got %d", ops.Remaining())
+	}
+	if ops.Next(); ops.Op().ItemID != (tag.ID{0, 0, 0}) {
+		t.Fatalf("AttrItems: unexpected first item %v", ops.Op().ItemID)
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	local := NewHandshake()
+	peer := &Handshake{
+		MinHeaderVersion: uint32(MinTxHeaderVersion) - 2,
+		MaxHeaderVersion: uint32(MaxTxHeaderVersion) + 5,
+		Codecs:           []TxCodec{TxCodec_Gzip, TxCodec(31)},
+		SigningKits:      []CryptoKitID{CryptoKit_Signing_NaCl},
+	}
+	proto, err := local.Negotiate(peer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proto.HeaderVersion != MaxTxHeaderVersion {
+		t.Errorf("expected TxHeader version %#x, got %#x", MaxTxHeaderVersion, proto.HeaderVersion)
+	}
+	if proto.Codecs != TxCodecs(1<<TxCodec_None|1<<TxCodec_Gzip) {
+		t.Errorf("unexpected codecs %b", proto.Codecs)
+	}
+	if !proto.HasSigningKit(CryptoKit_Signing_NaCl) || proto.HasSigningKit(CryptoKit_Signing_ED25519) {
+		t.Errorf("unexpected signing kits %v", proto.SigningKits)
+	}
+	if enc := proto.TxEncoding(DefaultTxEncoding); enc.Codec != TxCodec_None || enc.HeaderVersion != MaxTxHeaderVersion {
+		t.Errorf("unexpected TxEncoding %+v", enc)
+	}
+
+	peer.MinHeaderVersion = uint32(MaxTxHeaderVersion) + 1
+	if _, err = local.Negotiate(peer); GetErrCode(err) != ErrCode_UnsupportedOp {
+		t.Fatalf("expected ErrCode_UnsupportedOp, got %v", err)
+	}
+
+	// round trip as a MetaNodeID attr
+	tx, err := MarshalAttr(MetaNodeID, HandshakeAttr, local)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// until a version is negotiated, the lowest is written so that a peer supporting only newer versions can read it
+	var scrap []byte
+	var hsBuf bytes.Buffer
+	if err = tx.MarshalToWriter(&scrap, &hsBuf); err != nil {
+		t.Fatal(err)
+	}
+	if vers := hsBuf.Bytes()[3]; vers != MinTxHeaderVersion {
+		t.Fatalf("expected Handshake written at TxHeader version %#x, got %#x", MinTxHeaderVersion, vers)
+	}
+
+	// a signed tx is only sent once the signing kit it was signed with is negotiated
+	for _, kit := range []CryptoKitID{CryptoKit_Signing_NaCl, CryptoKit_Signing_ED25519} {
+		signKey, signPub, err := GenerateSigningKey(kit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signed := makeTestTx(1)
+		if err = signed.Sign(signKey); err != nil {
+			t.Fatal(err)
+		}
+		if signer, err := signed.Signer(); err != nil || signer.CryptoKitID != kit {
+			t.Fatalf("expected signer kit %v, got %v %v", kit, signer, err)
+		}
+		if err = signed.Verify(signPub); err != nil {
+			t.Fatal(err)
+		}
+		err = proto.CheckSigned(signed)
+		if kit == CryptoKit_Signing_NaCl && err != nil {
+			t.Fatal(err)
+		}
+		if kit == CryptoKit_Signing_ED25519 && (GetErrCode(err) != ErrCode_UnsupportedOp || !strings.Contains(err.Error(), kit.String())) {
+			t.Fatalf("expected ErrCode_UnsupportedOp naming %v, got %v", kit, err)
+		}
+		if err = (&Protocol{}).CheckSigned(signed); GetErrCode(err) != ErrCode_UnsupportedOp {
+			t.Fatalf("expected ErrCode_UnsupportedOp, got %v", err)
+		}
+	}
+	if err = (&Protocol{}).CheckSigned(makeTestTx(1)); err != nil {
+		t.Fatal(err)
+	}
+	hs, err := ReadHandshake(tx)
+	if err != nil || !reflect.DeepEqual(hs, local) {
+		t.Fatalf("ReadHandshake mismatch: %v %v", hs, err)
+	}
+	if _, err = ReadHandshake(NewTxMsg(true)); GetErrCode(err) != ErrCode_BadRequest {
+		t.Fatalf("expected ErrCode_BadRequest, got %v", err)
+	}
+
+	// a TxHeader version beyond what this package reads is rejected, as is writing one
+	var buf []byte
+	if err = tx.MarshalToBufferWith(TxEncoding{HeaderVersion: MaxTxHeaderVersion + 1}, &buf); GetErrCode(err) != ErrCode_UnsupportedOp {
+		t.Fatalf("expected ErrCode_UnsupportedOp, got %v", err)
+	}
+	if err = tx.MarshalToBufferWith(proto.TxEncoding(DefaultTxEncoding), &buf); err != nil {
+		t.Fatal(err)
+	}
+	buf[3]++
+	if _, err = ReadTxMsg(bytes.NewReader(buf)); GetErrCode(err) != ErrCode_UnsupportedOp {
+		t.Fatalf("expected ErrCode_UnsupportedOp, got %v", err)
+	}
+	buf[3] -= 2
+	if _, err = ReadTxMsg(bytes.NewReader(buf)); err != ErrTxHeader {
+		t.Fatalf("expected ErrTxHeader, got %v", err)
+	}
+}
+
+// makeTestTx returns a TxMsg exercising repeated and changing op fields, followed by numItems item ops.
+func makeTestTx(numItems int) *TxMsg {
 	tx := NewTxMsg(true)
 	tx.Status = OpStatus_Syncing
 	tx.ContextID_0 = 888854513
@@ -51,7 +558,7 @@ func TestTxSerialize(t *testing.T) {
 			HostAddress: "http://localhost:8080",
 		})
 
-		for i := 0; i < 5500; i++ {
+		for i := 0; i < numItems; i++ {
 			op.ItemID[0] = uint64(i)
 			if i%5 == 0 {
 				op.EditID[1] += 37
@@ -66,33 +573,143 @@ func TestTxSerialize(t *testing.T) {
 		op.OpCode = TxOpCode_DeleteElement
 		tx.MarshalOpWithBuf(&op, nil)
 	}
+	return tx
+}
 
-	var txBuf []byte
-	tx.MarshalToBuffer(&txBuf)
+func TestLoginChallenge(t *testing.T) {
+	deviceKey, devicePub, err := GenerateSigningKey(CryptoKit_Signing_ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _, err := GenerateSigningKey(CryptoKit_Signing_ED25519)
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := &Login{
+		UserID:     &Tag{Text: "user"},
+		DeviceID:   &Tag{Text: "device"},
+		Checkpoint: &LoginCheckpoint{AccessToken: "token"},
+	}
+	auth := NewEd25519Authenticator(func(login *Login) (*CryptoKey, error) {
+		if login.DeviceID.Text != "device" {
+			return nil, ErrCode_AuthFailed.Error("unknown device")
+		}
+		return devicePub, nil
+	})
 
-	r := bufReader{
-		buf: txBuf,
+	challenge, err := auth.NewChallenge(login)
+	if err != nil || len(challenge.Hash) != LoginChallengeSz {
+		t.Fatalf("NewChallenge: %v", err)
 	}
-	tx2, err := ReadTxMsg(&r)
+	response, err := challenge.Sign(deviceKey)
 	if err != nil {
-		t.Errorf("ReadTxMsg failed: %v", err)
+		t.Fatal(err)
 	}
-	if tx2.TxEnvelope != tx.TxEnvelope {
-		t.Errorf("ReadTxMsg failed: TxEnvelope mismatch")
+	verified, err := auth.CheckResponse(login, challenge, response)
+	if err != nil {
+		t.Fatalf("CheckResponse: %v", err)
 	}
-	if len(tx2.Ops) != len(tx.Ops) {
-		t.Errorf("ReadTxMsg failed: TxEnvelope mismatch")
+	if verified.UserID.Text != "user" || verified.Checkpoint != nil {
+		t.Fatalf("unexpected verified login %v", verified)
 	}
-	if !bytes.Equal(tx.DataStore, tx2.DataStore) {
-		t.Errorf("ReadTxMsg failed: DataStore mismatch")
+
+	// a response signed by another key, to another challenge, or from an unknown device fails
+	forged, _ := challenge.Sign(otherKey)
+	if _, err = auth.CheckResponse(login, challenge, forged); GetErrCode(err) != ErrCode_LoginFailed {
+		t.Fatalf("expected ErrCode_LoginFailed, got %v", err)
 	}
-	for i, op1 := range tx.Ops {
-		op2 := tx2.Ops[i]
+	replayed, _ := NewLoginChallenge()
+	if _, err = auth.CheckResponse(login, replayed, response); GetErrCode(err) != ErrCode_LoginFailed {
+		t.Fatalf("expected ErrCode_LoginFailed, got %v", err)
+	}
+	stranger := &Login{DeviceID: &Tag{Text: "stranger"}}
+	if _, err = auth.CheckResponse(stranger, challenge, response); GetErrCode(err) != ErrCode_LoginFailed {
+		t.Fatalf("expected ErrCode_LoginFailed, got %v", err)
+	}
+}
 
-		if op1.OpCode != op2.OpCode || op1 != op2 || op1.DataOfs != op2.DataOfs || op1.DataLen != op2.DataLen {
-			t.Errorf("ReadTxMsg failed: Op mismatch")
+func TestTxReaderHostile(t *testing.T) {
+	var txBuf []byte
+	makeTestTx(10).MarshalToBuffer(&txBuf)
+
+	// every truncation of a valid tx must fail cleanly
+	for n := 0; n < len(txBuf); n += 7 {
+		if _, err := ReadTxMsg(bytes.NewReader(txBuf[:n])); err == nil {
+			t.Fatalf("truncated tx (%d of %d bytes) did not fail", n, len(txBuf))
 		}
 	}
+
+	// a header claiming more than the max allowed must fail before reading the body
+	huge := append([]byte{}, txBuf[:Const_TxHeader_Size]...)
+	binary.LittleEndian.PutUint32(huge[8:12], 0xFFFFFFF0)
+	r := TxReader{
+		MaxTxSz: 1 << 20,
+	}
+	if _, err := r.ReadTxMsg(bytes.NewReader(huge)); err != ErrTxTooLarge {
+		t.Fatalf("expected ErrTxTooLarge, got %v", err)
+	}
+
+	// a body length smaller than the header itself
+	short := append([]byte{}, txBuf...)
+	binary.LittleEndian.PutUint32(short[4:8], 3)
+	if _, err := ReadTxMsg(bytes.NewReader(short)); GetErrCode(err) != ErrCode_MalformedTx {
+		t.Fatalf("expected ErrCode_MalformedTx, got %v", err)
+	}
+
+	// an op referencing data beyond the DataStore
+	tx := makeTestTx(0)
+	tx.Ops[0].DataLen += uint64(len(tx.DataStore))
+	tx.MarshalToBuffer(&txBuf)
+	if _, err := ReadTxMsg(bytes.NewReader(txBuf)); err != ErrTxBounds {
+		t.Fatalf("expected ErrTxBounds, got %v", err)
+	}
+}
+
+func FuzzReadTxMsg(f *testing.F) {
+	for _, numItems := range []int{0, 1, 3} {
+		var txBuf []byte
+		makeTestTx(numItems).MarshalToBuffer(&txBuf)
+		f.Add(txBuf)
+		f.Add(txBuf[:len(txBuf)/2])
+
+		makeTestTx(numItems).MarshalToBufferWith(TxEncoding{
+			Codec:        TxCodec_Deflate,
+			CompressBody: true,
+		}, &txBuf)
+		f.Add(txBuf)
+	}
+
+	reader := TxReader{
+		MaxTxSz: 1 << 20,
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tx, err := reader.ReadTxMsg(bytes.NewReader(data))
+		if err != nil {
+			if code := GetErrCode(err); code != ErrCode_MalformedTx && code != ErrCode_UnsupportedOp && err != io.EOF && err != io.ErrUnexpectedEOF {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			return
+		}
+		for i := range tx.Ops {
+			tx.UnmarshalOpValue(i, &Tag{})
+		}
+
+		// whatever was accepted must survive a round trip
+		var txBuf []byte
+		tx.MarshalToBuffer(&txBuf)
+		tx2, err := reader.ReadTxMsg(bytes.NewReader(txBuf))
+		if err != nil {
+			t.Fatalf("re-read failed: %v", err)
+		}
+		if !tx.TxEnvelope.Equal(&tx2.TxEnvelope) || len(tx.Ops) != len(tx2.Ops) || !bytes.Equal(tx.DataStore, tx2.DataStore) {
+			t.Fatalf("round trip mismatch")
+		}
+		for i := range tx.Ops {
+			if tx.Ops[i] != tx2.Ops[i] {
+				t.Fatalf("round trip op mismatch")
+			}
+		}
+	})
 }
 
 type bufReader struct {
@@ -141,3 +758,81 @@ func TestRegistry(t *testing.T) {
 		t.Fatalf("MakeValue returned wrong type: %v", reflect.TypeOf(elem))
 	}
 }
+
+// testPinner is a Pinner that serves nothing, used to check routing.
+type testPinner struct{}
+
+func (testPinner) ServeRequest(req Requester) (Pin, error) {
+	return nil, ErrCode_Unimplemented.Error("testPinner")
+}
+
+func TestURLRouter(t *testing.T) {
+	reg := NewRegistry()
+	app := &App{
+		AppSpec: AppSpec.With("test.files"),
+	}
+	if err := reg.RegisterApp(app); err != nil {
+		t.Fatal(err)
+	}
+
+	router := NewURLRouter()
+	web := testPinner{}
+	if err := router.Handle(UrlScheme_Http, web); err != nil {
+		t.Fatal(err)
+	}
+	if err := router.Handle(UrlScheme_Amp, web); GetErrCode(err) != ErrCode_BadValue {
+		t.Fatalf("expected ErrCode_BadValue, got %v", err)
+	}
+
+	route := func(target *Tag) (Route, error) {
+		return router.Route(reg, &Request{
+			PinRequest: PinRequest{
+				PinTarget: target,
+			},
+		})
+	}
+	tests := []struct {
+		url    string
+		scheme UrlScheme
+		app    *App
+		code   ErrCode
+	}{
+		{"amp://files/open/docs?x=1", UrlScheme_Amp, app, ErrCode_NoErr},
+		{"AMP://files", UrlScheme_Amp, app, ErrCode_NoErr},
+		{"amp:files/open", UrlScheme_Amp, app, ErrCode_NoErr},
+		{"files/open", UrlScheme_Amp, app, ErrCode_NoErr},
+		{"amp://nope/open", UrlScheme_Amp, nil, ErrCode_AppNotFound},
+		{"amp:", UrlScheme_Amp, nil, ErrCode_InvalidURI},
+		{"https://example.com/a", UrlScheme_Http, nil, ErrCode_NoErr},
+		{"data:text/plain;base64,SGk=", UrlScheme_Data, nil, ErrCode_InvalidURI},
+		{"file:///tmp/x", UrlScheme_File, nil, ErrCode_InvalidURI},
+		{"git://example.com/repo", UrlScheme_Git, nil, ErrCode_InvalidURI},
+		{"gopher://example.com", UrlScheme_Unrecognized, nil, ErrCode_InvalidURI},
+		{"http://[::1", UrlScheme_Nil, nil, ErrCode_InvalidURI},
+	}
+	for _, test := range tests {
+		got, err := route(&Tag{URL: test.url})
+		if code := GetErrCode(err); code != test.code {
+			t.Fatalf("%q: expected %v, got %v", test.url, test.code, err)
+		}
+		if got.Scheme != test.scheme || got.App != test.app {
+			t.Fatalf("%q: unexpected route %v %v", test.url, got.Scheme, got.App)
+		}
+		if test.scheme == UrlScheme_Http && got.Handler != web {
+			t.Fatalf("%q: expected the http handler", test.url)
+		}
+	}
+
+	// a target given by ID names its app
+	byID := &Tag{}
+	byID.SetID(app.AppSpec.ID)
+	if got, err := route(byID); err != nil || got.App != app {
+		t.Fatalf("expected app by ID, got %v %v", got.App, err)
+	}
+
+	// a catch-all handler serves schemes having no handler of their own
+	router.Handle(UrlScheme_Unrecognized, web)
+	if got, err := route(&Tag{URL: "gopher://example.com"}); err != nil || got.Handler != web {
+		t.Fatalf("expected catch-all handler, got %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+// Package transport offers stock amp.Transport implementations and the amp.HostService listeners that serve them.
+package transport
+
+import (
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+)
+
+const (
+	// DefaultBufSz is the default read and write buffer size used by a stream transport.
+	DefaultBufSz = 32 * 1024
+
+	// CloseFlushTimeout is how long closing a stream (or batching) transport waits to flush pending writes to a peer that isn't reading.
+	CloseFlushTimeout = time.Second
+
+	// Defaults used by NewBatchingTransport
+	DefaultMaxBatchSz = 64 * 1024
+	DefaultMaxDelay   = 5 * time.Millisecond
+	DefaultIdleDelay  = 500 * time.Microsecond
+	DefaultMaxQueued  = 1024
+)
+
+// BatchSender is implemented by transports that can send several TxMsgs in a single write.
+// As with SendTx, SendTxBatch takes ownership of each TxMsg.  It returns how many of the leading TxMsgs were written.
+type BatchSender interface {
+	SendTxBatch(txs []*amp.TxMsg) (int, error)
+}
+
+// BatchOpts configures a BatchingTransport.
+type BatchOpts struct {
+	MaxBatchSz int           // a batch is sent once its estimated byte size reaches this; if <= 0, DefaultMaxBatchSz is used
+	MaxDelay   time.Duration // max time a TxMsg waits to be sent; if <= 0, DefaultMaxDelay is used
+	IdleDelay  time.Duration // a batch is sent once no TxMsg has been queued for this long; if <= 0, DefaultIdleDelay is used
+	MaxQueued  int           // SendTx blocks while this many TxMsgs are queued; if <= 0, DefaultMaxQueued is used
+}
+
+// BatchStats reports how much batching a BatchingTransport has done.
+type BatchStats struct {
+	TxMsgs       uint64 // TxMsgs written to the wrapped transport
+	Batches      uint64 // writes made, each writing one or more TxMsgs
+	FullFlushes  uint64 // batches sent because MaxBatchSz was reached
+	IdleFlushes  uint64 // batches sent because no TxMsg was queued within IdleDelay
+	DelayFlushes uint64 // batches sent because MaxDelay elapsed
+}
+
+// StreamOpts configures an amp.Transport that wraps a byte stream connection (e.g. tcp or unix socket).
+type StreamOpts struct {
+	Label      string // describes the transport for logging; if empty, the connection's remote address is used
+	ReadBufSz  int    // size of the buffered reader; if <= 0, DefaultBufSz is used
+	WriteBufSz int    // size of the buffered writer; if <= 0, DefaultBufSz is used
+	MaxTxSz    int    // max byte size of an incoming TxMsg; if <= 0, amp.DefaultMaxTxSz is used
+
+	Encoding *amp.TxEncoding // outbound TxMsg encoding; if nil, amp.DefaultTxEncoding is used
+	Codecs   amp.TxCodecs    // TxCodecs accepted from the peer; if 0, amp.SupportedTxCodecs is used
+}
+
+// ListenerOpts configures a Listener.
+type ListenerOpts struct {
+	Network string     // "tcp", "tcp4", "tcp6", or "unix"; if empty, "tcp" is used
+	Address string     // address to listen on; if empty and the network is tcp, amp.Const_DefaultServicePort is used
+	TLS     TLSOpts    // if enabled, accepted connections are wrapped in TLS
+	Stream  StreamOpts // applied to each accepted connection
+}
+
+// WebSocketOpts configures a WebSocketService.
+type WebSocketOpts struct {
+	Address      string // address to listen on (e.g. ":5193"); if empty, no listener is started and Handler() is mounted by the caller
+	Path         string // URL path that accepts upgrade requests; if empty, DefaultWebSocketPath is used
+	TLS          TLSOpts
+	MaxMessageSz int // max byte size of an incoming message; if <= 0, DefaultMaxMessageSz is used
+
+	Encoding *amp.TxEncoding // outbound TxMsg encoding; if nil, amp.DefaultTxEncoding is used
+	Codecs   amp.TxCodecs    // TxCodecs accepted from the peer; if 0, amp.SupportedTxCodecs is used
+}
+
+// TLSOpts specifies how TLS is configured for a listening HostService.
+type TLSOpts struct {
+	CertFile   string // PEM certificate file; used together with KeyFile
+	KeyFile    string // PEM private key file
+	SelfSigned bool   // if set and no cert file is given, a self-signed certificate is generated
+}
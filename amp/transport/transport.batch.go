@@ -0,0 +1,260 @@
+package transport
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+)
+
+// txOpSzEstimate is the estimated marshalled size of a TxOp, used to estimate a batch's size without marshalling it.
+const txOpSzEstimate = 24
+
+// BatchingTransport wraps an amp.Transport, queuing outgoing TxMsgs so that adjacent ones are coalesced into a single write.
+//
+// A batch is sent once its estimated size reaches MaxBatchSz, once no TxMsg is queued for IdleDelay, or once its oldest TxMsg has waited MaxDelay.
+// If the wrapped transport implements BatchSender (as stream and WebSocket transports do), each batch is sent in a single write;
+// otherwise each TxMsg is passed to SendTx in order.
+//
+// Since SendTx returns once a TxMsg is queued, a send error is returned by the next call to SendTx (or Close).
+type BatchingTransport struct {
+	inner   amp.Transport
+	opts    BatchOpts
+	queue   chan *amp.TxMsg
+	closing chan struct{}         // closed once Close is called, releasing any SendTx blocked on a full queue
+	done    chan struct{}         // closed once the send loop exits
+	mu      sync.RWMutex          // held exclusively when setting closed
+	closed  bool                  // set once Close is called
+	senders sync.WaitGroup        // SendTx calls that may still put to queue; queue is closed once they return
+	err     atomic.Pointer[error] // first send error
+
+	txMsgs       atomic.Uint64
+	batches      atomic.Uint64
+	fullFlushes  atomic.Uint64
+	idleFlushes  atomic.Uint64
+	delayFlushes atomic.Uint64
+}
+
+// NewBatchingTransport returns a BatchingTransport that sends through the given transport, which it then owns.
+func NewBatchingTransport(inner amp.Transport, opts BatchOpts) *BatchingTransport {
+	if opts.MaxBatchSz <= 0 {
+		opts.MaxBatchSz = DefaultMaxBatchSz
+	}
+	if opts.MaxDelay <= 0 {
+		opts.MaxDelay = DefaultMaxDelay
+	}
+	if opts.IdleDelay <= 0 {
+		opts.IdleDelay = DefaultIdleDelay
+	}
+	if opts.MaxQueued <= 0 {
+		opts.MaxQueued = DefaultMaxQueued
+	}
+
+	bt := &BatchingTransport{
+		inner:   inner,
+		opts:    opts,
+		queue:   make(chan *amp.TxMsg, opts.MaxQueued),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go bt.sendLoop()
+	return bt
+}
+
+func (bt *BatchingTransport) Label() string {
+	return bt.inner.Label()
+}
+
+// SendTx queues the given TxMsg, taking ownership of it.
+// If the queue is full, SendTx blocks until there is room or until Close is called.
+func (bt *BatchingTransport) SendTx(tx *amp.TxMsg) error {
+	bt.mu.RLock()
+	closed := bt.closed
+	if !closed {
+		bt.senders.Add(1)
+	}
+	bt.mu.RUnlock()
+
+	if closed {
+		tx.ReleaseRef()
+		return amp.ErrStreamClosed
+	}
+	defer bt.senders.Done()
+
+	if err := bt.sendErr(); err != nil {
+		tx.ReleaseRef()
+		return err
+	}
+	select {
+	case bt.queue <- tx:
+		return nil
+	case <-bt.closing:
+		tx.ReleaseRef()
+		return amp.ErrStreamClosed
+	}
+}
+
+func (bt *BatchingTransport) RecvTx() (*amp.TxMsg, error) {
+	return bt.inner.RecvTx()
+}
+
+// Close sends any queued TxMsgs and then closes the wrapped transport.
+// If the queued TxMsgs are not sent within CloseFlushTimeout (e.g. the peer has stalled), the wrapped transport is closed regardless.
+func (bt *BatchingTransport) Close() error {
+	bt.mu.Lock()
+	first := !bt.closed
+	bt.closed = true
+	bt.mu.Unlock()
+
+	if first {
+		close(bt.closing)
+		bt.senders.Wait()
+		close(bt.queue)
+	}
+
+	timer := time.NewTimer(CloseFlushTimeout)
+	select {
+	case <-bt.done:
+	case <-timer.C:
+	}
+	timer.Stop()
+
+	err := bt.inner.Close()
+	<-bt.done
+	if sendErr := bt.sendErr(); sendErr != nil {
+		err = sendErr
+	}
+	return err
+}
+
+// SetTxEncoding implements amp.TxEncoder if the wrapped transport does.
+func (bt *BatchingTransport) SetTxEncoding(enc amp.TxEncoding) {
+	if encoder, ok := bt.inner.(amp.TxEncoder); ok {
+		encoder.SetTxEncoding(enc)
+	}
+}
+
+// Stats returns counters reflecting how much batching has been done.
+func (bt *BatchingTransport) Stats() BatchStats {
+	return BatchStats{
+		TxMsgs:       bt.txMsgs.Load(),
+		Batches:      bt.batches.Load(),
+		FullFlushes:  bt.fullFlushes.Load(),
+		IdleFlushes:  bt.idleFlushes.Load(),
+		DelayFlushes: bt.delayFlushes.Load(),
+	}
+}
+
+func (bt *BatchingTransport) sendErr() error {
+	if err := bt.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// sendLoop gathers queued TxMsgs into batches and sends them until the queue is closed.
+func (bt *BatchingTransport) sendLoop() {
+	defer close(bt.done)
+
+	var (
+		batch   []*amp.TxMsg
+		batchSz int
+	)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+
+	for {
+		tx, ok := <-bt.queue
+		if !ok {
+			return
+		}
+		batch = append(batch[:0], tx)
+		batchSz = txSzEstimate(tx)
+		deadline := time.Now().Add(bt.opts.MaxDelay)
+
+		for open := true; len(batch) > 0; {
+			if batchSz >= bt.opts.MaxBatchSz {
+				bt.fullFlushes.Add(1)
+				break
+			}
+			if !open {
+				break
+			}
+			wait := min(bt.opts.IdleDelay, time.Until(deadline))
+			if wait <= 0 {
+				bt.delayFlushes.Add(1)
+				break
+			}
+			timer.Reset(wait)
+			select {
+			case tx, open = <-bt.queue:
+				if open {
+					batch = append(batch, tx)
+					batchSz += txSzEstimate(tx)
+				}
+			case <-timer.C:
+				if wait == bt.opts.IdleDelay {
+					bt.idleFlushes.Add(1)
+				} else {
+					bt.delayFlushes.Add(1)
+				}
+				batch = bt.send(batch)
+				continue
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+		batch = bt.send(batch)
+	}
+}
+
+// send sends (or releases, following a prior error) the given batch, returning it emptied.
+func (bt *BatchingTransport) send(batch []*amp.TxMsg) []*amp.TxMsg {
+	if len(batch) == 0 {
+		return batch
+	}
+	if bt.sendErr() != nil {
+		releaseAll(batch)
+		return batch[:0]
+	}
+
+	var err error
+	sent := 0
+	if sender, ok := bt.inner.(BatchSender); ok && len(batch) > 1 {
+		sent, err = sender.SendTxBatch(batch)
+	} else {
+		for i, tx := range batch {
+			if err = bt.inner.SendTx(tx); err != nil {
+				releaseAll(batch[i+1:])
+				break
+			}
+			sent++
+		}
+	}
+	if err != nil {
+		bt.err.CompareAndSwap(nil, &err)
+	}
+
+	// Only what reached the wrapped transport is counted so that Stats reflects what was written
+	if sent > 0 {
+		bt.txMsgs.Add(uint64(sent))
+		bt.batches.Add(1)
+	}
+	clear(batch)
+	return batch[:0]
+}
+
+func txSzEstimate(tx *amp.TxMsg) int {
+	return int(amp.Const_TxHeader_Size) + len(tx.Ops)*txOpSzEstimate + len(tx.DataStore)
+}
+
+func releaseAll(txs []*amp.TxMsg) {
+	for _, tx := range txs {
+		tx.ReleaseRef()
+	}
+}
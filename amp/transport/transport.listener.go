@@ -0,0 +1,157 @@
+package transport
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"sync/atomic"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/task"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/utils"
+)
+
+// Listener is an amp.HostService that accepts stream connections and starts a new amp.Session for each.
+type Listener struct {
+	task.Context
+	opts     ListenerOpts
+	host     amp.Host
+	listener net.Listener
+	stopping atomic.Bool
+}
+
+// NewListener returns a HostService that, once started, listens on the given network address.
+func NewListener(opts ListenerOpts) *Listener {
+	if opts.Network == "" {
+		opts.Network = "tcp"
+	}
+	if opts.Address == "" && opts.Network != "unix" {
+		opts.Address = fmt.Sprintf(":%d", amp.Const_DefaultServicePort)
+	}
+	return &Listener{
+		opts: opts,
+	}
+}
+
+// Addr returns the address this Listener is bound to (or nil if not started).
+func (l *Listener) Addr() net.Addr {
+	if l.listener == nil {
+		return nil
+	}
+	return l.listener.Addr()
+}
+
+func (l *Listener) StartService(on amp.Host) error {
+	if l.opts.Network == "unix" {
+		if fi, err := os.Stat(l.opts.Address); err == nil && fi.Mode()&os.ModeSocket != 0 {
+			os.Remove(l.opts.Address) // remove a stale socket from a previous run
+		}
+	}
+
+	tlsConfig, err := l.opts.TLS.MakeConfig()
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen(l.opts.Network, l.opts.Address)
+	if err != nil {
+		return amp.ErrCode_NotConnected.Wrap(err)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	l.host = on
+	l.listener = listener
+	_, err = on.StartChild(&task.Task{
+		Info: task.Info{
+			Label: fmt.Sprintf("listener %s %s", l.opts.Network, listener.Addr().String()),
+		},
+		OnStart: func(ctx task.Context) error {
+			l.Context = ctx
+			return nil
+		},
+		OnRun: func(ctx task.Context) {
+			ctx.Log().Infof(1, "listening")
+			l.acceptLoop()
+		},
+		OnClosing: func() {
+			l.listener.Close()
+		},
+	})
+	if err != nil {
+		listener.Close()
+		return err
+	}
+	return nil
+}
+
+func (l *Listener) acceptLoop() {
+	for {
+		conn, err := l.listener.Accept()
+		if err != nil {
+			select {
+			case <-l.Closing():
+			default:
+				if !l.stopping.Load() {
+					l.Log().Warnf("accept failed: %v", err)
+					l.Close()
+				}
+			}
+			return
+		}
+
+		stream := NewStreamTransport(conn, l.opts.Stream)
+		if _, err := l.host.StartNewSession(l, stream); err != nil {
+			l.Log().Warnf("StartNewSession failed for %v: %v", stream.Label(), err)
+			stream.Close()
+		}
+	}
+}
+
+// GracefulStop stops accepting new connections and blocks until all child sessions have closed.
+func (l *Listener) GracefulStop() {
+	if l.listener == nil {
+		return
+	}
+	l.stopping.Store(true)
+	l.listener.Close()
+
+	for {
+		children := l.GetChildren(nil)
+		if len(children) == 0 {
+			break
+		}
+		<-children[0].Done()
+	}
+}
+
+// Enabled returns true if these options result in a TLS listener.
+func (opts TLSOpts) Enabled() bool {
+	return opts.CertFile != "" || opts.SelfSigned
+}
+
+// MakeConfig returns the tls.Config described by these options (or nil if TLS is not enabled).
+func (opts TLSOpts) MakeConfig() (*tls.Config, error) {
+	var cert tls.Certificate
+	switch {
+	case opts.CertFile != "":
+		var err error
+		cert, err = tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+	case opts.SelfSigned:
+		selfSigned, err := utils.MakeSelfSignedX509Certificate()
+		if err != nil {
+			return nil, err
+		}
+		cert = *selfSigned
+	default:
+		return nil, nil
+	}
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+	}, nil
+}
@@ -0,0 +1,72 @@
+package transport
+
+import (
+	"io"
+	"strings"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/metrics"
+)
+
+// Traffic metrics, labelled by transport kind ("tcp", "unix", "ws", or "pipe")
+var (
+	txMsgsIn       = metrics.Default.CounterVec("amp_transport_txmsgs_in_total", "TxMsgs received.", "transport")
+	txMsgsOut      = metrics.Default.CounterVec("amp_transport_txmsgs_out_total", "TxMsgs sent.", "transport")
+	bytesIn        = metrics.Default.CounterVec("amp_transport_bytes_in_total", "Bytes received (excluding pipe transports).", "transport")
+	bytesOut       = metrics.Default.CounterVec("amp_transport_bytes_out_total", "Bytes sent (excluding pipe transports).", "transport")
+	decodeFailures = metrics.Default.CounterVec("amp_transport_decode_failures_total", "Received TxMsgs that failed to decode, by ErrCode.", "transport", "code")
+)
+
+// meter counts the traffic of a transport of a given kind.
+type meter struct {
+	kind     string
+	txIn     *metrics.Counter
+	txOut    *metrics.Counter
+	bytesIn  *metrics.Counter
+	bytesOut *metrics.Counter
+}
+
+func newMeter(kind string) meter {
+	return meter{
+		kind:     kind,
+		txIn:     txMsgsIn.With(kind),
+		txOut:    txMsgsOut.With(kind),
+		bytesIn:  bytesIn.With(kind),
+		bytesOut: bytesOut.With(kind),
+	}
+}
+
+// recvd counts the outcome of reading a TxMsg: a TxMsg received or, if err is an amp.Err from amp.TxReader, a decode failure.
+func (m *meter) recvd(err error) {
+	if err == nil {
+		m.txIn.Inc()
+		return
+	}
+	if code := amp.GetErrCode(err); code != amp.ErrCode_UnnamedErr {
+		decodeFailures.With(m.kind, strings.TrimPrefix(code.String(), "ErrCode_")).Inc()
+	}
+}
+
+// countingReader counts the bytes read from an io.Reader.
+type countingReader struct {
+	r io.Reader
+	n *metrics.Counter
+}
+
+func (cr countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n.Add(uint64(n))
+	return n, err
+}
+
+// countingWriter counts the bytes written to an io.Writer.
+type countingWriter struct {
+	w io.Writer
+	n *metrics.Counter
+}
+
+func (cw countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n.Add(uint64(n))
+	return n, err
+}
@@ -0,0 +1,217 @@
+package transport
+
+import (
+	"bytes"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+)
+
+// PipeOpts configures an in-process transport pair -- see NewPipeTransport().
+type PipeOpts struct {
+	Label     string        // label prefix for each end; if empty, "pipe" is used
+	Serialize bool          // if set, each TxMsg is marshalled and then re-read via amp.ReadTxMsg, exercising the wire format
+	Latency   time.Duration // delay between SendTx() and when the TxMsg is available to the remote RecvTx()
+	FailAfter int           // if > 0, the pipe breaks once this many TxMsgs have been sent (by either end)
+	QueueSz   int           // max number of TxMsgs in flight in each direction; if <= 0, 64 is used
+}
+
+// PipeTransport is one end of an in-process amp.Transport pair.
+type PipeTransport struct {
+	label  string
+	pipe   *pipe
+	out    *pipeQueue // TxMsgs sent by this end
+	in     *pipeQueue // TxMsgs sent by the remote end
+	scrap  []byte     // used when PipeOpts.Serialize is set
+	sendMu sync.Mutex
+	closed atomic.Bool
+	meter  meter
+}
+
+// NewPipeTransport returns two connected amp.Transport ends, allowing a Host, Session, and App to be exercised in one process with no sockets.
+func NewPipeTransport(opts PipeOpts) (*PipeTransport, *PipeTransport) {
+	if opts.Label == "" {
+		opts.Label = "pipe"
+	}
+	if opts.QueueSz <= 0 {
+		opts.QueueSz = 64
+	}
+
+	p := &pipe{
+		opts:   opts,
+		broken: make(chan struct{}),
+	}
+	ab := newPipeQueue(opts.QueueSz)
+	ba := newPipeQueue(opts.QueueSz)
+
+	a := &PipeTransport{
+		label: opts.Label + " a",
+		pipe:  p,
+		out:   ab,
+		in:    ba,
+		meter: newMeter("pipe"),
+	}
+	b := &PipeTransport{
+		label: opts.Label + " b",
+		pipe:  p,
+		out:   ba,
+		in:    ab,
+		meter: newMeter("pipe"),
+	}
+	return a, b
+}
+
+// pipe is the state shared by both ends of a pipe.
+type pipe struct {
+	opts      PipeOpts
+	sendCount atomic.Int64
+	breakOnce sync.Once
+	broken    chan struct{}
+	brokenErr error
+}
+
+type pipeEntry struct {
+	tx        *amp.TxMsg
+	deliverAt time.Time
+}
+
+// pipeQueue is a one-way TxMsg queue where the sending end closes the queue.
+type pipeQueue struct {
+	entries chan pipeEntry
+	closed  chan struct{}
+	once    sync.Once
+}
+
+func newPipeQueue(queueSz int) *pipeQueue {
+	return &pipeQueue{
+		entries: make(chan pipeEntry, queueSz),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (q *pipeQueue) close() {
+	q.once.Do(func() {
+		close(q.closed)
+	})
+}
+
+// Break simulates a connection failure: both ends are closed and pending or subsequent calls return the given error.
+// If err == nil, amp.ErrStreamClosed is used.
+func (pt *PipeTransport) Break(err error) {
+	pt.pipe.breakWith(err)
+}
+
+func (p *pipe) breakWith(err error) {
+	p.breakOnce.Do(func() {
+		if err == nil {
+			err = amp.ErrStreamClosed
+		}
+		p.brokenErr = err
+		close(p.broken)
+	})
+}
+
+func (pt *PipeTransport) Label() string {
+	return pt.label
+}
+
+// Close closes this end's outgoing queue; the remote end receives amp.ErrStreamClosed once it has read all pending TxMsgs.
+func (pt *PipeTransport) Close() error {
+	pt.closed.Store(true)
+	pt.out.close()
+	pt.in.close()
+	return nil
+}
+
+func (pt *PipeTransport) SendTx(tx *amp.TxMsg) error {
+	p := pt.pipe
+
+	pt.sendMu.Lock()
+	defer pt.sendMu.Unlock()
+
+	select {
+	case <-p.broken:
+		tx.ReleaseRef()
+		return p.brokenErr
+	case <-pt.out.closed:
+		tx.ReleaseRef()
+		return amp.ErrStreamClosed
+	default:
+	}
+
+	if p.opts.Serialize {
+		tx.MarshalToBuffer(&pt.scrap)
+		tx.ReleaseRef()
+
+		var err error
+		tx, err = amp.ReadTxMsg(bytes.NewReader(pt.scrap))
+		if err != nil {
+			pt.meter.recvd(err)
+			return err
+		}
+	}
+
+	entry := pipeEntry{
+		tx: tx,
+	}
+	if p.opts.Latency > 0 {
+		entry.deliverAt = time.Now().Add(p.opts.Latency)
+	}
+
+	select {
+	case pt.out.entries <- entry:
+	case <-pt.out.closed:
+		tx.ReleaseRef()
+		return amp.ErrStreamClosed
+	case <-p.broken:
+		tx.ReleaseRef()
+		return p.brokenErr
+	}
+
+	if p.opts.FailAfter > 0 && p.sendCount.Add(1) >= int64(p.opts.FailAfter) {
+		p.breakWith(nil)
+	}
+	pt.meter.txOut.Inc()
+	return nil
+}
+
+func (pt *PipeTransport) RecvTx() (*amp.TxMsg, error) {
+	p := pt.pipe
+	if pt.closed.Load() {
+		return nil, amp.ErrStreamClosed
+	}
+	select {
+	case <-p.broken:
+		return nil, p.brokenErr
+	default:
+	}
+
+	var entry pipeEntry
+	select {
+	case <-p.broken:
+		return nil, p.brokenErr
+	case entry = <-pt.in.entries:
+	case <-pt.in.closed:
+		// drain what the remote end sent before it closed
+		select {
+		case entry = <-pt.in.entries:
+		default:
+			return nil, amp.ErrStreamClosed
+		}
+	}
+
+	if wait := time.Until(entry.deliverAt); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-p.broken:
+			timer.Stop()
+			entry.tx.ReleaseRef()
+			return nil, p.brokenErr
+		}
+	}
+	pt.meter.txIn.Inc()
+	return entry.tx, nil
+}
@@ -0,0 +1,196 @@
+package transport
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+)
+
+// Dial connects to the given network address and returns an amp.Transport for the connection.
+func Dial(network, address string, opts StreamOpts) (amp.Transport, error) {
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, amp.ErrCode_NotConnected.Wrap(err)
+	}
+	return NewStreamTransport(conn, opts), nil
+}
+
+// NewStreamTransport wraps a stream connection (e.g. tcp or unix socket) into an amp.Transport.
+// Each TxMsg is written using TxMsg.MarshalToWriterWith and read using an amp.TxReader bounded by StreamOpts.MaxTxSz.
+func NewStreamTransport(conn net.Conn, opts StreamOpts) amp.Transport {
+	if opts.ReadBufSz <= 0 {
+		opts.ReadBufSz = DefaultBufSz
+	}
+	if opts.WriteBufSz <= 0 {
+		opts.WriteBufSz = DefaultBufSz
+	}
+	if opts.Label == "" {
+		opts.Label = conn.RemoteAddr().Network() + " " + conn.RemoteAddr().String()
+	}
+	enc := amp.DefaultTxEncoding
+	if opts.Encoding != nil {
+		enc = *opts.Encoding
+	}
+
+	m := newMeter(conn.RemoteAddr().Network())
+	return &streamTransport{
+		label: opts.Label,
+		conn:  conn,
+		rd:    bufio.NewReaderSize(countingReader{conn, m.bytesIn}, opts.ReadBufSz),
+		txRd: amp.TxReader{
+			MaxTxSz: opts.MaxTxSz,
+			Codecs:  opts.Codecs,
+		},
+		enc:   enc,
+		wr:    bufio.NewWriterSize(countingWriter{conn, m.bytesOut}, opts.WriteBufSz),
+		meter: m,
+	}
+}
+
+// streamTransport implements amp.Transport for a net.Conn
+type streamTransport struct {
+	label  string
+	conn   net.Conn
+	rd     *bufio.Reader
+	txRd   amp.TxReader
+	sendMu sync.Mutex    // serializes SendTx() and Close()
+	wr     *bufio.Writer // buffers writes so that each TxMsg is flushed as a single write
+	enc    amp.TxEncoding
+	scrap  []byte // scrap buffer for TxMsg header and ops
+	closed atomic.Bool
+	meter  meter
+}
+
+// halfCloser is implemented by *net.TCPConn and *net.UnixConn
+type halfCloser interface {
+	CloseWrite() error
+}
+
+func (st *streamTransport) Label() string {
+	return st.label
+}
+
+// Close flushes pending writes and shuts down the write side first so the remote end reads a clean end of stream.
+// A write deadline is set first so that a SendTx blocked on a stalled peer (holding sendMu) can't block Close.
+func (st *streamTransport) Close() error {
+	if !st.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+
+	st.conn.SetWriteDeadline(time.Now().Add(CloseFlushTimeout))
+	st.sendMu.Lock()
+	st.wr.Flush()
+	if hc, ok := st.conn.(halfCloser); ok {
+		hc.CloseWrite()
+	}
+	st.sendMu.Unlock()
+
+	return st.conn.Close()
+}
+
+func (st *streamTransport) SendTx(tx *amp.TxMsg) error {
+	defer tx.ReleaseRef()
+
+	st.sendMu.Lock()
+	defer st.sendMu.Unlock()
+
+	if st.closed.Load() {
+		return amp.ErrStreamClosed
+	}
+
+	err := tx.MarshalToWriterWith(st.enc, &st.scrap, st.wr)
+	if err == nil {
+		err = st.flush()
+	}
+	if err == nil {
+		st.meter.txOut.Inc()
+	}
+	return st.filterErr(err)
+}
+
+// flush writes buffered TxMsgs to the connection -- caller holds sendMu.
+// If the write fails, the stream may end partway through a TxMsg, so the connection is closed rather than have a later TxMsg follow it.
+func (st *streamTransport) flush() error {
+	err := st.wr.Flush()
+	if err != nil {
+		st.conn.Close()
+	}
+	return err
+}
+
+// SendTxBatch implements BatchSender, writing each TxMsg into the buffered writer and flushing once.
+//
+// If a TxMsg fails to marshal, nothing of it has been written, so the TxMsgs before it are still sent and those after it are not.
+func (st *streamTransport) SendTxBatch(txs []*amp.TxMsg) (int, error) {
+	defer releaseAll(txs)
+
+	st.sendMu.Lock()
+	defer st.sendMu.Unlock()
+
+	if st.closed.Load() {
+		return 0, amp.ErrStreamClosed
+	}
+
+	var err error
+	sent := 0
+	for _, tx := range txs {
+		if err = tx.MarshalToWriterWith(st.enc, &st.scrap, st.wr); err != nil {
+			break
+		}
+		sent++
+	}
+	if flushErr := st.flush(); flushErr != nil {
+		return 0, st.filterErr(flushErr)
+	}
+	st.meter.txOut.Add(uint64(sent))
+	return sent, st.filterErr(err)
+}
+
+// SetTxEncoding implements amp.TxEncoder.
+func (st *streamTransport) SetTxEncoding(enc amp.TxEncoding) {
+	st.sendMu.Lock()
+	st.enc = enc
+	st.sendMu.Unlock()
+}
+
+func (st *streamTransport) RecvTx() (*amp.TxMsg, error) {
+	tx, err := st.txRd.ReadTxMsg(st.rd)
+	st.meter.recvd(err)
+	if err != nil {
+		return nil, st.filterErr(err)
+	}
+	return tx, nil
+}
+
+// filterErr maps errors that reflect a normal stream close to amp.ErrStreamClosed.
+func (st *streamTransport) filterErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if st.closed.Load() || IsClosedErr(err) {
+		return amp.ErrStreamClosed
+	}
+	return err
+}
+
+// IsClosedErr returns true if the given error denotes a connection that was closed normally or by the remote end.
+func IsClosedErr(err error) bool {
+	switch {
+	case err == amp.ErrStreamClosed,
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, io.ErrClosedPipe),
+		errors.Is(err, net.ErrClosed),
+		errors.Is(err, syscall.EPIPE),
+		errors.Is(err, syscall.ECONNRESET):
+		return true
+	}
+	return false
+}
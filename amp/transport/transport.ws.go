@@ -0,0 +1,656 @@
+package transport
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/task"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/utils"
+)
+
+const (
+	// WebSocketProtocol is the WebSocket sub-protocol name offered and accepted for amp TxMsg exchange.
+	WebSocketProtocol = "amp.tx"
+
+	// DefaultWebSocketPath is the URL path where a WebSocketService accepts upgrade requests.
+	DefaultWebSocketPath = "/amp"
+
+	// DefaultMaxMessageSz is the default max byte size of a single WebSocket message (one TxMsg).
+	DefaultMaxMessageSz = 64 << 20
+)
+
+// WebSocketService is an amp.HostService that upgrades HTTP requests to WebSockets and starts a new amp.Session for each.
+// Each binary WebSocket message carries exactly one TxMsg.
+type WebSocketService struct {
+	task.Context
+	opts     WebSocketOpts
+	host     amp.Host
+	server   *http.Server
+	listener net.Listener
+	stopping atomic.Bool
+}
+
+// NewWebSocketService returns a HostService that serves WebSocket upgrade requests once started.
+func NewWebSocketService(opts WebSocketOpts) *WebSocketService {
+	if opts.Path == "" {
+		opts.Path = DefaultWebSocketPath
+	}
+	if opts.MaxMessageSz <= 0 {
+		opts.MaxMessageSz = DefaultMaxMessageSz
+	}
+	return &WebSocketService{
+		opts: opts,
+	}
+}
+
+// Addr returns the address this service is bound to (or nil if not listening).
+func (ws *WebSocketService) Addr() net.Addr {
+	if ws.listener == nil {
+		return nil
+	}
+	return ws.listener.Addr()
+}
+
+// Handler returns an http.Handler (with unrestricted CORS) that upgrades requests and calls Host.StartNewSession.
+// Valid once StartService() has been called.
+func (ws *WebSocketService) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(ws.opts.Path, ws.serveUpgrade)
+	return utils.UnrestrictedCors(mux)
+}
+
+func (ws *WebSocketService) StartService(on amp.Host) error {
+	ws.host = on
+
+	label := "websocket"
+	if ws.opts.Address != "" {
+		tlsConfig, err := ws.opts.TLS.MakeConfig()
+		if err != nil {
+			return err
+		}
+		listener, err := net.Listen("tcp", ws.opts.Address)
+		if err != nil {
+			return amp.ErrCode_NotConnected.Wrap(err)
+		}
+		if tlsConfig != nil {
+			listener = tls.NewListener(listener, tlsConfig)
+		}
+		ws.listener = listener
+		ws.server = &http.Server{
+			Handler:           ws.Handler(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		label = fmt.Sprintf("websocket %s%s", listener.Addr().String(), ws.opts.Path)
+	}
+
+	_, err := on.StartChild(&task.Task{
+		Info: task.Info{
+			Label: label,
+		},
+		OnStart: func(ctx task.Context) error {
+			ws.Context = ctx
+			return nil
+		},
+		OnRun: func(ctx task.Context) {
+			if ws.server == nil {
+				<-ctx.Closing()
+				return
+			}
+			ctx.Log().Infof(1, "listening")
+			err := ws.server.Serve(ws.listener)
+			if err != nil && err != http.ErrServerClosed && !ws.stopping.Load() {
+				ctx.Log().Warnf("serve failed: %v", err)
+				ctx.Close()
+			}
+		},
+		OnClosing: func() {
+			if ws.server != nil {
+				ws.server.Close()
+			}
+		},
+	})
+	if err != nil && ws.listener != nil {
+		ws.listener.Close()
+	}
+	return err
+}
+
+// GracefulStop stops accepting new connections and blocks until all child sessions have closed.
+func (ws *WebSocketService) GracefulStop() {
+	ws.stopping.Store(true)
+	if ws.server != nil {
+		ws.server.Close() // hijacked connections are not affected
+	}
+	if ws.Context == nil {
+		return
+	}
+	for {
+		children := ws.GetChildren(nil)
+		if len(children) == 0 {
+			break
+		}
+		<-children[0].Done()
+	}
+}
+
+func (ws *WebSocketService) serveUpgrade(w http.ResponseWriter, r *http.Request) {
+	if ws.stopping.Load() {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+
+	conn, rw, err := upgradeWebSocket(w, r)
+	if err != nil {
+		ws.Log().Infof(1, "upgrade failed: %v", err)
+		return
+	}
+
+	wsConn := newWebSocket(conn, rw.Reader, true, ws.opts)
+	wsConn.label = "websocket " + r.RemoteAddr
+	if _, err := ws.host.StartNewSession(ws, wsConn); err != nil {
+		ws.Log().Warnf("StartNewSession failed for %v: %v", wsConn.Label(), err)
+		wsConn.Close()
+	}
+}
+
+// DialWebSocket connects to a WebSocketService at the given "ws://" or "wss://" URL.
+// If tlsConfig is nil and the URL is "wss", a default tls.Config is used.
+func DialWebSocket(wsURL string, tlsConfig *tls.Config) (amp.Transport, error) {
+	u, err := url.Parse(wsURL)
+	if err != nil {
+		return nil, amp.ErrCode_InvalidURI.Wrap(err)
+	}
+
+	host := u.Host
+	var conn net.Conn
+	switch u.Scheme {
+	case "ws", "http":
+		if u.Port() == "" {
+			host += ":80"
+		}
+		conn, err = net.Dial("tcp", host)
+	case "wss", "https":
+		if u.Port() == "" {
+			host += ":443"
+		}
+		if tlsConfig == nil {
+			tlsConfig = &tls.Config{}
+		}
+		conn, err = tls.Dial("tcp", host, tlsConfig)
+	default:
+		return nil, amp.ErrCode_InvalidURI.Errorf("unsupported websocket scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, amp.ErrCode_NotConnected.Wrap(err)
+	}
+
+	var keyBuf [16]byte
+	rand.Read(keyBuf[:])
+	key := base64.StdEncoding.EncodeToString(keyBuf[:])
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Host:       u.Host,
+		Header: http.Header{
+			"Upgrade":                {"websocket"},
+			"Connection":             {"Upgrade"},
+			"Sec-WebSocket-Key":      {key},
+			"Sec-WebSocket-Version":  {"13"},
+			"Sec-WebSocket-Protocol": {WebSocketProtocol},
+		},
+	}
+	if err = req.Write(conn); err != nil {
+		conn.Close()
+		return nil, amp.ErrCode_NotConnected.Wrap(err)
+	}
+
+	rd := bufio.NewReaderSize(conn, DefaultBufSz)
+	resp, err := http.ReadResponse(rd, req)
+	if err != nil {
+		conn.Close()
+		return nil, amp.ErrCode_NotConnected.Wrap(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-WebSocket-Accept") != webSocketAccept(key) {
+		conn.Close()
+		return nil, amp.ErrCode_NotConnected.Errorf("websocket upgrade failed: %s", resp.Status)
+	}
+
+	wsConn := newWebSocket(conn, rd, false, WebSocketOpts{})
+	wsConn.label = "websocket " + wsURL
+	return wsConn, nil
+}
+
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request) (net.Conn, *bufio.ReadWriter, error) {
+	if r.Method != http.MethodGet ||
+		!headerHasToken(r.Header, "Connection", "upgrade") ||
+		!headerHasToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+		return nil, nil, amp.ErrCode_BadRequest.Error("not a websocket upgrade request")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusBadRequest)
+		return nil, nil, amp.ErrCode_BadRequest.Error("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, nil, amp.ErrCode_BadRequest.Error("missing Sec-WebSocket-Key")
+	}
+
+	conn, rw, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, nil, err
+	}
+
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + webSocketAccept(key) + "\r\n"
+	if headerHasToken(r.Header, "Sec-WebSocket-Protocol", WebSocketProtocol) {
+		resp += "Sec-WebSocket-Protocol: " + WebSocketProtocol + "\r\n"
+	}
+	resp += "\r\n"
+
+	if _, err = rw.WriteString(resp); err == nil {
+		err = rw.Flush()
+	}
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, rw, nil
+}
+
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, val := range header.Values(name) {
+		for _, ti := range strings.Split(val, ",") {
+			if strings.EqualFold(strings.TrimSpace(ti), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// See RFC 6455, section 4.2.2
+func webSocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key))
+	h.Write([]byte("258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// WebSocket frame opcodes -- RFC 6455, section 5.2
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// webSocket implements amp.Transport over a WebSocket connection.
+type webSocket struct {
+	label        string
+	conn         net.Conn
+	rd           *bufio.Reader
+	isServer     bool // servers expect masked frames from clients and send unmasked frames
+	maxMessageSz int
+	txRd         amp.TxReader
+	enc          amp.TxEncoding // guarded by sendMu
+	sendMu       sync.Mutex
+	scrap        []byte // outgoing frame buffer
+	msg          []byte // incoming message buffer
+	closed       atomic.Bool
+	meter        meter
+}
+
+func newWebSocket(conn net.Conn, rd *bufio.Reader, isServer bool, opts WebSocketOpts) *webSocket {
+	if rd == nil {
+		rd = bufio.NewReaderSize(conn, DefaultBufSz)
+	}
+	if opts.MaxMessageSz <= 0 {
+		opts.MaxMessageSz = DefaultMaxMessageSz
+	}
+	enc := amp.DefaultTxEncoding
+	if opts.Encoding != nil {
+		enc = *opts.Encoding
+	}
+	return &webSocket{
+		conn:         conn,
+		rd:           rd,
+		isServer:     isServer,
+		maxMessageSz: opts.MaxMessageSz,
+		txRd: amp.TxReader{
+			MaxTxSz: opts.MaxMessageSz,
+			Codecs:  opts.Codecs,
+		},
+		enc:   enc,
+		meter: newMeter("ws"),
+	}
+}
+
+func (ws *webSocket) Label() string {
+	return ws.label
+}
+
+// Close sends a normal closure frame and closes the connection.
+func (ws *webSocket) Close() error {
+	if !ws.closed.CompareAndSwap(false, true) {
+		return nil
+	}
+	ws.conn.SetWriteDeadline(time.Now().Add(CloseFlushTimeout)) // see streamTransport.Close
+	ws.sendMu.Lock()
+	ws.writeFrame(wsOpClose, []byte{0x03, 0xE8}) // 1000: normal closure
+	ws.sendMu.Unlock()
+	return ws.conn.Close()
+}
+
+func (ws *webSocket) SendTx(tx *amp.TxMsg) error {
+	defer tx.ReleaseRef()
+
+	ws.sendMu.Lock()
+	defer ws.sendMu.Unlock()
+
+	if ws.closed.Load() {
+		return amp.ErrStreamClosed
+	}
+
+	frame, err := ws.appendTxFrame(ws.scrap[:0], tx)
+	if err != nil {
+		return err
+	}
+	ws.scrap = frame
+	return ws.sent(writeAll(ws.conn, frame), 1, len(frame))
+}
+
+// SendTxBatch implements BatchSender, sending each TxMsg as its own message but in a single write.
+// If a TxMsg fails to marshal, none of the batch is sent.
+func (ws *webSocket) SendTxBatch(txs []*amp.TxMsg) (int, error) {
+	defer releaseAll(txs)
+
+	ws.sendMu.Lock()
+	defer ws.sendMu.Unlock()
+
+	if ws.closed.Load() {
+		return 0, amp.ErrStreamClosed
+	}
+
+	frames := ws.scrap[:0]
+	for _, tx := range txs {
+		var err error
+		if frames, err = ws.appendTxFrame(frames, tx); err != nil {
+			return 0, err
+		}
+	}
+	ws.scrap = frames
+	if err := ws.sent(writeAll(ws.conn, frames), len(txs), len(frames)); err != nil {
+		return 0, err
+	}
+	return len(txs), nil
+}
+
+// sent counts the given TxMsgs and bytes as sent unless err is set, returning the filtered err.
+func (ws *webSocket) sent(err error, txCount, byteCount int) error {
+	if err != nil {
+		return ws.filterErr(err)
+	}
+	ws.meter.txOut.Add(uint64(txCount))
+	ws.meter.bytesOut.Add(uint64(byteCount))
+	return nil
+}
+
+// appendTxFrame appends the given TxMsg to dst as a binary message frame -- the caller holds sendMu.
+func (ws *webSocket) appendTxFrame(dst []byte, tx *amp.TxMsg) ([]byte, error) {
+
+	// Reserve room for the largest frame header so the payload is marshalled in place
+	const maxHeaderSz = 14
+	if cap(dst)-len(dst) < maxHeaderSz+2048 {
+		grown := make([]byte, len(dst), 2*cap(dst)+maxHeaderSz+2048)
+		copy(grown, dst)
+		dst = grown
+	}
+	payload := dst[len(dst)+maxHeaderSz : len(dst)+maxHeaderSz]
+	if err := tx.MarshalToBufferWith(ws.enc, &payload); err != nil {
+		return dst, err
+	}
+	return ws.appendFrame(dst, wsOpBinary, payload), nil
+}
+
+func (ws *webSocket) RecvTx() (*amp.TxMsg, error) {
+	msg, err := ws.readMessage()
+	if err != nil {
+		return nil, ws.filterErr(err)
+	}
+	ws.meter.bytesIn.Add(uint64(len(msg)))
+	tx, err := ws.txRd.ReadTxMsg(bytes.NewReader(msg))
+	ws.meter.recvd(err)
+	return tx, err
+}
+
+// SetTxEncoding implements amp.TxEncoder.
+func (ws *webSocket) SetTxEncoding(enc amp.TxEncoding) {
+	ws.sendMu.Lock()
+	ws.enc = enc
+	ws.sendMu.Unlock()
+}
+
+func (ws *webSocket) filterErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	if ws.closed.Load() || IsClosedErr(err) {
+		return amp.ErrStreamClosed
+	}
+	return err
+}
+
+// readMessage reads frames until a complete binary message is received, handling control frames along the way.
+// Only continuation and control frames may follow the first frame of a fragmented message -- RFC 6455, section 5.4
+func (ws *webSocket) readMessage() ([]byte, error) {
+	msg := ws.msg[:0]
+	fragmented := false // set once a non-final data frame is read
+	for {
+		fin, opcode, payload, err := ws.readFrame(msg)
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpContinuation:
+			if !fragmented {
+				return nil, amp.ErrCode_MalformedTx.Error("websocket continuation frame without a message")
+			}
+		case wsOpBinary:
+			if fragmented {
+				return nil, amp.ErrCode_MalformedTx.Error("websocket data frame within a fragmented message")
+			}
+		case wsOpPing:
+			ws.sendMu.Lock()
+			err = ws.writeFrame(wsOpPong, payload[len(msg):])
+			ws.sendMu.Unlock()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		case wsOpPong:
+			continue
+		case wsOpClose:
+			if ws.closed.CompareAndSwap(false, true) {
+				ws.sendMu.Lock()
+				ws.writeFrame(wsOpClose, nil)
+				ws.sendMu.Unlock()
+				ws.conn.Close()
+			}
+			return nil, amp.ErrStreamClosed
+		case wsOpText:
+			return nil, amp.ErrCode_MalformedTx.Error("websocket text message not supported")
+		default:
+			return nil, amp.ErrCode_MalformedTx.Errorf("websocket opcode %#x not supported", opcode)
+		}
+
+		msg = payload
+		fragmented = true
+		if fin {
+			ws.msg = msg
+			return msg, nil
+		}
+	}
+}
+
+// readFrame reads a single frame, appending its unmasked payload to dst.
+// Control frame payloads are appended to dst but are not retained by the caller.
+func (ws *webSocket) readFrame(dst []byte) (fin bool, opcode byte, out []byte, err error) {
+	var hdr [14]byte
+	if _, err = io.ReadFull(ws.rd, hdr[:2]); err != nil {
+		return
+	}
+	fin = hdr[0]&0x80 != 0
+	opcode = hdr[0] & 0x0F
+	masked := hdr[1]&0x80 != 0
+	if masked != ws.isServer {
+		err = amp.ErrCode_MalformedTx.Error("websocket frame masking violation")
+		return
+	}
+	if hdr[0]&0x70 != 0 {
+		err = amp.ErrCode_MalformedTx.Error("websocket reserved bits set")
+		return
+	}
+
+	payloadLen := uint64(hdr[1] & 0x7F)
+	switch payloadLen {
+	case 126:
+		if _, err = io.ReadFull(ws.rd, hdr[2:4]); err != nil {
+			return
+		}
+		payloadLen = uint64(binary.BigEndian.Uint16(hdr[2:4]))
+	case 127:
+		if _, err = io.ReadFull(ws.rd, hdr[2:10]); err != nil {
+			return
+		}
+		payloadLen = binary.BigEndian.Uint64(hdr[2:10])
+	}
+	if opcode >= wsOpClose && (payloadLen > 125 || !fin) {
+		err = amp.ErrCode_MalformedTx.Error("websocket control frame too long or fragmented")
+		return
+	}
+	if payloadLen&(1<<63) != 0 {
+		err = amp.ErrCode_MalformedTx.Error("websocket frame length has its most significant bit set")
+		return
+	}
+	if len(dst) > ws.maxMessageSz || payloadLen > uint64(ws.maxMessageSz-len(dst)) {
+		err = amp.ErrCode_MalformedTx.Errorf("websocket message exceeds %d bytes", ws.maxMessageSz)
+		return
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(ws.rd, mask[:]); err != nil {
+			return
+		}
+	}
+
+	start := len(dst)
+	need := start + int(payloadLen)
+	if cap(dst) < need {
+		grown := make([]byte, start, max(need, 2*cap(dst), 2048))
+		copy(grown, dst)
+		dst = grown
+	}
+	out = dst[:need]
+	if _, err = io.ReadFull(ws.rd, out[start:]); err != nil {
+		return
+	}
+	if masked {
+		for i := start; i < need; i++ {
+			out[i] ^= mask[(i-start)&3]
+		}
+	}
+	return
+}
+
+// writeFrame writes a single control frame -- the caller holds sendMu.
+func (ws *webSocket) writeFrame(opcode byte, payload []byte) error {
+	var buf [14 + 125]byte
+	frame := ws.appendFrame(buf[:0], opcode, append(buf[14:14], payload...))
+	return writeAll(ws.conn, frame)
+}
+
+// appendFrame encodes a final frame into dst, masking the payload (in place) when sending as a client.
+// The payload may reside in dst's backing buffer beyond len(dst) as long as it leaves room for the frame header.
+func (ws *webSocket) appendFrame(dst []byte, opcode byte, payload []byte) []byte {
+	var hdr [14]byte
+	hdr[0] = 0x80 | opcode
+	n := 2
+
+	payloadLen := len(payload)
+	switch {
+	case payloadLen < 126:
+		hdr[1] = byte(payloadLen)
+	case payloadLen <= 0xFFFF:
+		hdr[1] = 126
+		binary.BigEndian.PutUint16(hdr[2:4], uint16(payloadLen))
+		n = 4
+	default:
+		hdr[1] = 127
+		binary.BigEndian.PutUint64(hdr[2:10], uint64(payloadLen))
+		n = 10
+	}
+
+	if !ws.isServer {
+		hdr[1] |= 0x80
+		var mask [4]byte
+		rand.Read(mask[:])
+		copy(hdr[n:], mask[:])
+		n += 4
+		for i := range payload {
+			payload[i] ^= mask[i&3]
+		}
+	}
+
+	// Shift the payload to directly follow the header (copy handles overlap)
+	frameLen := n + payloadLen
+	if cap(dst) < len(dst)+frameLen {
+		grown := make([]byte, len(dst), len(dst)+frameLen)
+		copy(grown, dst)
+		dst = grown
+	}
+	frame := dst[len(dst) : len(dst)+frameLen]
+	copy(frame[n:], payload)
+	copy(frame, hdr[:n])
+	return dst[:len(dst)+frameLen]
+}
+
+func writeAll(w io.Writer, buf []byte) error {
+	for len(buf) > 0 {
+		n, err := w.Write(buf)
+		if err != nil {
+			return err
+		}
+		buf = buf[n:]
+	}
+	return nil
+}
@@ -0,0 +1,676 @@
+package transport_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/transport"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/metrics"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/tag"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/task"
+)
+
+// echoHost is a minimal amp.Host that echoes every received TxMsg back to its sender.
+type echoHost struct {
+	task.Context
+	reg amp.Registry
+}
+
+func startEchoHost(t *testing.T) *echoHost {
+	ctx, err := task.Start(&task.Task{
+		Info: task.Info{
+			Label: "echo host",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ctx.Close() })
+	return &echoHost{
+		Context: ctx,
+		reg:     amp.NewRegistry(),
+	}
+}
+
+func (host *echoHost) HostRegistry() amp.Registry {
+	return host.reg
+}
+
+func (host *echoHost) StartNewSession(parent amp.HostService, via amp.Transport) (amp.Session, error) {
+	_, err := parent.Go(via.Label(), func(ctx task.Context) {
+		defer via.Close()
+		for {
+			tx, err := via.RecvTx()
+			if err != nil {
+				return
+			}
+			if err = via.SendTx(tx); err != nil {
+				return
+			}
+		}
+	})
+	return nil, err
+}
+
+func makeTestTx(t *testing.T, numOps int) *amp.TxMsg {
+	tx := amp.NewTxMsg(true)
+	tx.Status = amp.OpStatus_Synced
+	tx.SetContextID(tag.Now())
+	for i := 0; i < numOps; i++ {
+		err := tx.Upsert(tag.ID{1, 2, 3}, amp.AttrSpec.With("LaunchURL").ID, tag.ID{0, 0, uint64(i)}, &amp.LaunchURL{
+			URL: "amp://test/item/" + tag.ID{0, 0, uint64(i)}.Base32(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return tx
+}
+
+func checkRoundTrip(t *testing.T, sent, recv *amp.TxMsg) {
+	t.Helper()
+	if recv.TxEnvelope != sent.TxEnvelope {
+		t.Fatalf("TxEnvelope mismatch")
+	}
+	if len(recv.Ops) != len(sent.Ops) {
+		t.Fatalf("expected %d ops, got %d", len(sent.Ops), len(recv.Ops))
+	}
+	for i := range sent.Ops {
+		if sent.Ops[i] != recv.Ops[i] {
+			t.Fatalf("op %d mismatch", i)
+		}
+	}
+	if !bytes.Equal(sent.DataStore, recv.DataStore) {
+		t.Fatalf("DataStore mismatch")
+	}
+}
+
+func testListener(t *testing.T, opts transport.ListenerOpts) {
+	host := startEchoHost(t)
+	listener := transport.NewListener(opts)
+	if err := listener.StartService(host); err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr()
+	client, err := transport.Dial(addr.Network(), addr.String(), opts.Stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, numOps := range []int{0, 1, 3000} {
+		tx := makeTestTx(t, numOps)
+		sent := *tx
+		sent.Ops = append([]amp.TxOp{}, tx.Ops...)
+		sent.DataStore = append([]byte{}, tx.DataStore...)
+
+		if err = client.SendTx(tx); err != nil {
+			t.Fatal(err)
+		}
+		recv, err := client.RecvTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkRoundTrip(t, &sent, recv)
+		recv.ReleaseRef()
+	}
+
+	client.Close()
+	if _, err = client.RecvTx(); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+	if err = client.SendTx(amp.NewTxMsg(true)); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+
+	listener.GracefulStop()
+	listener.Close()
+	<-listener.Done()
+}
+
+func TestTCPTransport(t *testing.T) {
+	testListener(t, transport.ListenerOpts{
+		Network: "tcp",
+		Address: "127.0.0.1:0",
+	})
+}
+
+func TestUnixTransport(t *testing.T) {
+	testListener(t, transport.ListenerOpts{
+		Network: "unix",
+		Address: filepath.Join(t.TempDir(), "amp.sock"),
+		Stream: transport.StreamOpts{
+			Encoding: &amp.TxEncoding{
+				Codec:        amp.TxCodec_Gzip,
+				CompressBody: true,
+			},
+		},
+	})
+}
+
+func TestPipeTransport(t *testing.T) {
+	a, b := transport.NewPipeTransport(transport.PipeOpts{
+		Serialize: true,
+		Latency:   20 * time.Millisecond,
+	})
+
+	tx := makeTestTx(t, 100)
+	sent := *tx
+	sent.Ops = append([]amp.TxOp{}, tx.Ops...)
+	sent.DataStore = append([]byte{}, tx.DataStore...)
+
+	start := time.Now()
+	if err := a.SendTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	recv, err := b.RecvTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if time.Since(start) < 20*time.Millisecond {
+		t.Fatal("latency not applied")
+	}
+	checkRoundTrip(t, &sent, recv)
+	recv.ReleaseRef()
+
+	a.Close()
+	if _, err = b.RecvTx(); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+	if err = b.SendTx(amp.NewTxMsg(true)); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+}
+
+func TestPipeTransportFailure(t *testing.T) {
+	a, b := transport.NewPipeTransport(transport.PipeOpts{
+		FailAfter: 2,
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := a.SendTx(makeTestTx(t, 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := a.SendTx(makeTestTx(t, 1)); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+	if _, err := b.RecvTx(); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+}
+
+func testWebSocket(t *testing.T, useTLS bool) {
+	host := startEchoHost(t)
+	service := transport.NewWebSocketService(transport.WebSocketOpts{})
+	if err := service.StartService(host); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		server    *httptest.Server
+		tlsConfig *tls.Config
+	)
+	if useTLS {
+		server = httptest.NewTLSServer(service.Handler())
+		tlsConfig = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	} else {
+		server = httptest.NewServer(service.Handler())
+	}
+	defer server.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + transport.DefaultWebSocketPath
+	client, err := transport.DialWebSocket(wsURL, tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, numOps := range []int{0, 1, 10, 3000} {
+		tx := makeTestTx(t, numOps)
+		sent := *tx
+		sent.Ops = append([]amp.TxOp{}, tx.Ops...)
+		sent.DataStore = append([]byte{}, tx.DataStore...)
+
+		if err = client.SendTx(tx); err != nil {
+			t.Fatal(err)
+		}
+		recv, err := client.RecvTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkRoundTrip(t, &sent, recv)
+		recv.ReleaseRef()
+	}
+
+	client.Close()
+	if _, err = client.RecvTx(); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+	service.GracefulStop()
+	service.Close()
+	<-service.Done()
+}
+
+func TestWebSocketTransport(t *testing.T) {
+	testWebSocket(t, false)
+}
+
+func TestWebSocketTransportTLS(t *testing.T) {
+	testWebSocket(t, true)
+}
+
+func TestBatchingTransport(t *testing.T) {
+	host := startEchoHost(t)
+	listener := transport.NewListener(transport.ListenerOpts{
+		Network: "tcp",
+		Address: "127.0.0.1:0",
+	})
+	if err := listener.StartService(host); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		listener.GracefulStop()
+		listener.Close()
+		<-listener.Done()
+	}()
+
+	addr := listener.Addr()
+	conn, err := transport.Dial(addr.Network(), addr.String(), transport.StreamOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := transport.NewBatchingTransport(conn, transport.BatchOpts{
+		MaxDelay:  50 * time.Millisecond,
+		IdleDelay: 20 * time.Millisecond,
+	})
+
+	const numTx = 100
+	var contextIDs []tag.ID
+	for i := 0; i < numTx; i++ {
+		tx := makeTestTx(t, 3)
+		contextIDs = append(contextIDs, tx.ContextID())
+		if err = client.SendTx(tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < numTx; i++ {
+		recv, err := client.RecvTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if recv.ContextID() != contextIDs[i] || len(recv.Ops) != 3 {
+			t.Fatalf("TxMsg %d out of order or altered", i)
+		}
+		recv.ReleaseRef()
+	}
+
+	stats := client.Stats()
+	if stats.TxMsgs != numTx || stats.Batches == 0 || stats.Batches >= numTx/4 {
+		t.Fatalf("expected batched sends, got %+v", stats)
+	}
+
+	// a full batch is sent without waiting
+	client.SendTx(makeTestTx(t, transport.DefaultMaxBatchSz/16))
+	recv, err := client.RecvTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recv.ReleaseRef()
+	if client.Stats().FullFlushes == 0 {
+		t.Fatalf("expected a full flush")
+	}
+
+	client.Close()
+	if err = client.SendTx(amp.NewTxMsg(true)); err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+}
+
+func TestBatchingTransportPipe(t *testing.T) {
+	a, b := transport.NewPipeTransport(transport.PipeOpts{})
+	client := transport.NewBatchingTransport(a, transport.BatchOpts{})
+
+	// PipeTransport is not a BatchSender, so each TxMsg is sent in turn
+	for i := 0; i < 5; i++ {
+		if err := client.SendTx(makeTestTx(t, 1)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < 5; i++ {
+		recv, err := b.RecvTx()
+		if err != nil {
+			t.Fatal(err)
+		}
+		recv.ReleaseRef()
+	}
+
+	// a send error surfaces on a later call, and what failed to send isn't counted
+	errBroken := amp.ErrCode_NotConnected.Error("broken")
+	a.Break(errBroken)
+	client.SendTx(makeTestTx(t, 1))
+	if err := client.Close(); err != errBroken {
+		t.Fatalf("expected send error from Close, got %v", err)
+	}
+	if stats := client.Stats(); stats.TxMsgs != 5 {
+		t.Fatalf("expected only the 5 TxMsgs sent to be counted, got %+v", stats)
+	}
+}
+
+func TestHandshake(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// the host only accepts gzip, so the client must not send deflate once the handshake completes
+	hostCodecs := amp.TxCodecs(1<<amp.TxCodec_None | 1<<amp.TxCodec_Gzip)
+	hostProto := make(chan amp.Protocol, 1)
+	hostErr := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			hostErr <- err
+			return
+		}
+		host := transport.NewStreamTransport(conn, transport.StreamOpts{
+			Codecs: hostCodecs,
+		})
+		defer host.Close()
+
+		hs := amp.NewHandshake()
+		hs.Codecs = []amp.TxCodec{amp.TxCodec_Gzip}
+		proto, err := amp.ExchangeHandshake(host, hs)
+		if err != nil {
+			hostErr <- err
+			return
+		}
+		hostProto <- proto
+
+		tx, err := host.RecvTx()
+		if err == nil {
+			err = host.SendTx(tx)
+		}
+		hostErr <- err
+	}()
+
+	client, err := transport.Dial("tcp", ln.Addr().String(), transport.StreamOpts{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	proto, err := amp.ExchangeHandshake(client, amp.NewHandshake())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proto.Codecs != hostCodecs || proto.HeaderVersion != amp.MaxTxHeaderVersion {
+		t.Fatalf("unexpected client protocol %+v", proto)
+	}
+	if p := <-hostProto; p.Codecs != hostCodecs {
+		t.Fatalf("unexpected host protocol %+v", p)
+	}
+
+	tx := makeTestTx(t, 3000)
+	sent := *tx
+	sent.Ops = append([]amp.TxOp{}, tx.Ops...)
+	sent.DataStore = append([]byte{}, tx.DataStore...)
+	if err = client.SendTx(tx); err != nil {
+		t.Fatal(err)
+	}
+	recv, err := client.RecvTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkRoundTrip(t, &sent, recv)
+	recv.ReleaseRef()
+	if err = <-hostErr; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// scrape returns the value of the given series from metrics.Default (or 0 if absent).
+func scrape(t *testing.T, series string) float64 {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := metrics.Default.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(buf.String(), "\n") {
+		if val, found := strings.CutPrefix(line, series+" "); found {
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return f
+		}
+	}
+	return 0
+}
+
+func TestTransportMetrics(t *testing.T) {
+	const (
+		txsIn    = `amp_transport_txmsgs_in_total{transport="pipe"}`
+		bytesIn  = `amp_transport_bytes_in_total{transport="pipe"}`
+		failures = `amp_transport_decode_failures_total{transport="pipe",code="MalformedTx"}`
+	)
+	local, remote := net.Pipe()
+	defer remote.Close()
+	st := transport.NewStreamTransport(local, transport.StreamOpts{})
+	defer st.Close()
+
+	prevTxs, prevBytes, prevFailures := scrape(t, txsIn), scrape(t, bytesIn), scrape(t, failures)
+
+	var frame []byte
+	makeTestTx(t, 3).MarshalToBuffer(&frame)
+	go func() {
+		remote.Write(frame)
+		remote.Write(bytes.Repeat([]byte{0xFF}, int(amp.Const_TxHeader_Size)))
+	}()
+
+	tx, err := st.RecvTx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx.ReleaseRef()
+	if _, err = st.RecvTx(); amp.GetErrCode(err) != amp.ErrCode_MalformedTx {
+		t.Fatalf("expected ErrCode_MalformedTx, got %v", err)
+	}
+
+	if got := scrape(t, txsIn) - prevTxs; got != 1 {
+		t.Fatalf("expected 1 TxMsg in, got %v", got)
+	}
+	if got := scrape(t, bytesIn) - prevBytes; got != float64(len(frame))+float64(amp.Const_TxHeader_Size) {
+		t.Fatalf("expected %d bytes in, got %v", len(frame)+int(amp.Const_TxHeader_Size), got)
+	}
+	if got := scrape(t, failures) - prevFailures; got != 1 {
+		t.Fatalf("expected 1 decode failure, got %v", got)
+	}
+}
+
+func TestStreamTransportCloseStalled(t *testing.T) {
+	local, remote := net.Pipe() // writes block until the remote end reads, which it never does
+	defer remote.Close()
+	st := transport.NewStreamTransport(local, transport.StreamOpts{})
+
+	sent := make(chan error, 1)
+	go func() {
+		sent <- st.SendTx(makeTestTx(t, 1))
+	}()
+	time.Sleep(20 * time.Millisecond) // let SendTx block on the stalled peer
+
+	closed := make(chan struct{})
+	go func() {
+		st.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked on a stalled peer")
+	}
+	if err := <-sent; err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+}
+
+// stalledTransport is an amp.Transport whose peer never reads, so SendTx blocks until it is closed.
+type stalledTransport struct {
+	closed chan struct{}
+	once   sync.Once
+}
+
+func (st *stalledTransport) Label() string {
+	return "stalled"
+}
+
+func (st *stalledTransport) SendTx(tx *amp.TxMsg) error {
+	<-st.closed
+	tx.ReleaseRef()
+	return amp.ErrStreamClosed
+}
+
+func (st *stalledTransport) RecvTx() (*amp.TxMsg, error) {
+	<-st.closed
+	return nil, amp.ErrStreamClosed
+}
+
+func (st *stalledTransport) Close() error {
+	st.once.Do(func() { close(st.closed) })
+	return nil
+}
+
+func TestBatchingTransportCloseStalled(t *testing.T) {
+	bt := transport.NewBatchingTransport(&stalledTransport{closed: make(chan struct{})}, transport.BatchOpts{
+		MaxQueued: 1,
+		IdleDelay: time.Millisecond,
+	})
+
+	// fill the queue so that SendTx blocks
+	sent := make(chan error, 1)
+	go func() {
+		var err error
+		for err == nil {
+			err = bt.SendTx(makeTestTx(t, 1))
+		}
+		sent <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	closed := make(chan struct{})
+	go func() {
+		bt.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close blocked on a stalled transport")
+	}
+	if err := <-sent; err != amp.ErrStreamClosed {
+		t.Fatalf("expected ErrStreamClosed, got %v", err)
+	}
+}
+
+// recvErrHost is an amp.Host whose sessions report the error that ends their receive loop.
+type recvErrHost struct {
+	*echoHost
+	errs chan error
+}
+
+func (host *recvErrHost) StartNewSession(parent amp.HostService, via amp.Transport) (amp.Session, error) {
+	_, err := parent.Go(via.Label(), func(ctx task.Context) {
+		defer via.Close()
+		for {
+			tx, err := via.RecvTx()
+			if err != nil {
+				host.errs <- err
+				return
+			}
+			tx.ReleaseRef()
+		}
+	})
+	return nil, err
+}
+
+// wsFrame returns a masked client frame (using a zero masking key) declaring the given payload length.
+func wsFrame(fin bool, opcode byte, declaredLen uint64, payload []byte) []byte {
+	frame := []byte{opcode, 0x80 | 127}
+	if fin {
+		frame[0] |= 0x80
+	}
+	frame = binary.BigEndian.AppendUint64(frame, declaredLen)
+	frame = append(frame, 0, 0, 0, 0)
+	return append(frame, payload...)
+}
+
+func TestWebSocketHostileFrames(t *testing.T) {
+	host := &recvErrHost{
+		echoHost: startEchoHost(t),
+		errs:     make(chan error, 1),
+	}
+	service := transport.NewWebSocketService(transport.WebSocketOpts{})
+	if err := service.StartService(host); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(service.Handler())
+	defer server.Close()
+
+	tests := map[string][][]byte{
+		"length overflow": {
+			wsFrame(false, 0x2, 4, []byte{1, 2, 3, 4}),
+			wsFrame(true, 0x0, ^uint64(0)-3, nil),
+		},
+		"length msb set": {
+			wsFrame(true, 0x2, 1<<63, nil),
+		},
+		"data frame within message": {
+			wsFrame(false, 0x2, 4, []byte{1, 2, 3, 4}),
+			wsFrame(true, 0x2, 4, []byte{1, 2, 3, 4}),
+		},
+		"continuation without message": {
+			wsFrame(true, 0x0, 4, []byte{1, 2, 3, 4}),
+		},
+	}
+	for name, frames := range tests {
+		conn, err := net.Dial("tcp", server.Listener.Addr().String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(conn, "GET %s HTTP/1.1\r\nHost: test\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n"+
+			"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\nSec-WebSocket-Version: 13\r\n\r\n", transport.DefaultWebSocketPath)
+		resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil || resp.StatusCode != http.StatusSwitchingProtocols {
+			t.Fatalf("%s: upgrade failed: %v %v", name, resp, err)
+		}
+		for _, frame := range frames {
+			conn.Write(frame)
+		}
+		select {
+		case err = <-host.errs:
+			if amp.GetErrCode(err) != amp.ErrCode_MalformedTx {
+				t.Fatalf("%s: expected ErrCode_MalformedTx, got %v", name, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("%s: timed out", name)
+		}
+		conn.Close()
+	}
+	service.Close()
+	<-service.Done()
+}
@@ -0,0 +1,135 @@
+// Command amphost is a stand-alone "headless" amp.Host that serves the apps registered in registry.Global().
+//
+// To serve additional apps, blank-import their packages below so that they register themselves on init:
+//
+//	import _ "example.com/myapp"
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/art-media-platform/amp-sdk-go/amp"
+	"github.com/art-media-platform/amp-sdk-go/amp/host"
+	"github.com/art-media-platform/amp-sdk-go/amp/registry"
+	"github.com/art-media-platform/amp-sdk-go/amp/transport"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/log"
+	"github.com/art-media-platform/amp-sdk-go/stdlib/metrics"
+)
+
+func main() {
+	var (
+		dataPath      = flag.String("data", defaultDataPath(), "root directory of each app's local data; if empty, apps have no local data directory")
+		listenAddr    = flag.String("listen", fmt.Sprintf(":%d", amp.Const_DefaultServicePort), "tcp address to accept stream connections on; if empty, no stream listener is started")
+		unixPath      = flag.String("unix", "", "unix socket path to accept stream connections on; if empty, no unix listener is started")
+		wsAddr        = flag.String("ws", "", "address to accept WebSocket connections on (e.g. \":5193\"); if empty, no WebSocket service is started")
+		tlsCert       = flag.String("tls_cert", "", "PEM certificate file; if set, tcp and WebSocket connections use TLS")
+		tlsKey        = flag.String("tls_key", "", "PEM private key file for -tls_cert")
+		tlsSelfSigned = flag.Bool("tls_self_signed", false, "if set and no -tls_cert is given, serve TLS using a generated self-signed certificate")
+		maxPins       = flag.Int("max_pins", 0, "max open pins per session; if 0, unlimited")
+		maxTxRate     = flag.Float64("max_tx_rate", 0, "max TxMsgs received per second per session; if 0, unlimited")
+		maxByteRate   = flag.Int64("max_byte_rate", 0, "max bytes received per second per session; if 0, unlimited")
+		maxCommitSz   = flag.Int64("max_commit_sz", 0, "max DataStore size of a TxMsg received by an app; if 0, unlimited")
+		metricsAddr   = flag.String("metrics", "", "address to serve Prometheus metrics on at /metrics (e.g. \":9193\"); if empty, metrics are not served")
+	)
+	log.InitFlags(flag.CommandLine) // adds -v (verbosity) and other logging flags
+	flag.Set("logtostderr", "true")
+	flag.Parse()
+	log.UseStockFormatter(24, true)
+	defer log.Flush()
+
+	tlsOpts := transport.TLSOpts{
+		CertFile:   *tlsCert,
+		KeyFile:    *tlsKey,
+		SelfSigned: *tlsSelfSigned,
+	}
+
+	h, err := host.Start(host.Opts{
+		Label:    "amphost",
+		Registry: registry.Global(),
+		DataPath: *dataPath,
+		SessionLimits: host.Limits{
+			MaxPins:        *maxPins,
+			MaxTxPerSec:    *maxTxRate,
+			MaxBytesPerSec: *maxByteRate,
+		},
+		DefaultAppLimits: host.Limits{
+			MaxCommitSz: *maxCommitSz,
+		},
+	})
+	if err != nil {
+		log.Fatalf("failed to start host: %v", err)
+	}
+
+	var services []amp.HostService
+	if *listenAddr != "" {
+		services = append(services, transport.NewListener(transport.ListenerOpts{
+			Address: *listenAddr,
+			TLS:     tlsOpts,
+		}))
+	}
+	if *unixPath != "" {
+		services = append(services, transport.NewListener(transport.ListenerOpts{
+			Network: "unix",
+			Address: *unixPath,
+		}))
+	}
+	if *wsAddr != "" {
+		services = append(services, transport.NewWebSocketService(transport.WebSocketOpts{
+			Address: *wsAddr,
+			TLS:     tlsOpts,
+		}))
+	}
+	if len(services) == 0 {
+		h.Close()
+		log.Fatalf("no transports configured: use -listen, -unix, or -ws")
+	}
+	for _, svc := range services {
+		if err := h.StartService(svc); err != nil {
+			h.Close()
+			log.Fatalf("failed to start service: %v", err)
+		}
+	}
+
+	if *metricsAddr != "" {
+		lis, err := net.Listen("tcp", *metricsAddr)
+		if err != nil {
+			h.Close()
+			log.Fatalf("failed to serve metrics: %v", err)
+		}
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		go http.Serve(lis, mux)
+	}
+
+	first, repeated := log.AwaitInterrupt()
+	select {
+	case <-first:
+	case <-h.Done():
+		return
+	}
+
+	// Stop accepting connections and let open sessions finish, unless interrupted again.
+	go func() {
+		<-repeated
+		h.Close()
+	}()
+	for _, svc := range services {
+		svc.GracefulStop()
+	}
+	h.Close()
+	<-h.Done()
+}
+
+// defaultDataPath returns the default root directory of app data (or "" if the user has no home directory).
+func defaultDataPath() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".amphost")
+}
@@ -0,0 +1,257 @@
+// Package metrics offers counters and gauges served in the Prometheus text exposition format, using only the standard library.
+//
+// https://prometheus.io/docs/instrumenting/exposition_formats/
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Default is the Registry that packages register their metrics with and that Handler serves.
+var Default = NewRegistry()
+
+// Handler returns an http.Handler serving the metrics in Default.
+func Handler() http.Handler {
+	return Default
+}
+
+// Counter is a value that only increases -- concurrency safe.
+type Counter struct {
+	val atomic.Uint64
+}
+
+func (c *Counter) Inc()          { c.val.Add(1) }
+func (c *Counter) Add(n uint64)  { c.val.Add(n) }
+func (c *Counter) Value() uint64 { return c.val.Load() }
+
+// Gauge is a value that can go up and down -- concurrency safe.
+type Gauge struct {
+	val atomic.Int64
+}
+
+func (g *Gauge) Inc()         { g.val.Add(1) }
+func (g *Gauge) Dec()         { g.val.Add(-1) }
+func (g *Gauge) Add(n int64)  { g.val.Add(n) }
+func (g *Gauge) Set(n int64)  { g.val.Store(n) }
+func (g *Gauge) Value() int64 { return g.val.Load() }
+
+// CounterVec is a set of Counters sharing a name and distinguished by their label values.
+type CounterVec struct {
+	fam *family
+}
+
+// With returns the Counter having the given label values (in the order the labels were declared), creating it if needed.
+func (v *CounterVec) With(labelValues ...string) *Counter {
+	return v.fam.with(labelValues).counter
+}
+
+// GaugeVec is a set of Gauges sharing a name and distinguished by their label values.
+type GaugeVec struct {
+	fam *family
+}
+
+// With returns the Gauge having the given label values (in the order the labels were declared), creating it if needed.
+func (v *GaugeVec) With(labelValues ...string) *Gauge {
+	return v.fam.with(labelValues).gauge
+}
+
+// Registry is a set of named metrics, written in the Prometheus text format -- concurrency safe.
+type Registry struct {
+	mu   sync.Mutex
+	fams map[string]*family
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		fams: make(map[string]*family),
+	}
+}
+
+// Counter registers and returns a new Counter.
+func (reg *Registry) Counter(name, help string) *Counter {
+	return reg.add(name, help, "counter", nil, nil).with(nil).counter
+}
+
+// Gauge registers and returns a new Gauge.
+func (reg *Registry) Gauge(name, help string) *Gauge {
+	return reg.add(name, help, "gauge", nil, nil).with(nil).gauge
+}
+
+// GaugeFunc registers a gauge whose value is returned by the given function each time the metrics are written.
+func (reg *Registry) GaugeFunc(name, help string, fn func() float64) {
+	reg.add(name, help, "gauge", nil, fn)
+}
+
+// CounterFunc registers a counter whose value is returned by the given function each time the metrics are written.
+func (reg *Registry) CounterFunc(name, help string, fn func() float64) {
+	reg.add(name, help, "counter", nil, fn)
+}
+
+// CounterVec registers and returns a new CounterVec having the given label names.
+func (reg *Registry) CounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{reg.add(name, help, "counter", labels, nil)}
+}
+
+// GaugeVec registers and returns a new GaugeVec having the given label names.
+func (reg *Registry) GaugeVec(name, help string, labels ...string) *GaugeVec {
+	return &GaugeVec{reg.add(name, help, "gauge", labels, nil)}
+}
+
+// add registers a new metric family, panicking if the name is already registered (a programming error).
+func (reg *Registry) add(name, help, kind string, labels []string, fn func() float64) *family {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if reg.fams[name] != nil {
+		panic("metrics: " + name + " is already registered")
+	}
+	fam := &family{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		fn:     fn,
+		series: make(map[string]*series),
+	}
+	reg.fams[name] = fam
+	return fam
+}
+
+// WriteText writes each metric in the Prometheus text exposition format (version 0.0.4), sorted by name.
+func (reg *Registry) WriteText(w io.Writer) error {
+	reg.mu.Lock()
+	fams := make([]*family, 0, len(reg.fams))
+	for _, fam := range reg.fams {
+		fams = append(fams, fam)
+	}
+	reg.mu.Unlock()
+
+	sort.Slice(fams, func(i, j int) bool {
+		return fams[i].name < fams[j].name
+	})
+
+	bw := bufio.NewWriter(w)
+	for _, fam := range fams {
+		fam.writeText(bw)
+	}
+	return bw.Flush()
+}
+
+// Implements http.Handler
+func (reg *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	reg.WriteText(w)
+}
+
+// family is a named metric and its series (one for each distinct set of label values).
+type family struct {
+	name   string
+	help   string
+	kind   string             // "counter" or "gauge"
+	labels []string           // label names
+	fn     func() float64     // if set, the value of this (unlabelled) metric
+	mu     sync.Mutex         // guards series
+	series map[string]*series // series by their rendered labels
+}
+
+type series struct {
+	labels  string // rendered labels, e.g. `{app="echo"}`
+	counter *Counter
+	gauge   *Gauge
+}
+
+// with returns the series having the given label values, creating it if needed.
+func (fam *family) with(labelValues []string) *series {
+	if len(labelValues) != len(fam.labels) {
+		panic("metrics: " + fam.name + " expects labels " + strings.Join(fam.labels, ", "))
+	}
+
+	var buf strings.Builder
+	for i, label := range fam.labels {
+		if i == 0 {
+			buf.WriteByte('{')
+		} else {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(label)
+		buf.WriteString(`="`)
+		labelEscaper.WriteString(&buf, labelValues[i])
+		buf.WriteByte('"')
+	}
+	if buf.Len() > 0 {
+		buf.WriteByte('}')
+	}
+	labels := buf.String()
+
+	fam.mu.Lock()
+	defer fam.mu.Unlock()
+
+	s := fam.series[labels]
+	if s == nil {
+		s = &series{
+			labels: labels,
+		}
+		if fam.kind == "counter" {
+			s.counter = &Counter{}
+		} else {
+			s.gauge = &Gauge{}
+		}
+		fam.series[labels] = s
+	}
+	return s
+}
+
+func (fam *family) writeText(w *bufio.Writer) {
+	w.WriteString("# HELP ")
+	w.WriteString(fam.name)
+	w.WriteByte(' ')
+	helpEscaper.WriteString(w, fam.help)
+	w.WriteString("\n# TYPE ")
+	w.WriteString(fam.name)
+	w.WriteByte(' ')
+	w.WriteString(fam.kind)
+	w.WriteByte('\n')
+
+	if fam.fn != nil {
+		w.WriteString(fam.name)
+		w.WriteByte(' ')
+		w.WriteString(strconv.FormatFloat(fam.fn(), 'g', -1, 64))
+		w.WriteByte('\n')
+		return
+	}
+
+	fam.mu.Lock()
+	all := make([]*series, 0, len(fam.series))
+	for _, s := range fam.series {
+		all = append(all, s)
+	}
+	fam.mu.Unlock()
+
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].labels < all[j].labels
+	})
+	for _, s := range all {
+		w.WriteString(fam.name)
+		w.WriteString(s.labels)
+		w.WriteByte(' ')
+		if s.counter != nil {
+			w.WriteString(strconv.FormatUint(s.counter.Value(), 10))
+		} else {
+			w.WriteString(strconv.FormatInt(s.gauge.Value(), 10))
+		}
+		w.WriteByte('\n')
+	}
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
@@ -0,0 +1,64 @@
+package metrics_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/art-media-platform/amp-sdk-go/stdlib/metrics"
+)
+
+func TestWriteText(t *testing.T) {
+	reg := metrics.NewRegistry()
+	reqs := reg.Counter("test_requests_total", "Requests served.")
+	conns := reg.Gauge("test_conns", "Open connections.")
+	byApp := reg.GaugeVec("test_pins", "Open pins,\nby app.", "app", "kind")
+	reg.GaugeFunc("test_ratio", "A computed value.", func() float64 { return 0.25 })
+
+	reqs.Add(3)
+	reqs.Inc()
+	conns.Inc()
+	conns.Inc()
+	conns.Dec()
+	byApp.With("b", "x").Add(2)
+	byApp.With(`a"\`, "y").Inc()
+	byApp.With("b", "x").Inc()
+
+	const expected = `# HELP test_conns Open connections.
+# TYPE test_conns gauge
+test_conns 1
+# HELP test_pins Open pins,\nby app.
+# TYPE test_pins gauge
+test_pins{app="a\"\\",kind="y"} 1
+test_pins{app="b",kind="x"} 3
+# HELP test_ratio A computed value.
+# TYPE test_ratio gauge
+test_ratio 0.25
+# HELP test_requests_total Requests served.
+# TYPE test_requests_total counter
+test_requests_total 4
+`
+	var buf strings.Builder
+	if err := reg.WriteText(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != expected {
+		t.Fatalf("unexpected output:\n%s", buf.String())
+	}
+
+	rec := httptest.NewRecorder()
+	reg.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("unexpected Content-Type %q", ct)
+	}
+	if rec.Body.String() != expected {
+		t.Fatalf("unexpected body:\n%s", rec.Body.String())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic registering a duplicate name")
+		}
+	}()
+	reg.Counter("test_conns", "")
+}
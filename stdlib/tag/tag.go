@@ -4,8 +4,10 @@ import (
 	"crypto/sha1"
 	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"regexp"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/art-media-platform/amp-sdk-go/stdlib/bufs"
@@ -193,9 +195,16 @@ func FromTime(t time.Time, addEntropy bool) ID {
 	}
 
 	if addEntropy {
-		gTagSeed = 377377733*ns_f64 ^ gTagSeed
-		tag[1] ^= gTagSeed & EntropyMask
-		tag[2] ^= gTagSeed * ns_f64
+		var seed uint64
+		for {
+			prev := gTagSeed.Load()
+			seed = 377377733*ns_f64 ^ prev
+			if gTagSeed.CompareAndSwap(prev, seed) {
+				break
+			}
+		}
+		tag[1] ^= seed & EntropyMask
+		tag[2] ^= seed * ns_f64
 	}
 
 	return tag
@@ -340,6 +349,26 @@ func (tag ID) Base32() string {
 	return "0"
 }
 
+// ParseBase32 is the inverse of ID.Base32().
+func ParseBase32(str string) (ID, error) {
+	const encodedLen = 40 // Base32 length of 25 bytes
+	if len(str) == 0 || len(str) > encodedLen {
+		return ID{}, ErrBadBase32
+	}
+	var padded [encodedLen]byte
+	pad := encodedLen - len(str)
+	for i := 0; i < pad; i++ {
+		padded[i] = '0'
+	}
+	copy(padded[pad:], str)
+
+	var buf [25]byte
+	if _, err := bufs.Base32Encoding.Decode(buf[:], padded[:]); err != nil || buf[0] != 0 {
+		return ID{}, ErrBadBase32
+	}
+	return FromBytes(buf[1:])
+}
+
 func (tag ID) Base16() string {
 	buf := make([]byte, 0, 48)
 	tagBytes := tag.AppendTo(buf)
@@ -400,10 +429,17 @@ func IntsToID(x0 int64, x1, x2 uint64) ID {
 
 type Key [24]byte
 
-var gTagSeed = uint64(0x3773000000003773)
+// gTagSeed is the entropy mixed into each tag.Now() -- updated atomically so that tag.Now() is safe to call from any goroutine.
+var gTagSeed atomic.Uint64
+
+func init() {
+	gTagSeed.Store(0x3773000000003773)
+}
 
 var (
 	Nil = ID{}
+
+	ErrBadBase32 = errors.New("tag: invalid Base32 tag.ID")
 )
 
 func FromBytes(in []byte) (tag ID, err error) {
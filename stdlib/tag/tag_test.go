@@ -39,6 +39,14 @@ func TestTag(t *testing.T) {
 	if tid.Base32() != "vrfxvrfxvrfxvj4e2qg2ectrrh" {
 		t.Errorf("tag.ID.Base32() failed: %v", tid.Base32())
 	}
+	for _, id := range []tag.ID{tid, spec.ID, {}, {0, 0, 1}, {^uint64(0), ^uint64(0), ^uint64(0)}} {
+		if parsed, err := tag.ParseBase32(id.Base32()); err != nil || parsed != id {
+			t.Errorf("tag.ParseBase32() failed: %v", id)
+		}
+	}
+	if _, err := tag.ParseBase32("not base32!"); err == nil {
+		t.Errorf("tag.ParseBase32() accepted bad input")
+	}
 	if b16 := tid.Base16(); b16 != "37777777777777777123456789abcdef0" {
 		t.Errorf("tag.ID.Base16() failed: %v", b16)
 	}
@@ -3,6 +3,7 @@ package task
 
 import (
 	"context"
+	"sync/atomic"
 	"time"
 
 	"github.com/art-media-platform/amp-sdk-go/stdlib/log"
@@ -14,6 +15,11 @@ func Start(task *Task) (Context, error) {
 	return Context((*ctx)(nil)).StartChild(task)
 }
 
+// Counts returns the number of Contexts started since the process began and the number of those not yet closed.
+func Counts() (started, open int64) {
+	return atomic.LoadInt64(&gInstanceCount), atomic.LoadInt64(&gOpenCount)
+}
+
 // Go is a convenience function that starts a new Context that runs the given function -- like starting a goroutine.
 //
 // If parent == null, then the new Context will have no parent.
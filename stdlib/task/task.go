@@ -36,7 +36,10 @@ var (
 	ErrClosed         = errors.New("closed")
 )
 
-var gInstanceCount = int64(0)
+var (
+	gInstanceCount = int64(0) // Contexts started, also used to assign Info.TID
+	gOpenCount     = int64(0) // Contexts started and not yet closed
+)
 
 func (p *ctx) Close() error {
 	first := atomic.CompareAndSwapInt32(&p.state, Running, Closing)
@@ -218,7 +221,7 @@ func (p *ctx) StartChild(task *Task) (Context, error) {
 	if p != nil {
 		var err error
 		p.subsMu.Lock()
-		if p.state == Running {
+		if atomic.LoadInt32(&p.state) == Running {
 			p.busy.Add(1)
 			p.idle = false
 			p.subs = append(p.subs, child)
@@ -232,6 +235,8 @@ func (p *ctx) StartChild(task *Task) (Context, error) {
 		}
 	}
 
+	atomic.AddInt64(&gOpenCount, 1)
+
 	go func() {
 
 		// If there is a parent, wait until child.Close() *or* p.Close()
@@ -286,11 +291,12 @@ func (p *ctx) StartChild(task *Task) (Context, error) {
 		}
 
 		// Move to Closed state now that all all that remains is the OnClosed callback and release of the chClosed chan.
-		child.state = Closed
+		atomic.StoreInt32(&child.state, Closed)
 		if child.task.OnClosed != nil {
 			child.task.OnClosed()
 		}
 		close(child.chClosed)
+		atomic.AddInt64(&gOpenCount, -1)
 
 		// With the child now fully closed, the parent is no longer waiting on this child
 		if p != nil {